@@ -18,12 +18,15 @@ package v1alpha1
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+var imageDigestRegex = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
@@ -37,9 +40,30 @@ const (
 	DriverStateNotReady DriverState = "notReady"
 )
 
+// ManagementState is how the operator manages the resources of the driver
+// +kubebuilder:validation:Enum=Managed;Unmanaged;Removed
+type ManagementState string
+
+const (
+	// ManagementStateManaged reconciles the driver resources to the spec
+	ManagementStateManaged ManagementState = "Managed"
+	// ManagementStateUnmanaged leaves the existing driver resources untouched but still reports their status
+	ManagementStateUnmanaged ManagementState = "Unmanaged"
+	// ManagementStateRemoved deletes the driver resources
+	ManagementStateRemoved ManagementState = "Removed"
+)
+
 // RBLNDriverSpec defines the desired state of RBLNDriver
 // +kubebuilder:object:generate=true
+// +kubebuilder:validation:XValidation:rule="!has(self.digests) || !has(self.precompiled) || self.precompiled",message="digests pin precompiled driver images and cannot be used with precompiled: false"
+// +kubebuilder:validation:XValidation:rule="!has(self.precompiled) || self.precompiled || has(self.sourceBuildContract)",message="precompiled: false requires the sourceBuildContract the driver image implements"
 type RBLNDriverSpec struct {
+	// ManagementState is Managed to reconcile the driver, Unmanaged to leave its existing
+	// DaemonSets untouched, e.g. while hot-fixing one, or Removed to delete them
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Managed
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
 	// Registry override for the Rebellions driver container image
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=repo.rebellions.ai
@@ -54,6 +78,25 @@ type RBLNDriverSpec struct {
 	// +kubebuilder:validation:Optional
 	Version string `json:"version,omitempty"`
 
+	// Precompiled selects driver images built for each kernel, tagged <version>-<kernel>-<os>.
+	// When disabled, the driver container builds the module against the kernel headers of the node
+	// from the <version>-<os> image and caches it on the host per kernel version, which requires
+	// the sourceBuildContract. With the image preflight enabled and the sourceBuildContract set,
+	// pools without a precompiled image fall back to the source build.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=true
+	Precompiled *bool `json:"precompiled,omitempty"`
+
+	// SourceBuildContract is the version of the source build contract the driver image implements.
+	// Images without it only load precompiled modules, so source builds, including the image
+	// preflight fallback and the Driver Toolkit, are only configured once it is set. Contract v1:
+	// the <version>-<os> image builds the module when RBLN_DRIVER_BUILD=source, for the kernel in
+	// KERNEL_VERSION, against the headers mounted at /host/lib/modules and /host/usr/src, and reuses
+	// the modules cached in RBLN_MODULE_CACHE_DIR.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=v1
+	SourceBuildContract string `json:"sourceBuildContract,omitempty"`
+
 	// ImagePullPolicy specifies the image pull policy for the driver pod
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=IfNotPresent
@@ -109,8 +152,80 @@ type RBLNDriverSpec struct {
 	// Env specifies environment variables for the driver container
 	// +kubebuilder:validation:Optional
 	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// SecureBoot provides the key used to sign the kernel module on nodes with UEFI Secure Boot enabled
+	// +kubebuilder:validation:Optional
+	SecureBoot *DriverSecureBootSpec `json:"secureBoot,omitempty"`
+
+	// Digests pins the precompiled driver image of each node pool by digest instead of by tag.
+	// It requires precompiled driver images.
+	// +kubebuilder:validation:Optional
+	Digests *DriverDigestsSpec `json:"digests,omitempty"`
+
+	// ImagePreflight checks that the precompiled driver image of every node pool exists in the
+	// registry before deploying the pool. Pools without an image fall back to the source build,
+	// or are skipped and reported when no source image exists either.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=true
+	ImagePreflight *bool `json:"imagePreflight,omitempty"`
+
+	// DriverToolkit builds the module of RHCOS nodes on OpenShift from the source of the
+	// <version>-rhcos<ocp version> driver image against the kernel headers of the Driver Toolkit
+	// image of the release. RHCOS pools are keyed by RHCOS version, and the pool of a pending
+	// cluster update is created before nodes reboot into it. It requires the sourceBuildContract;
+	// without it RHCOS nodes use precompiled images like other nodes.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=true
+	DriverToolkit *bool `json:"driverToolkit,omitempty"`
+}
+
+// DriverDigestsSpec maps node pools to the digests of their precompiled driver images.
+// Pools are named <os><version>-<kernel>, e.g. ubuntu22.04-5.15.0-100-generic, as in the
+// nodepool label of the driver DaemonSets. A pool without a digest is not deployed.
+type DriverDigestsSpec struct {
+	// Pools maps a node pool name to an image digest, e.g. sha256:<hex>
+	// +kubebuilder:validation:Optional
+	Pools map[string]string `json:"pools,omitempty"`
+
+	// ConfigMap is the name of a ConfigMap in the operator namespace mapping node pool names to
+	// image digests. Pools listed above take precedence.
+	// +kubebuilder:validation:Optional
+	ConfigMap string `json:"configMap,omitempty"`
 }
 
+// DriverModuleSigningContractV1 is the first version of the driver image module signing contract.
+const DriverModuleSigningContractV1 = "v1"
+
+// DriverSecureBootSpec references the module signing key. The key and certificate are mounted
+// only into the driver container, which signs the module before loading it.
+// The certificate must be enrolled on the nodes, e.g. as a Machine Owner Key.
+type DriverSecureBootSpec struct {
+	// ComponentContract is the version of the module signing contract the driver image implements.
+	// Images that do not implement it ignore the key and load the module unsigned.
+	// Contract v1: the driver container signs the module with the PEM private key at the path in
+	// RBLN_MODULE_SIGNING_KEY and the DER certificate at the path in RBLN_MODULE_SIGNING_CERT
+	// before loading it.
+	// +kubebuilder:validation:Enum=v1
+	ComponentContract string `json:"componentContract"`
+
+	// SigningKeySecret is the name of a Secret in the operator namespace holding the signing key and certificate
+	// +kubebuilder:validation:MinLength=1
+	SigningKeySecret string `json:"signingKeySecret"`
+
+	// PrivateKey is the Secret key holding the PEM encoded private key
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=signing_key.priv
+	PrivateKey string `json:"privateKey,omitempty"`
+
+	// Certificate is the Secret key holding the DER encoded X.509 certificate
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=signing_key.x509
+	Certificate string `json:"certificate,omitempty"`
+}
+
+// DriverSourceBuildContractV1 is the first version of the driver image source build contract.
+const DriverSourceBuildContractV1 = "v1"
+
 // RBLNDriverStatus defines the observed state of RBLNDriver
 type RBLNDriverStatus struct {
 	// +kubebuilder:validation:Enum=ready;notReady
@@ -119,6 +234,19 @@ type RBLNDriverStatus struct {
 	State DriverState `json:"state,omitempty"`
 	// Conditions is a list of conditions representing the RBLNDriver's current state
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Images is the effective image of every driver container, after the image mirrors of the RBLNClusterPolicy are applied
+	// +optional
+	Images []ContainerImage `json:"images,omitempty"`
+}
+
+// ContainerImage is the image a container of a managed workload runs
+type ContainerImage struct {
+	// Workload is the kind, namespace and name of the workload, e.g. DaemonSet/rbln-system/rbln-driver-ubuntu22.04-5.15.0
+	Workload string `json:"workload"`
+	// Container name
+	Container string `json:"container"`
+	// Image reference
+	Image string `json:"image"`
 }
 
 // +kubebuilder:object:root=true
@@ -207,6 +335,68 @@ func (d *RBLNDriverSpec) GetPrecompiledImagePath(osVersion string, kernelVersion
 	return imagePath, nil
 }
 
+// GetSourceImagePath returns the driver image that builds the module on the node.
+func (d *RBLNDriverSpec) GetSourceImagePath(osVersion string) (string, error) {
+	if osVersion == "" {
+		return "", fmt.Errorf("osVersion is required")
+	}
+
+	registry := strings.TrimSuffix(strings.TrimSpace(d.Registry), "/")
+	image := strings.TrimPrefix(strings.TrimSpace(d.Image), "/")
+	version := strings.TrimSpace(d.Version)
+	if version == "" {
+		return "", fmt.Errorf("driver version is required")
+	}
+	if strings.Contains(image, "@") || strings.Contains(version, "sha256:") {
+		return "", fmt.Errorf("specifying image digest in image or version is not supported, use digests instead")
+	}
+
+	return fmt.Sprintf("%s/%s:%s-%s", registry, image, version, osVersion), nil
+}
+
+// GetPrecompiledImageDigestPath returns the driver image pinned by digest.
+func (d *RBLNDriverSpec) GetPrecompiledImageDigestPath(digest string) (string, error) {
+	registry := strings.TrimSuffix(strings.TrimSpace(d.Registry), "/")
+	image := strings.TrimPrefix(strings.TrimSpace(d.Image), "/")
+	digest = strings.TrimSpace(digest)
+	if !imageDigestRegex.MatchString(digest) {
+		return "", fmt.Errorf("invalid image digest %q, expected sha256:<64 hex characters>", digest)
+	}
+	if strings.Contains(image, "@") {
+		return "", fmt.Errorf("image %q already contains a digest", image)
+	}
+	return fmt.Sprintf("%s/%s@%s", registry, image, digest), nil
+}
+
+// IsPrecompiledEnabled returns true if precompiled driver images are used
+func (d *RBLNDriverSpec) IsPrecompiledEnabled() bool {
+	return d.Precompiled == nil || *d.Precompiled
+}
+
+// IsSourceBuildSupported returns true if the driver image implements a source build contract
+func (d *RBLNDriverSpec) IsSourceBuildSupported() bool {
+	return d.SourceBuildContract != ""
+}
+
+// IsDriverToolkitEnabled returns true if RHCOS nodes build the module with the OpenShift Driver Toolkit,
+// which requires a source build contract
+func (d *RBLNDriverSpec) IsDriverToolkitEnabled() bool {
+	return (d.DriverToolkit == nil || *d.DriverToolkit) && d.IsSourceBuildSupported()
+}
+
+// IsImagePreflightEnabled returns true if driver images are checked against the registry before deployment
+func (d *RBLNDriverSpec) IsImagePreflightEnabled() bool {
+	return d.ImagePreflight == nil || *d.ImagePreflight
+}
+
+// GetManagementState returns the management state of the driver, Managed when unset
+func (d *RBLNDriverSpec) GetManagementState() ManagementState {
+	if d.ManagementState == "" {
+		return ManagementStateManaged
+	}
+	return d.ManagementState
+}
+
 // GetNodeSelector returns node selector labels for Rebellions driver installation.
 func (d *RBLNDriver) GetNodeSelector() map[string]string {
 	if d == nil || len(d.Spec.NodeSelector) == 0 {
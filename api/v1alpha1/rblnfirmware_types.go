@@ -0,0 +1,258 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FirmwareState represents the overall firmware compliance of the selected nodes.
+type FirmwareState string
+
+const (
+	// FirmwareStateCompliant indicates every card runs its desired firmware version.
+	FirmwareStateCompliant FirmwareState = "compliant"
+	// FirmwareStateNonCompliant indicates at least one card runs another firmware version.
+	FirmwareStateNonCompliant FirmwareState = "nonCompliant"
+	// FirmwareStateUpdating indicates a node is being updated.
+	FirmwareStateUpdating FirmwareState = "updating"
+	// FirmwareStateFailed indicates the rolling update stopped on a failed node.
+	FirmwareStateFailed FirmwareState = "failed"
+)
+
+// FirmwareUpdatePhase is the step of the update of a node.
+type FirmwareUpdatePhase string
+
+const (
+	// FirmwareUpdateDraining indicates the node is cordoned and its pods are evicted.
+	FirmwareUpdateDraining FirmwareUpdatePhase = "Draining"
+	// FirmwareUpdateFlashing indicates the flash job runs on the node.
+	FirmwareUpdateFlashing FirmwareUpdatePhase = "Flashing"
+	// FirmwareUpdateVerifying indicates the operator waits for the cards of the node to report the desired version after the reset.
+	FirmwareUpdateVerifying FirmwareUpdatePhase = "Verifying"
+	// FirmwareUpdateFailed indicates the update of the node failed; the node stays cordoned.
+	FirmwareUpdateFailed FirmwareUpdatePhase = "Failed"
+)
+
+// RBLNFirmwareSpec defines the desired state of RBLNFirmware
+// +kubebuilder:object:generate=true
+type RBLNFirmwareSpec struct {
+	// Cards lists the firmware version per product card that the flasher image ships.
+	// A node is compliant once every managed card reports this version as its running
+	// firmware. Nodes with a managed card that reports no version are not updated automatically.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=product
+	Cards []FirmwareCardSpec `json:"cards"`
+
+	// Registry of the firmware flasher image
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=repo.rebellions.ai
+	Registry string `json:"registry,omitempty"`
+
+	// Image of the firmware flasher. Its entrypoint flashes the cards of the node with the
+	// firmware the image ships and exits non-zero on failure. The NPUs are reset afterwards.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=rebellions/rbln-firmware
+	Image string `json:"image,omitempty"`
+
+	// Version of the firmware flasher image
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+
+	// ImagePullPolicy specifies the image pull policy for the flash job
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ImagePullSecrets specifies the image pull secrets for the flash job
+	// +kubebuilder:validation:Optional
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+
+	// NodeSelector specifies the nodes whose firmware is managed
+	// +kubebuilder:validation:Optional
+	// +mapType=atomic
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// InventoryIntervalSeconds between two firmware inventories on a node
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=30
+	InventoryIntervalSeconds int32 `json:"inventoryIntervalSeconds,omitempty"`
+
+	// Update configures the rolling firmware update of non-compliant nodes
+	// +kubebuilder:validation:Optional
+	Update FirmwareUpdateSpec `json:"update,omitempty"`
+
+	// Env specifies environment variables for the flash job
+	// +kubebuilder:validation:Optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+}
+
+// FirmwareCardSpec is the desired firmware version of a product card
+type FirmwareCardSpec struct {
+	// Product card name
+	// +kubebuilder:validation:Enum=RBLN-CA12;RBLN-CA22;RBLN-CA25;RBLN-CR03
+	Product string `json:"product"`
+
+	// Version of the firmware
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+}
+
+// FirmwareUpdateSpec describes the rolling update of non-compliant nodes.
+// Nodes are updated one at a time: the node is drained, flashed by a privileged job without
+// API access, and verified against the running versions its cards report before it is
+// uncordoned and the next node starts. Nodes whose cards report no version are skipped.
+type FirmwareUpdateSpec struct {
+	// Enabled indicates if non-compliant nodes are updated automatically
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// DrainTimeoutSeconds to wait for the pods of a node to be evicted
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=600
+	// +kubebuilder:validation:Minimum=0
+	DrainTimeoutSeconds int32 `json:"drainTimeoutSeconds,omitempty"`
+
+	// FlashTimeoutSeconds to wait for the flash job to complete
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=1800
+	// +kubebuilder:validation:Minimum=60
+	FlashTimeoutSeconds int32 `json:"flashTimeoutSeconds,omitempty"`
+
+	// VerifyTimeoutSeconds to wait for the cards of the node to report the desired version after flashing
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=900
+	// +kubebuilder:validation:Minimum=60
+	VerifyTimeoutSeconds int32 `json:"verifyTimeoutSeconds,omitempty"`
+}
+
+// RBLNFirmwareStatus defines the observed state of RBLNFirmware
+type RBLNFirmwareStatus struct {
+	// +kubebuilder:validation:Enum=compliant;nonCompliant;updating;failed
+	// +optional
+	// State indicates the firmware compliance of the selected nodes
+	State FirmwareState `json:"state,omitempty"`
+	// Conditions is a list of conditions representing the RBLNFirmware's current state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Nodes is the firmware inventory of the selected nodes
+	// +optional
+	Nodes []NodeFirmwareStatus `json:"nodes,omitempty"`
+	// Update is the node currently being updated
+	// +optional
+	Update *FirmwareUpdateStatus `json:"update,omitempty"`
+}
+
+// NodeFirmwareStatus is the firmware inventory of a node
+type NodeFirmwareStatus struct {
+	// NodeName of the node
+	NodeName string `json:"nodeName"`
+	// Compliant is true if every managed card runs its desired version
+	Compliant bool `json:"compliant"`
+	// VersionUnknown is true if a managed card does not report its running firmware version.
+	// The node is not compliant and is not updated automatically.
+	// +optional
+	VersionUnknown bool `json:"versionUnknown,omitempty"`
+	// Cards found on the node
+	// +optional
+	Cards []CardFirmwareStatus `json:"cards,omitempty"`
+	// LastInventoryTime is when the node reported its inventory
+	// +optional
+	LastInventoryTime *metav1.Time `json:"lastInventoryTime,omitempty"`
+}
+
+// CardFirmwareStatus is the firmware of a card
+type CardFirmwareStatus struct {
+	// Address is the PCI address of the card
+	Address string `json:"address"`
+	// Product card name
+	Product string `json:"product,omitempty"`
+	// Version of the running firmware reported by the card, Unknown if the card does not report it
+	Version string `json:"version,omitempty"`
+	// DesiredVersion of the firmware, empty if the product is not managed
+	// +optional
+	DesiredVersion string `json:"desiredVersion,omitempty"`
+}
+
+// FirmwareUpdateStatus is the state of the update of a node
+type FirmwareUpdateStatus struct {
+	// NodeName of the node being updated
+	NodeName string `json:"nodeName"`
+	// Phase of the update
+	Phase FirmwareUpdatePhase `json:"phase"`
+	// PhaseStartTime is when the current phase started
+	PhaseStartTime metav1.Time `json:"phaseStartTime"`
+	// ObservedGeneration of the RBLNFirmware the update was started for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Cards is the number of RBLN cards the node reported when its update started
+	// +optional
+	Cards int32 `json:"cards,omitempty"`
+	// Message describes the state of the update
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Updating",type=string,JSONPath=`.status.update.nodeName`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RBLNFirmware is the Schema for the rblnfirmwares API
+type RBLNFirmware struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RBLNFirmwareSpec   `json:"spec,omitempty"`
+	Status RBLNFirmwareStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RBLNFirmwareList contains a list of RBLNFirmware
+type RBLNFirmwareList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RBLNFirmware `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RBLNFirmware{}, &RBLNFirmwareList{})
+}
+
+// GetNodeSelector returns node selector labels for the nodes whose firmware is managed.
+func (f *RBLNFirmware) GetNodeSelector() map[string]string {
+	if f == nil || len(f.Spec.NodeSelector) == 0 {
+		return map[string]string{
+			"rebellions.ai/npu.present": "true",
+		}
+	}
+	return f.Spec.NodeSelector
+}
+
+// DesiredVersions returns the desired firmware version per product card.
+func (s *RBLNFirmwareSpec) DesiredVersions() map[string]string {
+	versions := make(map[string]string, len(s.Cards))
+	for _, card := range s.Cards {
+		versions[card.Product] = card.Version
+	}
+	return versions
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RBLNUsageReportSpec defines the desired state of RBLNUsageReport
+// +kubebuilder:object:generate=true
+type RBLNUsageReportSpec struct {
+	// IntervalSeconds between two samples of the allocated NPU resources.
+	// Usage is accumulated from the start and end times of the pods, so no pod is missed between samples.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=30
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+
+	// Namespaces limits the report to these namespaces. All namespaces are reported if empty.
+	// +kubebuilder:validation:Optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// IncludePods adds the allocation of every pod to the status
+	// +kubebuilder:validation:Optional
+	IncludePods bool `json:"includePods,omitempty"`
+}
+
+// RBLNUsageReportStatus defines the observed state of RBLNUsageReport
+type RBLNUsageReportStatus struct {
+	// PeriodStart is when usage started to be accumulated. Recreate the report to start a new period.
+	// +optional
+	PeriodStart *metav1.Time `json:"periodStart,omitempty"`
+	// LastSampleTime is when the allocated NPU resources were last sampled
+	// +optional
+	LastSampleTime *metav1.Time `json:"lastSampleTime,omitempty"`
+	// Namespaces is the usage per namespace. Namespaces keep their accumulated usage
+	// after their pods are gone until the period ends.
+	// +optional
+	Namespaces []NamespaceUsage `json:"namespaces,omitempty"`
+	// Nodes is the NPU resources allocated per node at the last sample
+	// +optional
+	Nodes []NodeUsage `json:"nodes,omitempty"`
+	// Pods is the NPU resources allocated per pod at the last sample, if includePods is set
+	// +optional
+	Pods []PodUsage `json:"pods,omitempty"`
+	// Conditions is a list of conditions representing the RBLNUsageReport's current state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NamespaceUsage is the NPU usage of a namespace
+type NamespaceUsage struct {
+	// Namespace name
+	Namespace string `json:"namespace"`
+	// Resources used by the pods of the namespace
+	// +optional
+	Resources []ResourceUsage `json:"resources,omitempty"`
+}
+
+// NodeUsage is the NPU resources allocated on a node
+type NodeUsage struct {
+	// NodeName of the node
+	NodeName string `json:"nodeName"`
+	// Resources allocated to the pods of the node
+	// +optional
+	Resources []ResourceUsage `json:"resources,omitempty"`
+}
+
+// PodUsage is the NPU resources allocated to a pod
+type PodUsage struct {
+	// Namespace of the pod
+	Namespace string `json:"namespace"`
+	// Name of the pod
+	Name string `json:"name"`
+	// NodeName the pod is bound to
+	NodeName string `json:"nodeName"`
+	// Resources allocated to the pod
+	// +optional
+	Resources []ResourceUsage `json:"resources,omitempty"`
+}
+
+// ResourceUsage is the usage of an NPU resource
+type ResourceUsage struct {
+	// Resource name, e.g. rebellions.ai/ATOM
+	Resource string `json:"resource"`
+	// Allocated is the number of devices allocated at the last sample
+	Allocated int64 `json:"allocated"`
+	// DeviceSeconds is the number of devices allocated integrated over the period, from the
+	// time each pod was bound to its node until it terminated; divide by 3600 for device hours.
+	// Only reported per namespace.
+	// +optional
+	DeviceSeconds int64 `json:"deviceSeconds,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Period Start",type=date,JSONPath=`.status.periodStart`
+// +kubebuilder:printcolumn:name="Last Sample",type=date,JSONPath=`.status.lastSampleTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RBLNUsageReport is the Schema for the rblnusagereports API
+type RBLNUsageReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RBLNUsageReportSpec   `json:"spec,omitempty"`
+	Status RBLNUsageReportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RBLNUsageReportList contains a list of RBLNUsageReport
+type RBLNUsageReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RBLNUsageReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RBLNUsageReport{}, &RBLNUsageReportList{})
+}
@@ -26,6 +26,58 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CardFirmwareStatus) DeepCopyInto(out *CardFirmwareStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CardFirmwareStatus.
+func (in *CardFirmwareStatus) DeepCopy() *CardFirmwareStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CardFirmwareStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerImage) DeepCopyInto(out *ContainerImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerImage.
+func (in *ContainerImage) DeepCopy() *ContainerImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverDigestsSpec) DeepCopyInto(out *DriverDigestsSpec) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverDigestsSpec.
+func (in *DriverDigestsSpec) DeepCopy() *DriverDigestsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverDigestsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DriverManagerSpec) DeepCopyInto(out *DriverManagerSpec) {
 	*out = *in
@@ -51,6 +103,21 @@ func (in *DriverManagerSpec) DeepCopy() *DriverManagerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverSecureBootSpec) DeepCopyInto(out *DriverSecureBootSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverSecureBootSpec.
+func (in *DriverSecureBootSpec) DeepCopy() *DriverSecureBootSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverSecureBootSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvVar) DeepCopyInto(out *EnvVar) {
 	*out = *in
@@ -66,6 +133,136 @@ func (in *EnvVar) DeepCopy() *EnvVar {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareCardSpec) DeepCopyInto(out *FirmwareCardSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareCardSpec.
+func (in *FirmwareCardSpec) DeepCopy() *FirmwareCardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareCardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareUpdateSpec) DeepCopyInto(out *FirmwareUpdateSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdateSpec.
+func (in *FirmwareUpdateSpec) DeepCopy() *FirmwareUpdateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareUpdateStatus) DeepCopyInto(out *FirmwareUpdateStatus) {
+	*out = *in
+	in.PhaseStartTime.DeepCopyInto(&out.PhaseStartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdateStatus.
+func (in *FirmwareUpdateStatus) DeepCopy() *FirmwareUpdateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceUsage) DeepCopyInto(out *NamespaceUsage) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceUsage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceUsage.
+func (in *NamespaceUsage) DeepCopy() *NamespaceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeFirmwareStatus) DeepCopyInto(out *NodeFirmwareStatus) {
+	*out = *in
+	if in.Cards != nil {
+		in, out := &in.Cards, &out.Cards
+		*out = make([]CardFirmwareStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastInventoryTime != nil {
+		in, out := &in.LastInventoryTime, &out.LastInventoryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeFirmwareStatus.
+func (in *NodeFirmwareStatus) DeepCopy() *NodeFirmwareStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeFirmwareStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeUsage) DeepCopyInto(out *NodeUsage) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceUsage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUsage.
+func (in *NodeUsage) DeepCopy() *NodeUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodUsage) DeepCopyInto(out *PodUsage) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceUsage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodUsage.
+func (in *PodUsage) DeepCopy() *PodUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(PodUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RBLNDriver) DeepCopyInto(out *RBLNDriver) {
 	*out = *in
@@ -128,6 +325,11 @@ func (in *RBLNDriverList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RBLNDriverSpec) DeepCopyInto(out *RBLNDriverSpec) {
 	*out = *in
+	if in.Precompiled != nil {
+		in, out := &in.Precompiled, &out.Precompiled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
 		*out = make([]string, len(*in))
@@ -180,6 +382,26 @@ func (in *RBLNDriverSpec) DeepCopyInto(out *RBLNDriverSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SecureBoot != nil {
+		in, out := &in.SecureBoot, &out.SecureBoot
+		*out = new(DriverSecureBootSpec)
+		**out = **in
+	}
+	if in.Digests != nil {
+		in, out := &in.Digests, &out.Digests
+		*out = new(DriverDigestsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePreflight != nil {
+		in, out := &in.ImagePreflight, &out.ImagePreflight
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DriverToolkit != nil {
+		in, out := &in.DriverToolkit, &out.DriverToolkit
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNDriverSpec.
@@ -202,6 +424,11 @@ func (in *RBLNDriverStatus) DeepCopyInto(out *RBLNDriverStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ContainerImage, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNDriverStatus.
@@ -213,3 +440,281 @@ func (in *RBLNDriverStatus) DeepCopy() *RBLNDriverStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBLNFirmware) DeepCopyInto(out *RBLNFirmware) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNFirmware.
+func (in *RBLNFirmware) DeepCopy() *RBLNFirmware {
+	if in == nil {
+		return nil
+	}
+	out := new(RBLNFirmware)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RBLNFirmware) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBLNFirmwareList) DeepCopyInto(out *RBLNFirmwareList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RBLNFirmware, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNFirmwareList.
+func (in *RBLNFirmwareList) DeepCopy() *RBLNFirmwareList {
+	if in == nil {
+		return nil
+	}
+	out := new(RBLNFirmwareList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RBLNFirmwareList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBLNFirmwareSpec) DeepCopyInto(out *RBLNFirmwareSpec) {
+	*out = *in
+	if in.Cards != nil {
+		in, out := &in.Cards, &out.Cards
+		*out = make([]FirmwareCardSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Update = in.Update
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNFirmwareSpec.
+func (in *RBLNFirmwareSpec) DeepCopy() *RBLNFirmwareSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RBLNFirmwareSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBLNFirmwareStatus) DeepCopyInto(out *RBLNFirmwareStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeFirmwareStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Update != nil {
+		in, out := &in.Update, &out.Update
+		*out = new(FirmwareUpdateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNFirmwareStatus.
+func (in *RBLNFirmwareStatus) DeepCopy() *RBLNFirmwareStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RBLNFirmwareStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBLNUsageReport) DeepCopyInto(out *RBLNUsageReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNUsageReport.
+func (in *RBLNUsageReport) DeepCopy() *RBLNUsageReport {
+	if in == nil {
+		return nil
+	}
+	out := new(RBLNUsageReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RBLNUsageReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBLNUsageReportList) DeepCopyInto(out *RBLNUsageReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RBLNUsageReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNUsageReportList.
+func (in *RBLNUsageReportList) DeepCopy() *RBLNUsageReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(RBLNUsageReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RBLNUsageReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBLNUsageReportSpec) DeepCopyInto(out *RBLNUsageReportSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNUsageReportSpec.
+func (in *RBLNUsageReportSpec) DeepCopy() *RBLNUsageReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RBLNUsageReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBLNUsageReportStatus) DeepCopyInto(out *RBLNUsageReportStatus) {
+	*out = *in
+	if in.PeriodStart != nil {
+		in, out := &in.PeriodStart, &out.PeriodStart
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSampleTime != nil {
+		in, out := &in.LastSampleTime, &out.LastSampleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]NamespaceUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]PodUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNUsageReportStatus.
+func (in *RBLNUsageReportStatus) DeepCopy() *RBLNUsageReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RBLNUsageReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceUsage) DeepCopyInto(out *ResourceUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceUsage.
+func (in *ResourceUsage) DeepCopy() *ResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
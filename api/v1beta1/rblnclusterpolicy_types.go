@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -45,6 +46,18 @@ type RBLNClusterPolicySpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Workload Type",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:container,urn:alm:descriptor:com.tectonic.ui:select:vm-passthrough"
 	WorkloadType string `json:"workloadType"`
 
+	// DeviceListStrategy selects how allocated NPUs are passed to containers.
+	// cdi-annotations makes the device plugins return CDI annotations for the specs generated by
+	// the container toolkit; envvar returns the device nodes and environment variables instead,
+	// for container runtimes without CDI. The device plugins, the container toolkit and the toolkit
+	// validation all receive the strategy. Each component keeps its own default when unset.
+	// cdi-cri and volume-mounts are not offered: the device plugins only switch CDI annotations on
+	// or off, and have no way to return CDI devices through the CRI or devices as volume mounts.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=cdi-annotations;envvar
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Device List Strategy",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:cdi-annotations,urn:alm:descriptor:com.tectonic.ui:select:envvar"
+	DeviceListStrategy string `json:"deviceListStrategy,omitempty"`
+
 	// DaemonSets is common spec of rbln daemonset components
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Daemonsets",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
 	Daemonsets *DaemonsetsSpec `json:"daemonsets,omitempty"`
@@ -80,6 +93,47 @@ type RBLNClusterPolicySpec struct {
 	// Validator defines the spec for operator-validator daemonset
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Validator",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
 	Validator ValidatorSpec `json:"validator,omitempty"`
+
+	// HealthMonitor reports NPU health on each node as the RBLNNPUHealthy node condition
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Health Monitor",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	HealthMonitor *RBLNHealthMonitorSpec `json:"healthMonitor,omitempty"`
+
+	// Remediation escalates automated recovery actions on nodes reported unhealthy by the health monitor
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Remediation",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Remediation *RemediationSpec `json:"remediation,omitempty"`
+
+	// ImageMirrors rewrites the image of every managed container, e.g. for air-gapped installs
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image Mirrors",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	ImageMirrors *ImageMirrorsSpec `json:"imageMirrors,omitempty"`
+
+	// Proxy sets the HTTP(S) proxy of the operand containers, including the driver and flash jobs.
+	// On OpenShift the cluster-wide Proxy object is used when unset.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Proxy",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Proxy *ProxySpec `json:"proxy,omitempty"`
+
+	// TrustedCA mounts a CA bundle into every operand container, e.g. for a private registry
+	// or package mirror signed by an internal CA
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Trusted CA",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	TrustedCA *TrustedCASpec `json:"trustedCA,omitempty"`
+
+	// NodeTaint dedicates the nodes with RBLN devices to NPU workloads with a NoSchedule taint.
+	// Operands and the RBLN RuntimeClass tolerate it; other workloads must tolerate it to run on
+	// NPU nodes.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Node Taint",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	NodeTaint *NodeTaintSpec `json:"nodeTaint,omitempty"`
+
+	// Paused stops reconciling the components, drivers and firmware, leaving their resources
+	// untouched. Node labels and taints are not changed, firmware updates and NPU health
+	// remediation are suspended, and the status is still reported.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Paused",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Paused bool `json:"paused,omitempty"`
 }
 
 // DaemonsetsSpec indicates common configuration for all Daemonsets managed by RBLN NPU Operator
@@ -156,6 +210,13 @@ type RBLNVFIOManagerSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable RBLN VFIO Manager deployment",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
 	Enabled bool `json:"enabled,omitempty"`
 
+	// ManagementState is Managed to reconcile the component, Unmanaged to leave its existing
+	// resources untouched, e.g. while hot-fixing a DaemonSet, or Removed to delete them
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Managed
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Management State",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:Managed,urn:alm:descriptor:com.tectonic.ui:select:Unmanaged,urn:alm:descriptor:com.tectonic.ui:select:Removed"
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
 	// RBLN VFIO Manager image name
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=rebellions/rbln-vfio-manager
@@ -193,6 +254,13 @@ type RBLNSandboxDevicePluginSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable RBLN Sandbox Device Plugin deployment",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
 	Enabled bool `json:"enabled,omitempty"`
 
+	// ManagementState is Managed to reconcile the component, Unmanaged to leave its existing
+	// resources untouched, e.g. while hot-fixing a DaemonSet, or Removed to delete them
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Managed
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Management State",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:Managed,urn:alm:descriptor:com.tectonic.ui:select:Unmanaged,urn:alm:descriptor:com.tectonic.ui:select:Removed"
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
 	// RBLN Sandbox Device Plugin image name
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=rebellions/k8s-device-plugin
@@ -280,6 +348,13 @@ type RBLNDevicePluginSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable RBLN Device Plugin deployment",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
 	Enabled bool `json:"enabled,omitempty"`
 
+	// ManagementState is Managed to reconcile the component, Unmanaged to leave its existing
+	// resources untouched, e.g. while hot-fixing a DaemonSet, or Removed to delete them
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Managed
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Management State",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:Managed,urn:alm:descriptor:com.tectonic.ui:select:Unmanaged,urn:alm:descriptor:com.tectonic.ui:select:Removed"
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
 	// RBLN Device Plugin image name
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=rebellions/k8s-device-plugin
@@ -328,6 +403,13 @@ type RBLNMetricsExporterSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable RBLN Metrics Exporter deployment",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
 	Enabled bool `json:"enabled,omitempty"`
 
+	// ManagementState is Managed to reconcile the component, Unmanaged to leave its existing
+	// resources untouched, e.g. while hot-fixing a DaemonSet, or Removed to delete them
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Managed
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Management State",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:Managed,urn:alm:descriptor:com.tectonic.ui:select:Unmanaged,urn:alm:descriptor:com.tectonic.ui:select:Removed"
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
 	// RBLN Metrics Exporter image name
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=rebellions/rbln-metrics-exporter
@@ -355,6 +437,259 @@ type RBLNMetricsExporterSpec struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PriorityClassName",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// ServiceMonitor specifies the Prometheus Operator ServiceMonitor configuration for the metrics exporter
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Service Monitor",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	ServiceMonitor *ServiceMonitorSpec `json:"serviceMonitor,omitempty"`
+
+	// PrometheusRule specifies the default alerting rules deployed alongside the metrics exporter
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Prometheus Rule",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PrometheusRule *PrometheusRuleSpec `json:"prometheusRule,omitempty"`
+
+	// Port the metrics endpoint is served on, by the exporter itself in the none mode and by the
+	// kube-rbac-proxy sidecar in the tls and rbacProxy modes, where the exporter listens on 9091
+	// behind it. The exporter reads the port from RBLN_METRICS_EXPORTER_PORT.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=9090
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Metrics port",xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	Port int32 `json:"port,omitempty"`
+
+	// Security configures how the metrics endpoint is protected
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Security",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Security *MetricsExporterSecuritySpec `json:"security,omitempty"`
+
+	// GrafanaDashboard configures provisioning of the NPU telemetry Grafana dashboard
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Grafana Dashboard",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	GrafanaDashboard *GrafanaDashboardSpec `json:"grafanaDashboard,omitempty"`
+}
+
+// GrafanaDashboardSpec defines how the NPU telemetry dashboard is provisioned.
+// The dashboard is shipped as a GrafanaDashboard resource when the Grafana Operator CRD is installed
+// and InstanceSelector is set, and as a labelled ConfigMap for the Grafana sidecar otherwise.
+type GrafanaDashboardSpec struct {
+	// Enabled indicates if the NPU telemetry dashboard is provisioned
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Grafana Dashboard",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Namespace in which the dashboard is created, defaults to the operator namespace
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Namespace string `json:"namespace,omitempty"`
+
+	// Labels added to the dashboard ConfigMap so that it is discovered by the Grafana sidecar
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={grafana_dashboard:"1"}
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Labels",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Folder the dashboard is placed in
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Folder",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Folder string `json:"folder,omitempty"`
+
+	// InstanceSelector selects the Grafana instances the GrafanaDashboard resource is applied to
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Instance Selector",xDescriptors="urn:alm:descriptor:com.tectonic.ui:selector:grafana.integreatly.org:v1beta1:Grafana"
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+}
+
+const (
+	// MetricsSecurityModeNone serves metrics over plain HTTP
+	MetricsSecurityModeNone = "none"
+	// MetricsSecurityModeTLS serves metrics over HTTPS through a kube-rbac-proxy sidecar that only terminates TLS
+	MetricsSecurityModeTLS = "tls"
+	// MetricsSecurityModeRBACProxy fronts the exporter with kube-rbac-proxy, authorizing scrapes with TokenReview and SubjectAccessReview.
+	// The ServiceMonitor scrapes with the token of a ServiceAccount that is granted get on /metrics.
+	MetricsSecurityModeRBACProxy = "rbacProxy"
+)
+
+// MetricsExporterSecuritySpec defines how the metrics exporter endpoint is protected
+type MetricsExporterSecuritySpec struct {
+	// Mode selects how the metrics endpoint is protected
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=none;tls;rbacProxy
+	// +kubebuilder:default:=none
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mode",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:none,urn:alm:descriptor:com.tectonic.ui:select:tls,urn:alm:descriptor:com.tectonic.ui:select:rbacProxy"
+	Mode string `json:"mode,omitempty"`
+
+	// TLS specifies the serving certificate used by the kube-rbac-proxy sidecar. It is required in
+	// the tls and rbacProxy modes outside OpenShift, where the service CA signs the certificate.
+	// The ServiceMonitor verifies the certificate with the ca.crt key of the Secret.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TLS",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	TLS *TLSCertificateSpec `json:"tls,omitempty"`
+
+	// RBACProxy configures the kube-rbac-proxy sidecar used in the tls and rbacProxy modes
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="RBAC Proxy",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	RBACProxy RBACProxySpec `json:"rbacProxy,omitempty"`
+}
+
+// TLSCertificateSpec references a certificate stored in a kubernetes.io/tls Secret,
+// either provided by the user or issued by cert-manager
+type TLSCertificateSpec struct {
+	// SecretName is the name of a kubernetes.io/tls Secret in the operator namespace.
+	// When CertManager is set, the issued certificate is written to this Secret.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Secret Name",xDescriptors="urn:alm:descriptor:io.kubernetes:Secret"
+	SecretName string `json:"secretName,omitempty"`
+
+	// CertManager requests the certificate from a cert-manager issuer
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="cert-manager",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	CertManager *CertManagerIssuerSpec `json:"certManager,omitempty"`
+}
+
+// CertManagerIssuerSpec references the cert-manager issuer used to sign a Certificate
+type CertManagerIssuerSpec struct {
+	// IssuerName is the name of the Issuer or ClusterIssuer
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Issuer Name",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	IssuerName string `json:"issuerName"`
+
+	// IssuerKind is the kind of the issuer
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default:=Issuer
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Issuer Kind",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:Issuer,urn:alm:descriptor:com.tectonic.ui:select:ClusterIssuer"
+	IssuerKind string `json:"issuerKind,omitempty"`
+
+	// IssuerGroup is the API group of the issuer
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=cert-manager.io
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Issuer Group",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	IssuerGroup string `json:"issuerGroup,omitempty"`
+
+	// Duration is the requested lifetime of the certificate
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Duration",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Duration string `json:"duration,omitempty"`
+}
+
+// RBACProxySpec defines the kube-rbac-proxy sidecar container
+type RBACProxySpec struct {
+	// kube-rbac-proxy image name
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=brancz/kube-rbac-proxy
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Image string `json:"image,omitempty"`
+
+	// Registry override for the kube-rbac-proxy image
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=quay.io
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Registry",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Registry string `json:"registry,omitempty"`
+
+	// kube-rbac-proxy image tag
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="v0.18.1"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Version",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Version string `json:"version,omitempty"`
+
+	// ImagePullPolicy specifies the image pull policy for the kube-rbac-proxy container
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=IfNotPresent
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image Pull Policy",xDescriptors="urn:alm:descriptor:com.tectonic.ui:imagePullPolicy"
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// Resources specifies the resource requirements for the kube-rbac-proxy container
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Requirements",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:resourceRequirements"
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// ServiceMonitorSpec defines the ServiceMonitor created for the metrics exporter
+type ServiceMonitorSpec struct {
+	// Enabled indicates if a ServiceMonitor is created for the metrics exporter
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable ServiceMonitor",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Interval at which metrics should be scraped
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="15s"
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Interval",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Interval string `json:"interval,omitempty"`
+
+	// HonorLabels chooses the metric's labels on collisions with target labels
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Honor Labels",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	HonorLabels bool `json:"honorLabels,omitempty"`
+
+	// AdditionalLabels to add to the ServiceMonitor so that it is selected by the Prometheus instance
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Additional Labels",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	AdditionalLabels map[string]string `json:"additionalLabels,omitempty"`
+
+	// Relabelings to apply to samples before ingestion
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Relabelings",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Relabelings []RelabelConfig `json:"relabelings,omitempty"`
+}
+
+// RelabelConfig allows dynamic rewriting of the label set of scraped targets.
+// It mirrors the RelabelConfig type of the Prometheus Operator.
+type RelabelConfig struct {
+	// SourceLabels select values from existing labels
+	// +kubebuilder:validation:Optional
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+
+	// Separator placed between concatenated source label values
+	// +kubebuilder:validation:Optional
+	Separator string `json:"separator,omitempty"`
+
+	// TargetLabel to which the resulting value is written in a replace action
+	// +kubebuilder:validation:Optional
+	TargetLabel string `json:"targetLabel,omitempty"`
+
+	// Regex against which the extracted value is matched
+	// +kubebuilder:validation:Optional
+	Regex string `json:"regex,omitempty"`
+
+	// Modulus to take of the hash of the source label values
+	// +kubebuilder:validation:Optional
+	Modulus uint64 `json:"modulus,omitempty"`
+
+	// Replacement value against which a regex replace is performed
+	// +kubebuilder:validation:Optional
+	Replacement string `json:"replacement,omitempty"`
+
+	// Action to perform based on regex matching
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=replace;Replace;keep;Keep;drop;Drop;hashmod;HashMod;labelmap;LabelMap;labeldrop;LabelDrop;labelkeep;LabelKeep;lowercase;Lowercase;uppercase;Uppercase;keepequal;KeepEqual;dropequal;DropEqual
+	Action string `json:"action,omitempty"`
+}
+
+// PrometheusRuleSpec defines the default PrometheusRule created for the metrics exporter
+type PrometheusRuleSpec struct {
+	// Enabled indicates if the default alerting rules are created
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable PrometheusRule",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled bool `json:"enabled,omitempty"`
+
+	// AdditionalLabels to add to the PrometheusRule so that it is selected by the Prometheus instance
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Additional Labels",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	AdditionalLabels map[string]string `json:"additionalLabels,omitempty"`
+
+	// TemperatureThreshold in degrees Celsius above which the NPU temperature alert fires
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=85
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Temperature Threshold",xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	TemperatureThreshold int32 `json:"temperatureThreshold,omitempty"`
 }
 
 // RBLNDaemonSpec defines the desired state of RBLN Daemon
@@ -364,6 +699,13 @@ type RBLNDaemonSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable RBLN Daemon deployment",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
 	Enabled bool `json:"enabled,omitempty"`
 
+	// ManagementState is Managed to reconcile the component, Unmanaged to leave its existing
+	// resources untouched, e.g. while hot-fixing a DaemonSet, or Removed to delete them
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Managed
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Management State",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:Managed,urn:alm:descriptor:com.tectonic.ui:select:Unmanaged,urn:alm:descriptor:com.tectonic.ui:select:Removed"
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
 	// RBLN Daemon image name
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=rebellions/rbln-daemon
@@ -404,6 +746,70 @@ type RBLNDaemonSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Host port to bind for rbln-daemon",xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
 	// +kubebuilder:default:=50051
 	HostPort int32 `json:"hostPort,omitempty"`
+
+	// TLS configures mutual TLS on the rbln-daemon gRPC endpoint
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TLS",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	TLS *RBLNDaemonTLSSpec `json:"tls,omitempty"`
+
+	// NetworkPolicy restricts which pods can reach the rbln-daemon endpoint
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Network Policy",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	NetworkPolicy *RBLNDaemonNetworkPolicySpec `json:"networkPolicy,omitempty"`
+}
+
+// RBLNDaemonTLSContractV1 is the first version of the rbln-daemon TLS component contract.
+const RBLNDaemonTLSContractV1 = "v1"
+
+// RBLNDaemonTLSSpec defines mutual TLS for the rbln-daemon gRPC endpoint.
+// Both certificates must be signed by the CA stored under ca.crt in the server certificate Secret.
+// The operator verifies the client certificate against that CA and distributes it to consumers
+// through the rbln-daemon-ca-bundle ConfigMap.
+// +kubebuilder:validation:XValidation:rule="!self.enabled || has(self.componentContract)",message="mTLS requires the componentContract the rbln-daemon, NPU feature discovery and metrics exporter images implement"
+type RBLNDaemonTLSSpec struct {
+	// Enabled indicates if rbln-daemon serves gRPC over mutual TLS
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable mTLS",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ComponentContract is the version of the TLS contract the configured rbln-daemon, NPU
+	// feature discovery and metrics exporter images implement. Images without it reject the
+	// settings below, so mTLS is only configured once it is set. Contract v1:
+	// rbln-daemon takes --tls-cert-file, --tls-key-file and --tls-client-ca-file;
+	// NPU feature discovery takes --rbln-daemon-tls-ca-file, --rbln-daemon-tls-cert-file,
+	// --rbln-daemon-tls-key-file and --rbln-daemon-tls-server-name;
+	// the metrics exporter reads RBLN_METRICS_EXPORTER_RBLN_DAEMON_URL_TLS_CA_FILE, _TLS_CERT_FILE,
+	// _TLS_KEY_FILE and _TLS_SERVER_NAME.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=v1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Component Contract",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	ComponentContract string `json:"componentContract,omitempty"`
+
+	// ServerCertificate is served by rbln-daemon
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Server Certificate",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	ServerCertificate TLSCertificateSpec `json:"serverCertificate,omitempty"`
+
+	// ClientCertificate is presented by operator components connecting to rbln-daemon
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Client Certificate",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	ClientCertificate TLSCertificateSpec `json:"clientCertificate,omitempty"`
+}
+
+// RBLNDaemonNetworkPolicySpec defines the NetworkPolicy protecting the rbln-daemon endpoint
+type RBLNDaemonNetworkPolicySpec struct {
+	// Enabled indicates if a NetworkPolicy restricting access to rbln-daemon is created.
+	// The metrics exporter and NPU feature discovery are always allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Network Policy",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled bool `json:"enabled,omitempty"`
+
+	// AdditionalClients are additional peers allowed to reach rbln-daemon
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Additional Clients",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	AdditionalClients []networkingv1.NetworkPolicyPeer `json:"additionalClients,omitempty"`
 }
 
 // RBLNNPUFeatureDiscoverySpec defines the desired state of RBLNNPUFeatureDiscovery
@@ -413,6 +819,13 @@ type RBLNNPUFeatureDiscoverySpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable RBLN NPU Feature Discovery deployment",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
 	Enabled bool `json:"enabled,omitempty"`
 
+	// ManagementState is Managed to reconcile the component, Unmanaged to leave its existing
+	// resources untouched, e.g. while hot-fixing a DaemonSet, or Removed to delete them
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Managed
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Management State",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:Managed,urn:alm:descriptor:com.tectonic.ui:select:Unmanaged,urn:alm:descriptor:com.tectonic.ui:select:Removed"
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
 	// RBLN NPU Feature Discovery image name
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=rebellions/rbln-npu-feature-discovery
@@ -449,6 +862,13 @@ type RBLNContainerToolkitSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable RBLN Container Toolkit deployment",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
 	Enabled bool `json:"enabled,omitempty"`
 
+	// ManagementState is Managed to reconcile the component, Unmanaged to leave its existing
+	// resources untouched, e.g. while hot-fixing a DaemonSet, or Removed to delete them
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Managed
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Management State",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:Managed,urn:alm:descriptor:com.tectonic.ui:select:Unmanaged,urn:alm:descriptor:com.tectonic.ui:select:Removed"
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
 	// RBLN Container Toolkit image name
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=rebellions/rbln-container-toolkit
@@ -484,10 +904,224 @@ type RBLNContainerToolkitSpec struct {
 	// Optional: List of environment variables
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Environment Variables",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Runtime overrides the container runtime detected on each node.
+	// The containerd major version is still detected per node.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=containerd;docker;crio;k3s;rke2
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Container Runtime",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
+	Runtime string `json:"runtime,omitempty"`
+
+	// SocketPath overrides the host path of the container runtime socket
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^/.*`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Runtime Socket Path",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
+	SocketPath string `json:"socketPath,omitempty"`
+
+	// ConfigPath overrides the host path of the container runtime configuration file.
+	// For k3s and RKE2 this is the containerd config template.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^/.*`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Runtime Config Path",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
+	ConfigPath string `json:"configPath,omitempty"`
+
+	// RuntimeClass registers a named runtime handler instead of injecting NPUs through the default runtime
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Runtime Class"
+	RuntimeClass *RuntimeClassSpec `json:"runtimeClass,omitempty"`
+}
+
+// RuntimeClassSpec describes the RuntimeClass created for the RBLN runtime handler.
+// Workloads opt in with runtimeClassName; other pods on the node keep using the default runtime.
+type RuntimeClassSpec struct {
+	// Enabled indicates if the named runtime handler and its RuntimeClass are created
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable RuntimeClass",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Name of the RuntimeClass referenced by workloads through runtimeClassName
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=rbln
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Name string `json:"name,omitempty"`
+
+	// Handler is the runtime handler registered in the container runtime configuration
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=rbln
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Handler",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Handler string `json:"handler,omitempty"`
+}
+
+// ImageMirrorsSpec maps image prefixes to mirror prefixes. Mappings are applied to the rendered
+// images of every component, the driver and the driver manager; the most specific source wins.
+type ImageMirrorsSpec struct {
+	// Mirrors lists the source prefix to mirror prefix mappings
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=source
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mirrors",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Mirrors []ImageMirror `json:"mirrors,omitempty"`
+
+	// UseOpenShiftMirrorSets keeps the images of the sources of the ImageDigestMirrorSets and ImageTagMirrorSets
+	// of an OpenShift cluster, which CRI-O pulls from their mirrors, instead of rewriting them with a less specific
+	// mirror listed above. Mirrors listed above take precedence for the same source.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=true
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Use OpenShift Mirror Sets",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	UseOpenShiftMirrorSets *bool `json:"useOpenShiftMirrorSets,omitempty"`
+}
+
+// ImageMirror replaces the Source prefix of an image with Mirror,
+// e.g. repo.rebellions.ai/rebellions to registry.local/rebellions
+type ImageMirror struct {
+	// Source is a registry, or a registry and repository path prefix
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source"`
+
+	// Mirror replaces Source in the image reference
+	// +kubebuilder:validation:MinLength=1
+	Mirror string `json:"mirror"`
+}
+
+// ProxySpec is the HTTP(S) proxy injected as HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// into operand containers. Variables set explicitly on a container are kept.
+type ProxySpec struct {
+	// HTTPProxy is the proxy URL of HTTP requests
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="HTTP Proxy",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the proxy URL of HTTPS requests
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="HTTPS Proxy",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is a comma-separated list of hosts, domains and CIDRs that bypass the proxy.
+	// The service networks, .svc and .cluster.local are always added.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="No Proxy",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// TrustedCASpec selects the CA bundle of the operands. The operator copies the bundle into a
+// ConfigMap of its namespace, and an init container appends it to the system bundle of the
+// validator image, which is then mounted over the system bundle of every container. The driver
+// image preflight trusts the bundle as well.
+type TrustedCASpec struct {
+	// ConfigMap is the name of the ConfigMap holding the PEM bundle. On OpenShift the cluster
+	// trusted CA bundle, including the additional CAs of the cluster proxy, is injected when unset.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ConfigMap",xDescriptors="urn:alm:descriptor:io.kubernetes:ConfigMap"
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// Namespace of the ConfigMap, the operator namespace when unset
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Namespace string `json:"namespace,omitempty"`
+
+	// Key of the bundle in the ConfigMap
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=ca-bundle.crt
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Key",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Key string `json:"key,omitempty"`
+}
+
+// NodeTaintSpec describes the rebellions.ai/npu=present:NoSchedule taint of the nodes with RBLN devices.
+type NodeTaintSpec struct {
+	// Enabled taints every node with RBLN devices and removes the taint when the devices are gone
+	// or the option is disabled. Only the taint the operator added is removed
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Node Taint",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// RBLNHealthMonitorSpec describes the NPU health watcher run by the validator daemonset.
+// The watcher checks device presence, driver binding, PCIe fatal errors and rbln-smi on every node.
+// The watcher may only annotate its own node, which is enforced by a ValidatingAdmissionPolicy (Kubernetes 1.30+).
+type RBLNHealthMonitorSpec struct {
+	// Enabled indicates if NPU health is watched and reported as a node condition
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Health Monitor",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled bool `json:"enabled,omitempty"`
+
+	// IntervalSeconds between two health checks on a node
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=5
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Interval Seconds",xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+
+	// TaintUnhealthyNodes adds a NoSchedule taint to nodes with an unhealthy NPU until they recover
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Taint Unhealthy Nodes",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	TaintUnhealthyNodes bool `json:"taintUnhealthyNodes,omitempty"`
+}
+
+// RemediationSpec describes the automated recovery of nodes with an unhealthy NPU.
+// Actions run one at a time, in order, until the node reports healthy again. Nodes whose firmware
+// is being updated or whose driver pod is not Ready are left alone, and nodes cordoned before
+// remediation stay cordoned after they recover.
+type RemediationSpec struct {
+	// Enabled indicates if unhealthy nodes are remediated automatically
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Remediation",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Actions to escalate through, in order
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={"restart-device-plugin","reset-device","restart-driver","cordon"}
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Actions",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Actions []RemediationAction `json:"actions,omitempty"`
+
+	// IntervalSeconds to wait for a node to recover before escalating to the next action
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=30
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Interval Seconds",xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+
+	// MaxConcurrent is the number of nodes remediated at the same time
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Concurrent",xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	MaxConcurrent int32 `json:"maxConcurrent,omitempty"`
+
+	// HistoryLimit is the number of remediation events kept per node in the status
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="History Limit",xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
 }
 
+// RemediationAction is a step of the remediation escalation
+// +kubebuilder:validation:Enum=restart-device-plugin;reset-device;restart-driver;cordon
+type RemediationAction string
+
+// Remediation actions
+const (
+	// RemediationRestartDevicePlugin deletes the device plugin pod of the node
+	RemediationRestartDevicePlugin RemediationAction = "restart-device-plugin"
+	// RemediationResetDevice cordons and drains the node and asks the health watcher to reset the unhealthy PCI functions
+	RemediationResetDevice RemediationAction = "reset-device"
+	// RemediationRestartDriver deletes the driver pod of the node
+	RemediationRestartDriver RemediationAction = "restart-driver"
+	// RemediationCordon cordons the node and annotates it for a reboot
+	RemediationCordon RemediationAction = "cordon"
+)
+
 // ValidatorSpec describes configuration options for validation daemonset
 type ValidatorSpec struct {
+	// ManagementState is Managed to reconcile the component, Unmanaged to leave its existing
+	// resources untouched, e.g. while hot-fixing a DaemonSet, or Removed to delete them
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Managed
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Management State",xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:Managed,urn:alm:descriptor:com.tectonic.ui:select:Unmanaged,urn:alm:descriptor:com.tectonic.ui:select:Removed"
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
 	// Plugin validator spec
 	Plugin PluginValidatorSpec `json:"plugin,omitempty"`
 
@@ -571,6 +1205,12 @@ type VFIOPCIValidatorSpec struct {
 	Env []corev1.EnvVar `json:"env,omitempty"`
 }
 
+// Device list strategies
+const (
+	DeviceListStrategyCDIAnnotations = "cdi-annotations"
+	DeviceListStrategyEnvVar         = "envvar"
+)
+
 // IsEnabled implementations for component specs
 func (s RBLNVFIOManagerSpec) IsEnabled() bool         { return s.Enabled }
 func (s RBLNDevicePluginSpec) IsEnabled() bool        { return s.Enabled }
@@ -580,12 +1220,125 @@ func (s RBLNNPUFeatureDiscoverySpec) IsEnabled() bool { return s.Enabled }
 func (s RBLNSandboxDevicePluginSpec) IsEnabled() bool { return s.Enabled }
 func (s RBLNContainerToolkitSpec) IsEnabled() bool    { return s.Enabled }
 
+// GetManagementState implementations for component specs. A disabled component is Removed.
+func (s RBLNVFIOManagerSpec) GetManagementState() ManagementState {
+	return effectiveManagementState(s.Enabled, s.ManagementState)
+}
+func (s RBLNDevicePluginSpec) GetManagementState() ManagementState {
+	return effectiveManagementState(s.Enabled, s.ManagementState)
+}
+func (s RBLNMetricsExporterSpec) GetManagementState() ManagementState {
+	return effectiveManagementState(s.Enabled, s.ManagementState)
+}
+func (s RBLNDaemonSpec) GetManagementState() ManagementState {
+	return effectiveManagementState(s.Enabled, s.ManagementState)
+}
+func (s RBLNNPUFeatureDiscoverySpec) GetManagementState() ManagementState {
+	return effectiveManagementState(s.Enabled, s.ManagementState)
+}
+func (s RBLNSandboxDevicePluginSpec) GetManagementState() ManagementState {
+	return effectiveManagementState(s.Enabled, s.ManagementState)
+}
+func (s RBLNContainerToolkitSpec) GetManagementState() ManagementState {
+	return effectiveManagementState(s.Enabled, s.ManagementState)
+}
+func (s ValidatorSpec) GetManagementState() ManagementState {
+	return effectiveManagementState(true, s.ManagementState)
+}
+
+func effectiveManagementState(enabled bool, state ManagementState) ManagementState {
+	if !enabled {
+		return ManagementStateRemoved
+	}
+	if state == "" {
+		return ManagementStateManaged
+	}
+	return state
+}
+
+// IsServiceMonitorEnabled returns true if a ServiceMonitor should be created for the metrics exporter
+func (s RBLNMetricsExporterSpec) IsServiceMonitorEnabled() bool {
+	return s.ServiceMonitor != nil && s.ServiceMonitor.Enabled
+}
+
+// IsPrometheusRuleEnabled returns true if the default PrometheusRule should be created for the metrics exporter
+func (s RBLNMetricsExporterSpec) IsPrometheusRuleEnabled() bool {
+	return s.PrometheusRule != nil && s.PrometheusRule.Enabled
+}
+
+// IsGrafanaDashboardEnabled returns true if the NPU telemetry dashboard should be provisioned
+func (s RBLNMetricsExporterSpec) IsGrafanaDashboardEnabled() bool {
+	return s.GrafanaDashboard != nil && s.GrafanaDashboard.Enabled
+}
+
+// IsTLSEnabled returns true if rbln-daemon serves gRPC over mutual TLS, which requires the
+// components to implement a TLS contract
+func (s RBLNDaemonSpec) IsTLSEnabled() bool {
+	return s.TLS != nil && s.TLS.Enabled && s.TLS.ComponentContract != ""
+}
+
+// IsNetworkPolicyEnabled returns true if access to rbln-daemon is restricted by a NetworkPolicy
+func (s RBLNDaemonSpec) IsNetworkPolicyEnabled() bool {
+	return s.NetworkPolicy != nil && s.NetworkPolicy.Enabled
+}
+
+// IsRuntimeClassEnabled returns true if the toolkit registers a named runtime handler with a RuntimeClass
+func (s RBLNContainerToolkitSpec) IsRuntimeClassEnabled() bool {
+	return s.RuntimeClass != nil && s.RuntimeClass.Enabled
+}
+
+// IsHealthMonitorEnabled returns true if NPU health is reported as a node condition
+func (s RBLNClusterPolicySpec) IsHealthMonitorEnabled() bool {
+	return s.HealthMonitor != nil && s.HealthMonitor.Enabled
+}
+
+// IsNodeTaintEnabled returns true if the nodes with RBLN devices are tainted for NPU workloads only
+func (s RBLNClusterPolicySpec) IsNodeTaintEnabled() bool {
+	return s.NodeTaint != nil && s.NodeTaint.Enabled
+}
+
+// IsUnhealthyNodeTaintEnabled returns true if nodes with an unhealthy NPU are tainted
+func (s RBLNClusterPolicySpec) IsUnhealthyNodeTaintEnabled() bool {
+	return s.IsHealthMonitorEnabled() && s.HealthMonitor.TaintUnhealthyNodes
+}
+
+// IsRemediationEnabled returns true if unhealthy nodes are remediated automatically
+func (s RBLNClusterPolicySpec) IsRemediationEnabled() bool {
+	return s.IsHealthMonitorEnabled() && s.Remediation != nil && s.Remediation.Enabled
+}
+
+// IsOpenShiftMirrorSetsEnabled returns true if the OpenShift image mirror sets are applied
+func (s *ImageMirrorsSpec) IsOpenShiftMirrorSetsEnabled() bool {
+	return s == nil || s.UseOpenShiftMirrorSets == nil || *s.UseOpenShiftMirrorSets
+}
+
+// GetSecurityMode returns the protection mode of the metrics endpoint
+func (s RBLNMetricsExporterSpec) GetSecurityMode() string {
+	if s.Security == nil || s.Security.Mode == "" {
+		return MetricsSecurityModeNone
+	}
+	return s.Security.Mode
+}
+
 type ClusterState string
 
 type NodeState string
 
 type ComponentState string
 
+// ManagementState is how the operator manages the resources of a component
+// +kubebuilder:validation:Enum=Managed;Unmanaged;Removed
+type ManagementState string
+
+const (
+	// ManagementStateManaged reconciles the resources of the component to its spec
+	ManagementStateManaged ManagementState = "Managed"
+	// ManagementStateUnmanaged leaves the existing resources untouched but still reports their status
+	ManagementStateUnmanaged ManagementState = "Unmanaged"
+	// ManagementStateRemoved deletes the resources of the component
+	ManagementStateRemoved ManagementState = "Removed"
+)
+
 const (
 	// Ready indicates RBLNClusterPolicy are ready
 	ClusterReady ClusterState = "ready"
@@ -601,10 +1354,11 @@ const (
 )
 
 type RBLNComponentStatus struct {
-	Name      string             `json:"name"`
-	Namespace string             `json:"namespace"`
-	State     ComponentState     `json:"state"`
-	Condition []metav1.Condition `json:"condition,omitempty"`
+	Name            string             `json:"name"`
+	Namespace       string             `json:"namespace"`
+	State           ComponentState     `json:"state"`
+	ManagementState ManagementState    `json:"managementState,omitempty"`
+	Condition       []metav1.Condition `json:"condition,omitempty"`
 }
 
 // RBLNClusterPolicyStatus defines the observed state of RBLNClusterPolicy
@@ -619,6 +1373,75 @@ type RBLNClusterPolicyStatus struct {
 	// Conditions is a list of conditions representing the RBLNClusterPolicy's current state
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Remediations is the remediation state and history of nodes with an unhealthy NPU
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Remediations",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Remediations []NodeRemediationStatus `json:"remediations,omitempty"`
+	// Images is the effective image of every managed container, after image mirrors are applied
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Images",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Images []ContainerImage `json:"images,omitempty"`
+	// GrafanaDashboard is the Grafana dashboard deployed for the metrics exporter, so that it is
+	// deleted once the dashboard moves to another namespace or kind, or is disabled
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Grafana Dashboard",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	GrafanaDashboard *GrafanaDashboardReference `json:"grafanaDashboard,omitempty"`
+}
+
+// GrafanaDashboardReference locates the deployed Grafana dashboard
+type GrafanaDashboardReference struct {
+	// Kind is ConfigMap or GrafanaDashboard
+	Kind string `json:"kind"`
+	// Namespace of the dashboard
+	Namespace string `json:"namespace"`
+	// Name of the dashboard
+	Name string `json:"name"`
+}
+
+// ContainerImage is the image a container of a managed workload runs
+type ContainerImage struct {
+	// Workload is the kind, namespace and name of the workload, e.g. DaemonSet/rbln-system/rbln-device-plugin
+	Workload string `json:"workload"`
+	// Container name
+	Container string `json:"container"`
+	// Image reference
+	Image string `json:"image"`
+}
+
+// RemediationPhase is the state of the remediation of a node
+type RemediationPhase string
+
+const (
+	// RemediationInProgress indicates actions are being escalated on the node
+	RemediationInProgress RemediationPhase = "InProgress"
+	// RemediationExhausted indicates every action ran and the node still is unhealthy
+	RemediationExhausted RemediationPhase = "Exhausted"
+	// RemediationSucceeded indicates the node recovered after a remediation
+	RemediationSucceeded RemediationPhase = "Succeeded"
+)
+
+// NodeRemediationStatus is the remediation state of a node
+type NodeRemediationStatus struct {
+	// NodeName of the remediated node
+	NodeName string `json:"nodeName"`
+	// Phase of the remediation
+	Phase RemediationPhase `json:"phase"`
+	// Step is the index of the next action to run
+	Step int32 `json:"step"`
+	// LastActionTime is when the last action ran
+	// +optional
+	LastActionTime *metav1.Time `json:"lastActionTime,omitempty"`
+	// History of the actions run on the node, oldest first
+	// +optional
+	History []RemediationEvent `json:"history,omitempty"`
+}
+
+// RemediationEvent records an action run on a node
+type RemediationEvent struct {
+	// Action that ran, or "recovered" once the node reported healthy again
+	Action string `json:"action"`
+	// Time the action ran
+	Time metav1.Time `json:"time"`
+	// Message describes the outcome of the action
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
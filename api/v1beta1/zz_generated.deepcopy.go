@@ -22,10 +22,41 @@ package v1beta1
 
 import (
 	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerSpec) DeepCopyInto(out *CertManagerIssuerSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerSpec.
+func (in *CertManagerIssuerSpec) DeepCopy() *CertManagerIssuerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerImage) DeepCopyInto(out *ContainerImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerImage.
+func (in *ContainerImage) DeepCopy() *ContainerImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DaemonsetsSpec) DeepCopyInto(out *DaemonsetsSpec) {
 	*out = *in
@@ -89,6 +120,150 @@ func (in *DriverValidatorSpec) DeepCopy() *DriverValidatorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardReference) DeepCopyInto(out *GrafanaDashboardReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardReference.
+func (in *GrafanaDashboardReference) DeepCopy() *GrafanaDashboardReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardSpec) DeepCopyInto(out *GrafanaDashboardSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardSpec.
+func (in *GrafanaDashboardSpec) DeepCopy() *GrafanaDashboardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageMirror) DeepCopyInto(out *ImageMirror) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMirror.
+func (in *ImageMirror) DeepCopy() *ImageMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageMirrorsSpec) DeepCopyInto(out *ImageMirrorsSpec) {
+	*out = *in
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]ImageMirror, len(*in))
+		copy(*out, *in)
+	}
+	if in.UseOpenShiftMirrorSets != nil {
+		in, out := &in.UseOpenShiftMirrorSets, &out.UseOpenShiftMirrorSets
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMirrorsSpec.
+func (in *ImageMirrorsSpec) DeepCopy() *ImageMirrorsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageMirrorsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsExporterSecuritySpec) DeepCopyInto(out *MetricsExporterSecuritySpec) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSCertificateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.RBACProxy.DeepCopyInto(&out.RBACProxy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsExporterSecuritySpec.
+func (in *MetricsExporterSecuritySpec) DeepCopy() *MetricsExporterSecuritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsExporterSecuritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRemediationStatus) DeepCopyInto(out *NodeRemediationStatus) {
+	*out = *in
+	if in.LastActionTime != nil {
+		in, out := &in.LastActionTime, &out.LastActionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]RemediationEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRemediationStatus.
+func (in *NodeRemediationStatus) DeepCopy() *NodeRemediationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeTaintSpec) DeepCopyInto(out *NodeTaintSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTaintSpec.
+func (in *NodeTaintSpec) DeepCopy() *NodeTaintSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeTaintSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PluginValidatorSpec) DeepCopyInto(out *PluginValidatorSpec) {
 	*out = *in
@@ -158,6 +333,59 @@ func (in *PodSpec) DeepCopy() *PodSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRuleSpec) DeepCopyInto(out *PrometheusRuleSpec) {
+	*out = *in
+	if in.AdditionalLabels != nil {
+		in, out := &in.AdditionalLabels, &out.AdditionalLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRuleSpec.
+func (in *PrometheusRuleSpec) DeepCopy() *PrometheusRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxySpec) DeepCopyInto(out *ProxySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySpec.
+func (in *ProxySpec) DeepCopy() *ProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACProxySpec) DeepCopyInto(out *RBACProxySpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACProxySpec.
+func (in *RBACProxySpec) DeepCopy() *RBACProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RBLNClusterPolicy) DeepCopyInto(out *RBLNClusterPolicy) {
 	*out = *in
@@ -233,6 +461,36 @@ func (in *RBLNClusterPolicySpec) DeepCopyInto(out *RBLNClusterPolicySpec) {
 	in.NPUFeatureDiscovery.DeepCopyInto(&out.NPUFeatureDiscovery)
 	in.ContainerToolkit.DeepCopyInto(&out.ContainerToolkit)
 	in.Validator.DeepCopyInto(&out.Validator)
+	if in.HealthMonitor != nil {
+		in, out := &in.HealthMonitor, &out.HealthMonitor
+		*out = new(RBLNHealthMonitorSpec)
+		**out = **in
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(RemediationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageMirrors != nil {
+		in, out := &in.ImageMirrors, &out.ImageMirrors
+		*out = new(ImageMirrorsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxySpec)
+		**out = **in
+	}
+	if in.TrustedCA != nil {
+		in, out := &in.TrustedCA, &out.TrustedCA
+		*out = new(TrustedCASpec)
+		**out = **in
+	}
+	if in.NodeTaint != nil {
+		in, out := &in.NodeTaint, &out.NodeTaint
+		*out = new(NodeTaintSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNClusterPolicySpec.
@@ -262,6 +520,23 @@ func (in *RBLNClusterPolicyStatus) DeepCopyInto(out *RBLNClusterPolicyStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Remediations != nil {
+		in, out := &in.Remediations, &out.Remediations
+		*out = make([]NodeRemediationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ContainerImage, len(*in))
+		copy(*out, *in)
+	}
+	if in.GrafanaDashboard != nil {
+		in, out := &in.GrafanaDashboard, &out.GrafanaDashboard
+		*out = new(GrafanaDashboardReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNClusterPolicyStatus.
@@ -312,6 +587,11 @@ func (in *RBLNContainerToolkitSpec) DeepCopyInto(out *RBLNContainerToolkitSpec)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RuntimeClass != nil {
+		in, out := &in.RuntimeClass, &out.RuntimeClass
+		*out = new(RuntimeClassSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNContainerToolkitSpec.
@@ -324,6 +604,28 @@ func (in *RBLNContainerToolkitSpec) DeepCopy() *RBLNContainerToolkitSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBLNDaemonNetworkPolicySpec) DeepCopyInto(out *RBLNDaemonNetworkPolicySpec) {
+	*out = *in
+	if in.AdditionalClients != nil {
+		in, out := &in.AdditionalClients, &out.AdditionalClients
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNDaemonNetworkPolicySpec.
+func (in *RBLNDaemonNetworkPolicySpec) DeepCopy() *RBLNDaemonNetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RBLNDaemonNetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RBLNDaemonSpec) DeepCopyInto(out *RBLNDaemonSpec) {
 	*out = *in
@@ -340,6 +642,16 @@ func (in *RBLNDaemonSpec) DeepCopyInto(out *RBLNDaemonSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(RBLNDaemonTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(RBLNDaemonNetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNDaemonSpec.
@@ -352,6 +664,23 @@ func (in *RBLNDaemonSpec) DeepCopy() *RBLNDaemonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBLNDaemonTLSSpec) DeepCopyInto(out *RBLNDaemonTLSSpec) {
+	*out = *in
+	in.ServerCertificate.DeepCopyInto(&out.ServerCertificate)
+	in.ClientCertificate.DeepCopyInto(&out.ClientCertificate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNDaemonTLSSpec.
+func (in *RBLNDaemonTLSSpec) DeepCopy() *RBLNDaemonTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RBLNDaemonTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RBLNDevicePluginResourceSpec) DeepCopyInto(out *RBLNDevicePluginResourceSpec) {
 	*out = *in
@@ -395,10 +724,45 @@ func (in *RBLNDevicePluginSpec) DeepCopy() *RBLNDevicePluginSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBLNHealthMonitorSpec) DeepCopyInto(out *RBLNHealthMonitorSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNHealthMonitorSpec.
+func (in *RBLNHealthMonitorSpec) DeepCopy() *RBLNHealthMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RBLNHealthMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RBLNMetricsExporterSpec) DeepCopyInto(out *RBLNMetricsExporterSpec) {
 	*out = *in
 	in.PodSpec.DeepCopyInto(&out.PodSpec)
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(ServiceMonitorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusRule != nil {
+		in, out := &in.PrometheusRule, &out.PrometheusRule
+		*out = new(PrometheusRuleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(MetricsExporterSecuritySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaDashboard != nil {
+		in, out := &in.GrafanaDashboard, &out.GrafanaDashboard
+		*out = new(GrafanaDashboardSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBLNMetricsExporterSpec.
@@ -467,6 +831,126 @@ func (in *RBLNVFIOManagerSpec) DeepCopy() *RBLNVFIOManagerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
+	*out = *in
+	if in.SourceLabels != nil {
+		in, out := &in.SourceLabels, &out.SourceLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelabelConfig.
+func (in *RelabelConfig) DeepCopy() *RelabelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RelabelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationEvent) DeepCopyInto(out *RemediationEvent) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationEvent.
+func (in *RemediationEvent) DeepCopy() *RemediationEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationSpec) DeepCopyInto(out *RemediationSpec) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]RemediationAction, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationSpec.
+func (in *RemediationSpec) DeepCopy() *RemediationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeClassSpec) DeepCopyInto(out *RuntimeClassSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeClassSpec.
+func (in *RuntimeClassSpec) DeepCopy() *RuntimeClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMonitorSpec) DeepCopyInto(out *ServiceMonitorSpec) {
+	*out = *in
+	if in.AdditionalLabels != nil {
+		in, out := &in.AdditionalLabels, &out.AdditionalLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Relabelings != nil {
+		in, out := &in.Relabelings, &out.Relabelings
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMonitorSpec.
+func (in *ServiceMonitorSpec) DeepCopy() *ServiceMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertificateSpec) DeepCopyInto(out *TLSCertificateSpec) {
+	*out = *in
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerIssuerSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertificateSpec.
+func (in *TLSCertificateSpec) DeepCopy() *TLSCertificateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertificateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ToolkitValidatorSpec) DeepCopyInto(out *ToolkitValidatorSpec) {
 	*out = *in
@@ -489,6 +973,21 @@ func (in *ToolkitValidatorSpec) DeepCopy() *ToolkitValidatorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedCASpec) DeepCopyInto(out *TrustedCASpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedCASpec.
+func (in *TrustedCASpec) DeepCopy() *TrustedCASpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedCASpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VFIOCheckerSpec) DeepCopyInto(out *VFIOCheckerSpec) {
 	*out = *in
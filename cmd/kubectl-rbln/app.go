@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/mustgather"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(rblnv1beta1.AddToScheme(scheme))
+	utilruntime.Must(rebellionsaiv1alpha1.AddToScheme(scheme))
+}
+
+// NewKubectlRBLNApp returns the kubectl rbln plugin, run by kubectl as kubectl-rbln.
+func NewKubectlRBLNApp() *cobra.Command {
+	configFlags := genericclioptions.NewConfigFlags(true)
+
+	cmd := &cobra.Command{
+		Use:           "kubectl-rbln",
+		Short:         "Show the state of the RBLN NPU fleet.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Annotations: map[string]string{
+			cobra.CommandDisplayNameAnnotation: "kubectl rbln",
+		},
+		CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newStatusCommand(configFlags),
+		newNodesCommand(configFlags),
+		newDriversCommand(configFlags),
+	)
+
+	configFlags.AddFlags(cmd.PersistentFlags())
+
+	return cmd
+}
+
+func newClient(configFlags *genericclioptions.ConfigFlags) (client.Client, error) {
+	cfg, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return c, nil
+}
+
+// operatorNamespace returns the namespace given with --namespace, or the one the operator runs in.
+func operatorNamespace(ctx context.Context, c client.Reader, configFlags *genericclioptions.ConfigFlags) (string, error) {
+	if configFlags.Namespace != nil && *configFlags.Namespace != "" {
+		return *configFlags.Namespace, nil
+	}
+	return mustgather.DetectNamespace(ctx, c)
+}
+
+// printRow prints a row of tab separated columns, showing empty columns as <none> like kubectl get.
+func printRow(w io.Writer, columns ...string) {
+	for i, column := range columns {
+		if column == "" {
+			columns[i] = "<none>"
+		}
+	}
+	fmt.Fprintln(w, strings.Join(columns, "\t"))
+}
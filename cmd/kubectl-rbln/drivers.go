@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/fleet"
+)
+
+func newDriversCommand(configFlags *genericclioptions.ConfigFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "drivers",
+		Short: "Show the RBLNDrivers with the DaemonSets and images of their node pools",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient(configFlags)
+			if err != nil {
+				return err
+			}
+			drivers, err := fleet.GetDriverStatuses(cmd.Context(), c)
+			if err != nil {
+				return err
+			}
+
+			w := printers.GetNewTabWriter(cmd.OutOrStdout())
+			printRow(w, "DRIVER", "MANAGEMENT", "STATE", "POOL", "DESIRED", "READY", "UP-TO-DATE", "IMAGES", "MESSAGE")
+			for _, driver := range drivers {
+				// drivers whose pools are all skipped still get a row for their message
+				if len(driver.Pools) == 0 {
+					printRow(w, driver.Name, driver.ManagementState, driver.State, "", "0", "0", "0", "", driver.Message)
+				}
+				for i, pool := range driver.Pools {
+					message := ""
+					if i == 0 {
+						message = driver.Message
+					}
+					printRow(w, driver.Name, driver.ManagementState, driver.State, pool.Name,
+						strconv.Itoa(int(pool.Desired)), strconv.Itoa(int(pool.Ready)), strconv.Itoa(int(pool.UpToDate)),
+						strings.Join(pool.Images, ","), message)
+				}
+			}
+			return w.Flush()
+		},
+	}
+}
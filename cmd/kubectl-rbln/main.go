@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	// to authenticate with the same kubeconfig as kubectl.
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+)
+
+func main() {
+	app := NewKubectlRBLNApp()
+	if err := app.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/fleet"
+)
+
+func newNodesCommand(configFlags *genericclioptions.ConfigFlags) *cobra.Command {
+	var unhealthy bool
+
+	cmd := &cobra.Command{
+		Use:   "nodes",
+		Short: "Show the workload config, NPU resources, driver pool and health of NPU nodes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient(configFlags)
+			if err != nil {
+				return err
+			}
+			namespace, err := operatorNamespace(cmd.Context(), c, configFlags)
+			if err != nil {
+				return err
+			}
+			nodes, err := fleet.GetNodeStatuses(cmd.Context(), c, namespace)
+			if err != nil {
+				return err
+			}
+
+			w := printers.GetNewTabWriter(cmd.OutOrStdout())
+			printRow(w, "NAME", "PRESENT", "WORKLOAD", "RESOURCES", "DRIVER-POOL", "HEALTH", "ISSUES")
+			for _, node := range nodes {
+				if unhealthy && node.Healthy() {
+					continue
+				}
+				printRow(w, node.Name, strconv.FormatBool(node.Present), node.WorkloadConfig,
+					strings.Join(node.Resources, ","), node.DriverPool, node.Health, strings.Join(node.Issues, "; "))
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().BoolVar(&unhealthy, "unhealthy", false, "only show nodes with an unhealthy NPU or another issue")
+
+	return cmd
+}
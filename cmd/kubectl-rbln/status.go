@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/fleet"
+)
+
+func newStatusCommand(configFlags *genericclioptions.ConfigFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the state of the RBLNClusterPolicy and the readiness of its components",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient(configFlags)
+			if err != nil {
+				return err
+			}
+			status, err := fleet.GetClusterStatus(cmd.Context(), c)
+			if err != nil {
+				return err
+			}
+			if status == nil {
+				return fmt.Errorf("no RBLNClusterPolicy found")
+			}
+
+			w := printers.GetNewTabWriter(cmd.OutOrStdout())
+			printRow(w, "NAME", "STATE", "READY", "PAUSED", "MESSAGE")
+			printRow(w, status.Name, status.State, status.Ready, strconv.FormatBool(status.Paused), status.Message)
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout())
+			printRow(w, "COMPONENT", "NAMESPACE", "MANAGEMENT", "STATE", "MESSAGE")
+			for _, component := range status.Components {
+				printRow(w, component.Name, component.Namespace, component.ManagementState, component.State, component.Message)
+			}
+			return w.Flush()
+		},
+	}
+}
@@ -18,18 +18,22 @@ package main
 
 import (
 	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -47,6 +51,12 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+// subcommands run instead of the manager when named by the first argument.
+var subcommands = map[string]func(args []string) error{
+	"render":      runRender,
+	"must-gather": runMustGather,
+}
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
@@ -56,6 +66,20 @@ func init() {
 }
 
 func main() {
+	// the image runs must-gather as /usr/bin/gather, the entrypoint of oc adm must-gather
+	if filepath.Base(os.Args[0]) == "gather" {
+		os.Args = append([]string{os.Args[0], "must-gather", "--dest-dir", mustGatherDir}, os.Args[1:]...)
+	}
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil && !errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
@@ -130,6 +154,13 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "91ec02eb.rebellions.ai",
+		// Secrets are read from the API server, so that the operator neither caches every Secret
+		// of the cluster nor needs to list and watch them; it is only granted access in its namespace.
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: []client.Object{&corev1.Secret{}},
+			},
+		},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -155,6 +186,11 @@ func main() {
 	}
 	setupLog.Info(fmt.Sprintf("openshift version: %s", clusterInfo.OpenshiftVersion))
 
+	if err := controller.IndexPodNodeName(ctx, mgr.GetFieldIndexer()); err != nil {
+		setupLog.Error(err, "unable to index pods by node")
+		os.Exit(1)
+	}
+
 	if err = (&controller.RBLNClusterPolicyReconciler{
 		Client:      mgr.GetClient(),
 		Log:         ctrl.Log.WithName("controllers").WithName("RBLNClusterPolicy"),
@@ -173,6 +209,31 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "RBLNDriver")
 		os.Exit(1)
 	}
+	if err = (&controller.NodeHealthReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("NodeHealth"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NodeHealth")
+		os.Exit(1)
+	}
+	if err = (&controller.RBLNFirmwareReconciler{
+		Client:      mgr.GetClient(),
+		Log:         ctrl.Log.WithName("controllers").WithName("RBLNFirmware"),
+		Scheme:      mgr.GetScheme(),
+		ClusterInfo: clusterInfo,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RBLNFirmware")
+		os.Exit(1)
+	}
+	if err = (&controller.RBLNUsageReportReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("RBLNUsageReport"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RBLNUsageReport")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
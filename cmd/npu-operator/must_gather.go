@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/mustgather"
+)
+
+// mustGatherDir is the directory oc adm must-gather copies back from the gather pod.
+const mustGatherDir = "/must-gather"
+
+// runMustGather collects a support bundle of the operator into a tarball or a directory.
+func runMustGather(args []string) error {
+	var (
+		kubeconfig    string
+		namespace     string
+		output        string
+		destDir       string
+		logLimitBytes int64
+	)
+	fs := flag.NewFlagSet("must-gather", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: npu-operator must-gather [flags]\n\n"+
+			"Collect the operator resources, operand workloads, logs, NPU nodes, events and validation status files.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig.")
+	fs.StringVar(&namespace, "namespace", os.Getenv("OPERATOR_NAMESPACE"), "Namespace of the operator. Detected from the RBLNClusterPolicy or the operator Deployment if empty.")
+	fs.StringVar(&output, "o", "", "Path of the tarball; - writes to stdout. Defaults to must-gather-<timestamp>.tar.gz.")
+	fs.StringVar(&destDir, "dest-dir", "", "Write the bundle into this directory instead of a tarball.")
+	fs.Int64Var(&logLimitBytes, "log-limit-bytes", mustgather.DefaultLogLimitBytes, "Maximum bytes of log collected per container.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctrl.SetLogger(zap.New(zap.WriteTo(os.Stderr)))
+	cfg, err := loadConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	pods, err := mustgather.NewPodReader(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+	if namespace == "" {
+		if namespace, err = mustgather.DetectNamespace(ctx, c); err != nil {
+			return err
+		}
+	}
+
+	var sink mustgather.Sink
+	switch {
+	case destDir != "":
+		sink = mustgather.NewDirSink(destDir)
+		output = destDir
+	case output == "-":
+		sink = mustgather.NewTarSink(os.Stdout)
+	default:
+		if output == "" {
+			output = fmt.Sprintf("must-gather-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+		}
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		sink = mustgather.NewTarSink(f)
+	}
+
+	if err := mustgather.Gather(ctx, c, pods, scheme, sink, mustgather.Options{
+		Namespace:     namespace,
+		LogLimitBytes: logLimitBytes,
+	}); err != nil {
+		return err
+	}
+	if output != "-" {
+		fmt.Fprintf(os.Stderr, "must-gather written to %s\n", output)
+	}
+	return nil
+}
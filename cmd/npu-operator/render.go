@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/controller"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/render"
+)
+
+// stringsFlag is a flag that may be repeated.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// runRender prints the manifests the operator would apply, without writing to the cluster.
+func runRender(args []string) error {
+	var (
+		files            stringsFlag
+		apiVersions      stringsFlag
+		live             bool
+		kubeconfig       string
+		namespace        string
+		openshiftVersion string
+		containerRuntime string
+	)
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: npu-operator render [flags]\n\n"+
+			"Print the manifests the operator would apply for a RBLNClusterPolicy, its RBLNDrivers and RBLNFirmwares.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Var(&files, "f", "YAML file with the RBLNClusterPolicy, RBLNDrivers, RBLNFirmwares, Nodes and referenced objects; - reads stdin. May be repeated.")
+	fs.Var(&apiVersions, "api-versions", "Optional kind served by the cluster as group/version/kind, e.g. monitoring.coreos.com/v1/ServiceMonitor. May be repeated.")
+	fs.BoolVar(&live, "live", false, "Read the cluster for objects not given with -f. Nothing is written to the cluster.")
+	fs.StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig used with --live.")
+	fs.StringVar(&namespace, "namespace", os.Getenv("OPERATOR_NAMESPACE"), "Namespace of the operator, unless set in the RBLNClusterPolicy.")
+	fs.StringVar(&openshiftVersion, "openshift-version", "", "OpenShift version of the cluster, e.g. 4.14. Detected with --live.")
+	fs.StringVar(&containerRuntime, "container-runtime", consts.Containerd, "Runtime of nodes whose container runtime cannot be detected. Detected with --live.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(files) == 0 && !live {
+		return fmt.Errorf("either -f or --live is required")
+	}
+
+	log := zap.New(zap.WriteTo(os.Stderr))
+	ctrl.SetLogger(log)
+	if namespace != "" {
+		// the scopes read the operator namespace from the environment
+		if err := os.Setenv("OPERATOR_NAMESPACE", namespace); err != nil {
+			return err
+		}
+	}
+
+	var inputs []client.Object
+	for _, file := range files {
+		objs, err := decodeFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		inputs = append(inputs, objs...)
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+	opts := render.Options{
+		OpenshiftVersion: openshiftVersion,
+		ContainerRuntime: containerRuntime,
+	}
+	var recorder *render.Recorder
+	if live {
+		var err error
+		recorder, opts, err = newLiveRecorder(ctx, kubeconfig)
+		if err != nil {
+			return err
+		}
+	} else {
+		mapper, err := render.NewRESTMapper(scheme, apiVersions)
+		if err != nil {
+			return err
+		}
+		recorder = render.NewRecorder(nil, scheme, mapper)
+	}
+	if err := recorder.Seed(inputs...); err != nil {
+		return err
+	}
+
+	objs, err := render.Render(ctx, recorder, log.WithName("render"), opts)
+	if err != nil {
+		return err
+	}
+	return render.Encode(os.Stdout, objs)
+}
+
+// newLiveRecorder returns a recorder reading the cluster, and the options detected from it.
+func newLiveRecorder(ctx context.Context, kubeconfig string) (*render.Recorder, render.Options, error) {
+	cfg, err := loadConfig(kubeconfig)
+	if err != nil {
+		return nil, render.Options{}, err
+	}
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, render.Options{}, fmt.Errorf("failed to create client: %w", err)
+	}
+	clusterInfo, err := controller.NewClusterInfo(ctx, cfg)
+	if err != nil {
+		return nil, render.Options{}, fmt.Errorf("failed to get cluster information: %w", err)
+	}
+	opts := render.Options{
+		OpenshiftVersion: clusterInfo.OpenshiftVersion,
+		ContainerRuntime: clusterInfo.ContainerRuntime,
+	}
+	return render.NewRecorder(c, scheme, c.RESTMapper()), opts, nil
+}
+
+// loadConfig loads kubeconfig, or the default config of controller-runtime if empty.
+func loadConfig(kubeconfig string) (*rest.Config, error) {
+	var (
+		cfg *rest.Config
+		err error
+	)
+	if kubeconfig != "" {
+		cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	} else {
+		cfg, err = ctrl.GetConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return cfg, nil
+}
+
+func decodeFile(file string) ([]client.Object, error) {
+	var in io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+	return render.Decode(in, scheme)
+}
@@ -18,6 +18,9 @@ func NewRBLNValidatorApp() *cobra.Command {
 	cmd.AddCommand(
 		newDriverCommand(builder),
 		newToolkitCommand(builder),
+		newHealthCommand(builder),
+		newFirmwareCommand(builder),
+		newResetDevicesCommand(),
 	)
 
 	builder.bindFlags(cmd)
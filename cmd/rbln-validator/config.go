@@ -18,6 +18,7 @@ const (
 	envNamespace            = "OPERATOR_NAMESPACE"
 	envWithWait             = "WITH_WAIT"
 	envSleepIntervalSeconds = "SLEEP_INTERVAL_SECONDS"
+	envDeviceListStrategy   = "DEVICE_LIST_STRATEGY"
 )
 
 type config struct {
@@ -25,6 +26,7 @@ type config struct {
 	outputDir            string
 	withWait             bool
 	sleepIntervalSeconds int
+	deviceListStrategy   string
 }
 
 type configBuilder struct {
@@ -32,6 +34,7 @@ type configBuilder struct {
 	outputDir            string
 	withWait             bool
 	sleepIntervalSeconds int
+	deviceListStrategy   string
 }
 
 func newConfigBuilder() *configBuilder {
@@ -40,6 +43,7 @@ func newConfigBuilder() *configBuilder {
 		outputDir:            envString(envOutputDir, defaultOutputDir),
 		withWait:             envBool(envWithWait, false),
 		sleepIntervalSeconds: envInt(envSleepIntervalSeconds, defaultSleepIntervalSeconds),
+		deviceListStrategy:   envString(envDeviceListStrategy, ""),
 	}
 }
 
@@ -54,6 +58,12 @@ func (b *configBuilder) bindFlags(cmd *cobra.Command) {
 		b.sleepIntervalSeconds,
 		"sleep interval in seconds between retries",
 	)
+	flags.StringVar(
+		&b.deviceListStrategy,
+		"device-list-strategy",
+		b.deviceListStrategy,
+		"how devices are passed to containers (cdi-annotations, envvar)",
+	)
 }
 
 func (b *configBuilder) finalize() (*config, error) {
@@ -74,6 +84,7 @@ func (b *configBuilder) finalize() (*config, error) {
 		outputDir:            outputDir,
 		withWait:             b.withWait,
 		sleepIntervalSeconds: b.sleepIntervalSeconds,
+		deviceListStrategy:   strings.TrimSpace(b.deviceListStrategy),
 	}, nil
 }
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"path/filepath"
 
@@ -46,10 +47,14 @@ func validateDriver(ctx context.Context, cfg *config) error {
 		ctx:                  ctx,
 	}
 	driverInfo, err := driver.runValidation(false)
+	sig, sigErr := verifyModuleSignature()
+	err = errors.Join(err, sigErr)
 	if err != nil {
 		slog.Error("driver is not ready", "err", err)
 		return err
 	}
+	driverInfo.secureBoot = sig.secureBoot
+	driverInfo.moduleVerified = sig.verified
 	slog.Info("driver validation completed", "hostDriver", driverInfo.isHostDriver)
 
 	return driver.createStatusFile(driverInfo)
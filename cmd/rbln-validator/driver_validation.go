@@ -45,6 +45,8 @@ type driverInfo struct {
 	driverRoot           string
 	driverRootCtrPath    string
 	containerLibraryPath string
+	secureBoot           bool
+	moduleVerified       bool
 }
 
 func (d *Driver) runValidation(silent bool) (driverInfo, error) {
@@ -276,6 +278,8 @@ func (d *Driver) createStatusFile(info driverInfo) error {
 		fmt.Sprintf("RBLN_CTK_DAEMON_HOST_ROOT=%s", info.driverRootCtrPath),
 		fmt.Sprintf("RBLN_CTK_DAEMON_DRIVER_ROOT=%s", info.driverRoot),
 		fmt.Sprintf("RBLN_CTK_DAEMON_CONTAINER_LIBRARY_PATH=%s", info.containerLibraryPath),
+		fmt.Sprintf("SECURE_BOOT=%t", info.secureBoot),
+		fmt.Sprintf("MODULE_SIGNATURE_VERIFIED=%t", !info.secureBoot || info.moduleVerified),
 	}, "\n") + "\n"
 
 	return createStatusFileWithContent(filepath.Join(d.outputDir, driverReadyFile), statusFileContent)
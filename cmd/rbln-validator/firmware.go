@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/firmware"
+)
+
+func newFirmwareCommand(builder *configBuilder) *cobra.Command {
+	return &cobra.Command{
+		Use:   "firmware",
+		Short: "Report the NPU firmware inventory on the node",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := builder.finalize()
+			if err != nil {
+				return err
+			}
+			return watchFirmware(cmd.Context(), cfg)
+		},
+	}
+}
+
+func watchFirmware(ctx context.Context, cfg *config) error {
+	nodeName := envString(envNodeName, "")
+	if nodeName == "" {
+		return fmt.Errorf("%s must be set", envNodeName)
+	}
+
+	kubeConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return fmt.Errorf("error getting cluster config: %w", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return fmt.Errorf("error getting k8s client: %w", err)
+	}
+
+	interval := time.Duration(cfg.sleepIntervalSeconds) * time.Second
+	slog.Info("starting NPU firmware inventory", "node", nodeName, "interval", interval)
+
+	var last *firmware.Inventory
+	for {
+		inventory, err := firmware.Scan(hostPCIDevicesPath, time.Now().UTC())
+		if err != nil {
+			slog.Error("NPU firmware inventory failed", "err", err)
+		} else if last == nil || !last.Equal(inventory) {
+			if err := publishFirmwareInventory(ctx, kubeClient, nodeName, inventory); err != nil {
+				slog.Error("failed to publish NPU firmware inventory", "err", err)
+			} else {
+				slog.Info("published NPU firmware inventory", "cards", len(inventory.Cards))
+				last = &inventory
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func publishFirmwareInventory(ctx context.Context, kubeClient kubernetes.Interface, nodeName string, inventory firmware.Inventory) error {
+	value, err := inventory.Encode()
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{firmware.AnnotationKey: value},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("build node patch: %w", err)
+	}
+	if _, err := kubeClient.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("patch node %s: %w", nodeName, err)
+	}
+	return nil
+}
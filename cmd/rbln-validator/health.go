@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/npuhealth"
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+const (
+	envNodeName        = "NODE_NAME"
+	hostSysMountPath   = "/host-sys"
+	hostPCIDevicesPath = hostSysMountPath + "/bus/pci/devices"
+)
+
+func newHealthCommand(builder *configBuilder) *cobra.Command {
+	return &cobra.Command{
+		Use:   "health",
+		Short: "Watch NPU health and report it on the node",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := builder.finalize()
+			if err != nil {
+				return err
+			}
+			return watchHealth(cmd.Context(), cfg)
+		},
+	}
+}
+
+func watchHealth(ctx context.Context, cfg *config) error {
+	nodeName := envString(envNodeName, "")
+	if nodeName == "" {
+		return fmt.Errorf("%s must be set", envNodeName)
+	}
+
+	kubeConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return fmt.Errorf("error getting cluster config: %w", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return fmt.Errorf("error getting k8s client: %w", err)
+	}
+
+	watcher := &healthWatcher{
+		nodeName:   nodeName,
+		kubeClient: kubeClient,
+	}
+	interval := time.Duration(cfg.sleepIntervalSeconds) * time.Second
+	slog.Info("starting NPU health watcher", "node", nodeName, "interval", interval)
+
+	for {
+		if err := watcher.check(ctx); err != nil {
+			slog.Error("NPU health check failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+type healthWatcher struct {
+	nodeName   string
+	kubeClient kubernetes.Interface
+	// previous is the last evaluated report; its expected devices and fatal error baselines
+	// are restored from the node annotation when the watcher starts
+	previous *npuhealth.Report
+	last     *npuhealth.Report
+}
+
+func (w *healthWatcher) check(ctx context.Context) error {
+	if w.previous == nil {
+		if err := w.restore(ctx); err != nil {
+			return err
+		}
+	}
+	devices, err := npuhealth.ScanPCIDevices(hostPCIDevicesPath)
+	if err != nil {
+		return err
+	}
+
+	report := npuhealth.Evaluate(*w.previous, devices, consts.RBLNDriverName, time.Now().UTC())
+	w.previous = &report
+	if report.Healthy && len(devices) > 0 {
+		if err := checkSMI(); err != nil {
+			report.Healthy = false
+			report.UnhealthyDevices = append(report.UnhealthyDevices, npuhealth.DeviceStatus{
+				Card:    "all",
+				Reason:  npuhealth.ReasonSMIFailed,
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if err := w.handleResetRequest(ctx, report); err != nil {
+		slog.Error("NPU reset failed", "err", err)
+	}
+
+	// republish an unchanged report as a heartbeat, so the operator notices a watcher that is gone
+	if w.last != nil && w.last.Equal(report) && report.Timestamp.Sub(w.last.Timestamp) < npuhealth.HeartbeatInterval {
+		return nil
+	}
+	value, err := report.Encode()
+	if err != nil {
+		return err
+	}
+	if err := w.annotate(ctx, map[string]string{npuhealth.AnnotationKey: value}); err != nil {
+		return err
+	}
+	slog.Info("published NPU health", "healthy", report.Healthy, "summary", report.Summary())
+	w.last = &report
+	return nil
+}
+
+// restore continues from the report published before the watcher restarted.
+func (w *healthWatcher) restore(ctx context.Context) error {
+	node, err := w.kubeClient.CoreV1().Nodes().Get(ctx, w.nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get node %s: %w", w.nodeName, err)
+	}
+	w.previous = &npuhealth.Report{}
+	if value, ok := node.Annotations[npuhealth.AnnotationKey]; ok {
+		report, err := npuhealth.Decode(value)
+		if err != nil {
+			slog.Error("ignoring invalid NPU health report", "err", err)
+			return nil
+		}
+		w.previous = &report
+	}
+	return nil
+}
+
+// handleResetRequest resets the unhealthy devices once per reset requested by the operator's remediation.
+// An admission policy only admits reset requests written by the operator; the reset waits until the
+// node is cordoned and drained, so no workload uses the devices.
+func (w *healthWatcher) handleResetRequest(ctx context.Context, report npuhealth.Report) error {
+	node, err := w.kubeClient.CoreV1().Nodes().Get(ctx, w.nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get node %s: %w", w.nodeName, err)
+	}
+	if !npuhealth.ResetPending(node.Annotations) {
+		return nil
+	}
+	request := node.Annotations[npuhealth.ResetRequestAnnotationKey]
+	if !node.Spec.Unschedulable {
+		slog.Info("NPU reset waits for the node to be cordoned", "request", request)
+		return nil
+	}
+	pods, err := w.kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(k8sutil.PodNodeNameField, w.nodeName).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("list pods of node %s: %w", w.nodeName, err)
+	}
+	remaining := 0
+	for i := range pods.Items {
+		if k8sutil.IsEvictable(&pods.Items[i]) {
+			remaining++
+		}
+	}
+	if remaining > 0 {
+		slog.Info("NPU reset waits for the node to be drained", "request", request, "pods", remaining)
+		return nil
+	}
+
+	slog.Info("resetting unhealthy NPUs", "request", request, "summary", report.Summary())
+	resetErr := npuhealth.ResetDevices(hostSysMountPath, report)
+	if resetErr == nil {
+		w.acceptResetErrors(report)
+	}
+	// mark the request handled even on failure; the operator escalates to the next action
+	if err := w.annotate(ctx, map[string]string{npuhealth.ResetCompletedAnnotationKey: request}); err != nil {
+		return err
+	}
+	return resetErr
+}
+
+// acceptResetErrors moves the fatal error baseline of the reset devices, as their AER counters
+// keep the errors that were just reset.
+func (w *healthWatcher) acceptResetErrors(report npuhealth.Report) {
+	devices, err := npuhealth.ScanPCIDevices(hostPCIDevicesPath)
+	if err != nil {
+		slog.Error("failed to read AER counters after reset", "err", err)
+		return
+	}
+	var reset []npuhealth.PCIDevice
+	for _, d := range devices {
+		if slices.ContainsFunc(report.UnhealthyDevices, func(s npuhealth.DeviceStatus) bool {
+			return s.Card == d.Address && s.Reason == npuhealth.ReasonPCIeFatalError
+		}) {
+			reset = append(reset, d)
+		}
+	}
+	w.previous.AcceptFatalErrors(reset)
+}
+
+func (w *healthWatcher) annotate(ctx context.Context, annotations map[string]string) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("build node patch: %w", err)
+	}
+	if _, err := w.kubeClient.CoreV1().Nodes().Patch(ctx, w.nodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("patch node %s: %w", w.nodeName, err)
+	}
+	return nil
+}
+
+// checkSMI runs rbln-smi from the host when it is installed there.
+func checkSMI() error {
+	if _, err := os.Lstat(hostRblnSMIPath); err != nil {
+		return nil
+	}
+	if err := runCommand(hostRblnSMIPath, []string{}, true); err != nil {
+		return fmt.Errorf("rbln-smi failed: %w", err)
+	}
+	return nil
+}
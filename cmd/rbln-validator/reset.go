@@ -0,0 +1,24 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/npuhealth"
+)
+
+func newResetDevicesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset-devices",
+		Short: "Reset every NPU of the node, e.g. to load a newly flashed firmware",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := npuhealth.ResetAllDevices(hostSysMountPath); err != nil {
+				return err
+			}
+			slog.Info("reset all NPUs of the node")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	// efiSecureBootVar is the EFI global variable holding the Secure Boot state, read through
+	// the host root since efivarfs is not mounted in the container.
+	efiSecureBootVar = hostRootMountPath + "/sys/firmware/efi/efivars/SecureBoot-8be4df61-93ca-11d2-aa0d-00e098032b8c"
+	moduleSysfsPath  = hostRootMountPath + "/sys/module/" + hostDriverModuleName
+	// taintUnsignedModule is set on a module that is unsigned or signed by a key the kernel does not trust.
+	taintUnsignedModule = "E"
+)
+
+type moduleSignature struct {
+	secureBoot bool
+	loaded     bool
+	verified   bool
+}
+
+// problem returns why the module cannot be trusted under Secure Boot, or an empty string.
+func (s moduleSignature) problem() string {
+	switch {
+	case !s.secureBoot:
+		return ""
+	case !s.loaded:
+		return fmt.Sprintf("Secure Boot is enabled and module %q is not loaded; it may be rejected because it is not signed with an enrolled key, set spec.secureBoot of the RBLNDriver", hostDriverModuleName)
+	case !s.verified:
+		return fmt.Sprintf("Secure Boot is enabled and module %q is unsigned or signed by an unknown key", hostDriverModuleName)
+	}
+	return ""
+}
+
+func checkModuleSignature() (moduleSignature, error) {
+	var sig moduleSignature
+	secureBoot, err := secureBootEnabled()
+	if err != nil {
+		return sig, err
+	}
+	sig.secureBoot = secureBoot
+	if !secureBoot {
+		return sig, nil
+	}
+
+	// #nosec G304 -- path is a fixed sysfs attribute.
+	taint, err := os.ReadFile(moduleSysfsPath + "/taint")
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return sig, nil
+		}
+		return sig, fmt.Errorf("read module taint: %w", err)
+	}
+	sig.loaded = true
+	sig.verified = !strings.Contains(string(taint), taintUnsignedModule)
+	return sig, nil
+}
+
+func secureBootEnabled() (bool, error) {
+	data, err := os.ReadFile(efiSecureBootVar)
+	if err != nil {
+		// legacy BIOS boot or efivarfs not mounted
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("read Secure Boot state: %w", err)
+	}
+	// 4 bytes of attributes followed by the value
+	return len(data) > 4 && data[4] == 1, nil
+}
+
+// verifyModuleSignature fails when the driver module is not trusted under Secure Boot, so the
+// driver validation does not pass on a node that cannot load the module.
+func verifyModuleSignature() (moduleSignature, error) {
+	sig, err := checkModuleSignature()
+	if err != nil {
+		slog.Warn("failed to check the kernel module signature", "err", err)
+		return sig, nil
+	}
+	if problem := sig.problem(); problem != "" {
+		return sig, errors.New(problem)
+	}
+	if sig.secureBoot {
+		slog.Info("Secure Boot is enabled and the driver module signature is verified", "module", hostDriverModuleName)
+	}
+	return sig, nil
+}
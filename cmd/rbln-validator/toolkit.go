@@ -14,6 +14,8 @@ import (
 const (
 	toolkitReadyFile = "toolkit-ready"
 	cdiRootPath      = "/var/run/cdi"
+
+	deviceListStrategyEnvVar = "envvar"
 )
 
 func newToolkitCommand(builder *configBuilder) *cobra.Command {
@@ -54,6 +56,11 @@ func validateToolkit(cfg *config) error {
 			driverReadyInfo.ModTime().Format(time.RFC3339),
 		)
 
+		if !usesCDI(cfg.deviceListStrategy) {
+			slog.Info("toolkit validation: skipping CDI spec check", "deviceListStrategy", cfg.deviceListStrategy)
+			return nil
+		}
+
 		entries, err := os.ReadDir(cdiRootPath)
 		if err != nil {
 			return fmt.Errorf("failed to read %s: %w", cdiRootPath, err)
@@ -121,3 +128,9 @@ func validateToolkit(cfg *config) error {
 
 	return recreateStatusFile(cfg.outputDir, toolkitReadyFile)
 }
+
+// usesCDI returns false for the device list strategy that does not rely on CDI specs.
+// An unset strategy keeps the CDI check.
+func usesCDI(deviceListStrategy string) bool {
+	return deviceListStrategy != deviceListStrategyEnvVar
+}
@@ -1,5 +1,7 @@
 package consts
 
+import "slices"
+
 // log level
 const (
 	LogLevelError = iota - 2
@@ -16,6 +18,21 @@ const (
 	RBLNWorkloadConfigUnknown       = "unknown"
 	RBLNPresentLabelKey             = "rebellions.ai/npu.present"
 	NFDLabelPrefix                  = "feature.node.kubernetes.io/"
+
+	// RBLNProductLabelKey holds the product card name of the RBLN devices of a node,
+	// or RBLNProductMixed if the node has several products
+	RBLNProductLabelKey = "rebellions.ai/npu.product"
+	RBLNProductMixed    = "mixed"
+	// RBLNCountLabelKeyPrefix is followed by a product card name and holds the number of its cards
+	RBLNCountLabelKeyPrefix = "rebellions.ai/npu.count."
+	// RBLNFunctionLabelKey holds whether a node sees the physical or only the virtual functions of its RBLN devices
+	RBLNFunctionLabelKey = "rebellions.ai/npu.function"
+	RBLNFunctionPhysical = "physical"
+	RBLNFunctionVirtual  = "virtual"
+
+	// RBLNNPUTaintKey is the NoSchedule taint dedicating nodes with RBLN devices to NPU workloads
+	RBLNNPUTaintKey   = "rebellions.ai/npu"
+	RBLNNPUTaintValue = "present"
 )
 
 // Container runtimes
@@ -23,6 +40,13 @@ const (
 	Containerd = "containerd"
 	Docker     = "docker"
 	CRIO       = "crio"
+	K3s        = "k3s"
+	RKE2       = "rke2"
+
+	// RBLNContainerRuntimeLabelKey holds the container runtime detected on a node,
+	// e.g. containerd, k3s-v2 or crio. The -v2 suffix marks containerd 2.x.
+	RBLNContainerRuntimeLabelKey = "rebellions.ai/npu.container-runtime"
+	ContainerdV2Suffix           = "-v2"
 )
 
 // Condition types
@@ -31,6 +55,16 @@ const (
 	RBLNConditionTypeComponentsReady = "ComponentsReady"
 )
 
+// NPU health constants
+const (
+	// RBLNNodeConditionTypeNPUHealthy is the node condition reporting the health of the node's NPUs
+	RBLNNodeConditionTypeNPUHealthy = "RBLNNPUHealthy"
+	RBLNNPUHealthyReason            = "NPUsHealthy"
+	RBLNNPUUnhealthyReason          = "NPUsUnhealthy"
+	// RBLNNPUUnhealthyTaintKey is the NoSchedule taint added to nodes with an unhealthy NPU
+	RBLNNPUUnhealthyTaintKey = "rebellions.ai/npu-unhealthy"
+)
+
 // Device plugin constants
 const (
 	RBLNDevicePluginName  = "device-plugin"
@@ -79,10 +113,21 @@ const (
 	RBLNVFIOManagerName = "vfio-manager"
 )
 
-// DeviceMapping maps product card names to their device IDs
+// DeviceMapping maps product card names to their device IDs: the physical function
+// first, then the SR-IOV virtual function
 var DeviceMapping = map[string][]string{
 	RBLNCardCA12: {"1120", "1121"},
 	RBLNCardCA22: {"1220", "1221"},
 	RBLNCardCA25: {"1250", "1251"},
 	RBLNCardCR03: {"2030", "2031"},
 }
+
+// ProductOf returns the product card name of a PCI device ID, or an empty string.
+func ProductOf(deviceID string) string {
+	for product, ids := range DeviceMapping {
+		if slices.Contains(ids, deviceID) {
+			return product
+		}
+	}
+	return ""
+}
@@ -18,6 +18,8 @@ import (
 
 type ClusterInfo struct {
 	OpenshiftVersion string
+	// ContainerRuntime is the default runtime for nodes whose container runtime cannot be detected.
+	// The runtime of each NPU node is detected and labelled while reconciling RBLNClusterPolicy.
 	ContainerRuntime string
 }
 
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+// IndexPodNodeName indexes the cached pods by node, so the pods of a node can be listed
+// with a spec.nodeName field selector.
+func IndexPodNodeName(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &corev1.Pod{}, k8sutil.PodNodeNameField, func(obj client.Object) []string {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Spec.NodeName == "" {
+			return nil
+		}
+		return []string{pod.Spec.NodeName}
+	})
+}
+
+// evictNodePods evicts the pods a drain removes from a node. Evictions refused, e.g. by a
+// PodDisruptionBudget, are retried on the next call. It returns the number of pods left.
+func evictNodePods(ctx context.Context, c client.Client, log logr.Logger, nodeName string) (int, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.MatchingFields{k8sutil.PodNodeNameField: nodeName}); err != nil {
+		return 0, fmt.Errorf("failed to list pods of node %s: %w", nodeName, err)
+	}
+	remaining := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !k8sutil.IsEvictable(pod) {
+			continue
+		}
+		remaining++
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+		if err := c.SubResource("eviction").Create(ctx, pod, eviction); err != nil {
+			if kapierrors.IsNotFound(err) {
+				remaining--
+				continue
+			}
+			log.Info("Pod eviction refused", "namespace", pod.Namespace, "name", pod.Name, "reason", err.Error())
+		}
+	}
+	return remaining, nil
+}
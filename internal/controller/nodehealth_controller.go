@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/npuhealth"
+)
+
+// NodeHealthReconciler turns the NPU health reports published by the validator into
+// the RBLNNPUHealthy node condition and, optionally, a NoSchedule taint and remediation actions
+type NodeHealthReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes/status,verbs=get;update;patch
+
+func (r *NodeHealthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	node := &corev1.Node{}
+	if err := r.Get(ctx, req.NamespacedName, node); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return ctrl.Result{}, r.forgetNode(ctx, req.Name)
+		}
+		return ctrl.Result{}, err
+	}
+
+	policy, err := r.activeClusterPolicy(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	value, reported := node.Annotations[npuhealth.AnnotationKey]
+	if policy == nil || !policy.Spec.IsHealthMonitorEnabled() || !reported {
+		return ctrl.Result{}, r.clearHealth(ctx, node, policy != nil && policy.Spec.Paused)
+	}
+
+	var cond corev1.NodeCondition
+	var result ctrl.Result
+	healthy := true
+	report, err := npuhealth.Decode(value)
+	switch {
+	case err != nil:
+		r.Log.Error(err, "Invalid NPU health report", "node", node.Name)
+		cond = npuhealth.UnknownCondition(err.Error())
+	case time.Since(report.Timestamp) > healthStaleAfter(policy):
+		// the watcher stopped publishing; keep the taint of its last report but do not remediate
+		cond = npuhealth.StaleCondition(report)
+		healthy = report.Healthy
+	default:
+		cond = npuhealth.Condition(report)
+		healthy = report.Healthy
+		// mark the report stale if no heartbeat follows
+		result.RequeueAfter = healthStaleAfter(policy) - time.Since(report.Timestamp)
+	}
+
+	if err := r.patchCondition(ctx, node, func(n *corev1.Node) bool {
+		return npuhealth.SetCondition(n, cond)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	// a paused policy keeps reporting the health but neither taints nor remediates the node
+	if policy.Spec.Paused {
+		r.Log.Info("RBLNClusterPolicy is paused. Skip NPU health taint and remediation", "node", node.Name)
+		return result, nil
+	}
+
+	mutateTaints := npuhealth.RemoveTaint
+	if policy.Spec.IsUnhealthyNodeTaintEnabled() && !healthy {
+		mutateTaints = npuhealth.SetTaint
+	}
+	if err := r.patchTaints(ctx, node, mutateTaints); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// an unreadable or stale report is not a reason to act on the node
+	if !policy.Spec.IsRemediationEnabled() || cond.Status == corev1.ConditionUnknown {
+		return result, nil
+	}
+	remediation, err := r.remediate(ctx, policy, node, healthy)
+	if err != nil || remediation.RequeueAfter == 0 {
+		return result, err
+	}
+	result.RequeueAfter = min(result.RequeueAfter, remediation.RequeueAfter)
+	return result, nil
+}
+
+// healthStaleAfter returns how long a health report stays valid without a newer one.
+func healthStaleAfter(policy *rblnv1beta1.RBLNClusterPolicy) time.Duration {
+	interval := time.Duration(policy.Spec.HealthMonitor.IntervalSeconds) * time.Second
+	return npuhealth.StaleAfter(interval)
+}
+
+// clearHealth removes the condition and taint once the node recovered or the monitor is disabled.
+// The taint of a paused policy is kept.
+func (r *NodeHealthReconciler) clearHealth(ctx context.Context, node *corev1.Node, paused bool) error {
+	if err := r.patchCondition(ctx, node, npuhealth.RemoveCondition); err != nil {
+		return err
+	}
+	if paused {
+		return nil
+	}
+	return r.patchTaints(ctx, node, npuhealth.RemoveTaint)
+}
+
+func (r *NodeHealthReconciler) patchCondition(ctx context.Context, node *corev1.Node, mutate func(*corev1.Node) bool) error {
+	original := node.DeepCopy()
+	if !mutate(node) {
+		return nil
+	}
+	if err := r.Status().Patch(ctx, node, client.StrategicMergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to patch %s condition of node %s: %w", consts.RBLNNodeConditionTypeNPUHealthy, node.Name, err)
+	}
+	r.Log.Info("Updated NPU health condition", "node", node.Name)
+	return nil
+}
+
+func (r *NodeHealthReconciler) patchTaints(ctx context.Context, node *corev1.Node, mutate func(*corev1.Node) bool) error {
+	original := node.DeepCopy()
+	if !mutate(node) {
+		return nil
+	}
+	// taints are a plain list; guard against overwriting concurrent changes
+	patch := client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+	if err := r.Patch(ctx, node, patch); err != nil {
+		return fmt.Errorf("failed to patch %s taint of node %s: %w", consts.RBLNNPUUnhealthyTaintKey, node.Name, err)
+	}
+	r.Log.Info("Updated NPU health taint", "node", node.Name, "taints", len(node.Spec.Taints))
+	return nil
+}
+
+// activeClusterPolicy returns the singleton RBLNClusterPolicy, or nil if there is none.
+func (r *NodeHealthReconciler) activeClusterPolicy(ctx context.Context) (*rblnv1beta1.RBLNClusterPolicy, error) {
+	list := &rblnv1beta1.RBLNClusterPolicyList{}
+	if err := r.List(ctx, list); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list RBLNClusterPolicy: %w", err)
+	}
+	for i := range list.Items {
+		if list.Items[i].Status.State != rblnv1beta1.ClusterIgnored {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeHealthReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("npuhealth").
+		For(&corev1.Node{}, builder.WithPredicates(healthAnnotationChanged())).
+		// re-evaluate every node when the health monitor settings change
+		Watches(
+			&rblnv1beta1.RBLNClusterPolicy{},
+			handler.EnqueueRequestsFromMapFunc(r.allNodes),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+func (r *NodeHealthReconciler) allNodes(ctx context.Context, _ client.Object) []ctrl.Request {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		r.Log.Error(err, "Failed to list nodes")
+		return nil
+	}
+	requests := make([]ctrl.Request, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKey{Name: node.Name}})
+	}
+	return requests
+}
+
+func healthAnnotationChanged() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetAnnotations()[npuhealth.AnnotationKey] !=
+				e.ObjectNew.GetAnnotations()[npuhealth.AnnotationKey]
+		},
+	}
+}
@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/npuhealth"
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+const (
+	driverPodComponentLabelKey = "app.kubernetes.io/component"
+	driverPodComponentName     = "rbln-driver"
+
+	// drainRequeue is how often evictions are retried while a reset waits for the node to be drained
+	drainRequeue = 30 * time.Second
+)
+
+// remediate escalates the remediation actions on an unhealthy node and records the recovery of a remediated one.
+func (r *NodeHealthReconciler) remediate(ctx context.Context, policy *rblnv1beta1.RBLNClusterPolicy, node *corev1.Node, healthy bool) (ctrl.Result, error) {
+	remediator := npuhealth.NewRemediator(policy.Spec.Remediation)
+	now := time.Now()
+	// plan on a copy, the policy is the base of the status patch
+	current := policy.DeepCopy().Status.Remediations
+
+	if healthy {
+		statuses, recovered := remediator.Recover(current, node.Name, now)
+		if !recovered {
+			return ctrl.Result{}, nil
+		}
+		r.Log.Info("Node recovered after NPU remediation", "node", node.Name)
+		if err := r.uncordon(ctx, node); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.patchRemediations(ctx, policy, statuses)
+	}
+
+	// firmware updates and driver rollouts unbind and reset the devices too; leave the node to them
+	if reason, err := r.nodeBusy(ctx, node.Name); err != nil || reason != "" {
+		if reason != "" {
+			r.Log.Info("Skip NPU remediation", "node", node.Name, "reason", reason)
+		}
+		return ctrl.Result{RequeueAfter: remediator.Interval()}, err
+	}
+
+	action, wait := remediator.Next(current, node.Name, now)
+	if action == "" {
+		if wait > 0 && npuhealth.ResetPending(node.Annotations) {
+			// the health watcher resets the devices once the node is drained; retry refused evictions
+			if _, err := evictNodePods(ctx, r.Client, r.Log, node.Name); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: min(wait, drainRequeue)}, nil
+		}
+		if wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		statuses := remediator.Exhaust(current, node.Name, now)
+		return ctrl.Result{}, r.patchRemediations(ctx, policy, statuses)
+	}
+
+	message, err := r.runRemediation(ctx, policy, node, action, now)
+	if err != nil {
+		r.Log.Error(err, "NPU remediation action failed", "node", node.Name, "action", action)
+		message = err.Error()
+	} else {
+		r.Log.Info("Ran NPU remediation action", "node", node.Name, "action", action, "message", message)
+	}
+	statuses := remediator.Record(current, node.Name, action, message, now)
+	if err := r.patchRemediations(ctx, policy, statuses); err != nil {
+		return ctrl.Result{}, err
+	}
+	// check again whether the node recovered once the action had time to take effect
+	return ctrl.Result{RequeueAfter: remediator.Interval()}, nil
+}
+
+func (r *NodeHealthReconciler) runRemediation(ctx context.Context, policy *rblnv1beta1.RBLNClusterPolicy, node *corev1.Node, action rblnv1beta1.RemediationAction, now time.Time) (string, error) {
+	switch action {
+	case rblnv1beta1.RemediationRestartDevicePlugin:
+		labels := client.MatchingLabels{"app": policy.Spec.BaseName + "-" + consts.RBLNDevicePluginName}
+		return r.deleteNodePods(ctx, node.Name, labels, client.InNamespace(policyNamespace(policy)))
+	case rblnv1beta1.RemediationResetDevice:
+		return r.requestReset(ctx, node, now)
+	case rblnv1beta1.RemediationRestartDriver:
+		return r.deleteNodePods(ctx, node.Name, client.MatchingLabels{driverPodComponentLabelKey: driverPodComponentName})
+	case rblnv1beta1.RemediationCordon:
+		original := node.DeepCopy()
+		cordon(node)
+		node.Annotations[npuhealth.RebootRequiredAnnotationKey] = "true"
+		if err := r.Patch(ctx, node, client.MergeFrom(original)); err != nil {
+			return "", fmt.Errorf("failed to cordon node %s: %w", node.Name, err)
+		}
+		return "cordoned the node, a reboot is required", nil
+	}
+	return "", fmt.Errorf("unknown remediation action %q", action)
+}
+
+// requestReset cordons and drains the node and asks the health watcher to reset the unhealthy NPUs.
+// The watcher resets the devices only once no pod but DaemonSet and mirror pods is left on the node.
+func (r *NodeHealthReconciler) requestReset(ctx context.Context, node *corev1.Node, now time.Time) (string, error) {
+	original := node.DeepCopy()
+	cordon(node)
+	node.Annotations[npuhealth.ResetRequestAnnotationKey] = now.UTC().Format(time.RFC3339)
+	if err := r.Patch(ctx, node, client.MergeFrom(original)); err != nil {
+		return "", fmt.Errorf("failed to request NPU reset on node %s: %w", node.Name, err)
+	}
+
+	remaining, err := evictNodePods(ctx, r.Client, r.Log, node.Name)
+	if err != nil {
+		return "", err
+	}
+	if remaining > 0 {
+		return fmt.Sprintf("cordoned the node and requested a reset of the unhealthy NPUs once %d pods are evicted", remaining), nil
+	}
+	return "cordoned and drained the node, requested a reset of the unhealthy NPUs", nil
+}
+
+func (r *NodeHealthReconciler) deleteNodePods(ctx context.Context, nodeName string, opts ...client.ListOption) (string, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, opts...); err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", err)
+	}
+	deleted := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != nodeName {
+			continue
+		}
+		if err := r.Delete(ctx, pod); err != nil && !kapierrors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to delete pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		deleted++
+	}
+	if deleted == 0 {
+		return "no pod found on the node", nil
+	}
+	return fmt.Sprintf("deleted %d pods", deleted), nil
+}
+
+// cordon marks the node unschedulable and records it, unless the node already was.
+func cordon(node *corev1.Node) {
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	if !node.Spec.Unschedulable {
+		node.Spec.Unschedulable = true
+		node.Annotations[npuhealth.CordonAnnotationKey] = "true"
+	}
+}
+
+// uncordon reverts the cordon done by remediation once the node is healthy again, e.g. after the reboot.
+// A node that was cordoned before remediation stays cordoned.
+func (r *NodeHealthReconciler) uncordon(ctx context.Context, node *corev1.Node) error {
+	_, rebootRequired := node.Annotations[npuhealth.RebootRequiredAnnotationKey]
+	_, cordoned := node.Annotations[npuhealth.CordonAnnotationKey]
+	if !rebootRequired && !cordoned {
+		return nil
+	}
+	original := node.DeepCopy()
+	if cordoned {
+		node.Spec.Unschedulable = false
+	}
+	delete(node.Annotations, npuhealth.RebootRequiredAnnotationKey)
+	delete(node.Annotations, npuhealth.CordonAnnotationKey)
+	if err := r.Patch(ctx, node, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to uncordon node %s: %w", node.Name, err)
+	}
+	r.Log.Info("Reverted NPU remediation cordon", "node", node.Name, "uncordoned", cordoned)
+	return nil
+}
+
+// nodeBusy returns why remediation must leave the node alone, or "" if it may act: a firmware
+// update of the node is in progress or a driver pod on it is not Ready.
+func (r *NodeHealthReconciler) nodeBusy(ctx context.Context, nodeName string) (string, error) {
+	firmwares := &rebellionsaiv1alpha1.RBLNFirmwareList{}
+	if err := r.List(ctx, firmwares); err != nil {
+		return "", fmt.Errorf("failed to list RBLNFirmware: %w", err)
+	}
+	for _, fw := range firmwares.Items {
+		if fw.Status.Update != nil && fw.Status.Update.NodeName == nodeName {
+			return fmt.Sprintf("RBLNFirmware %s is updating the node", fw.Name), nil
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.MatchingLabels{driverPodComponentLabelKey: driverPodComponentName}); err != nil {
+		return "", fmt.Errorf("failed to list driver pods: %w", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == nodeName && !k8sutil.IsPodReady(pod) {
+			return fmt.Sprintf("driver pod %s/%s is not Ready", pod.Namespace, pod.Name), nil
+		}
+	}
+	return "", nil
+}
+
+func (r *NodeHealthReconciler) patchRemediations(ctx context.Context, policy *rblnv1beta1.RBLNClusterPolicy, statuses []rblnv1beta1.NodeRemediationStatus) error {
+	original := policy.DeepCopy()
+	policy.Status.Remediations = statuses
+	// the list is replaced as a whole; fail on a stale policy rather than dropping another node's entry
+	patch := client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+	if err := r.Status().Patch(ctx, policy, patch); err != nil {
+		return fmt.Errorf("failed to update remediation status of RBLNClusterPolicy %s: %w", policy.Name, err)
+	}
+	return nil
+}
+
+// forgetNode drops the remediation status of a deleted node.
+func (r *NodeHealthReconciler) forgetNode(ctx context.Context, nodeName string) error {
+	policy, err := r.activeClusterPolicy(ctx)
+	if err != nil || policy == nil {
+		return err
+	}
+	statuses, removed := npuhealth.RemoveRemediation(policy.DeepCopy().Status.Remediations, nodeName)
+	if !removed {
+		return nil
+	}
+	return r.patchRemediations(ctx, policy, statuses)
+}
+
+func policyNamespace(policy *rblnv1beta1.RBLNClusterPolicy) string {
+	if policy.Spec.Namespace != "" {
+		return policy.Spec.Namespace
+	}
+	return os.Getenv("OPERATOR_NAMESPACE")
+}
@@ -13,7 +13,9 @@ import (
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -27,6 +29,7 @@ import (
 	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
 	"github.com/rebellions-sw/rbln-npu-operator/internal/conditions"
 	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/nodefeature"
 	"github.com/rebellions-sw/rbln-npu-operator/internal/scope"
 )
 
@@ -40,7 +43,9 @@ type RBLNClusterPolicyReconciler struct {
 	conditionUpdater conditions.ConditionUpdater
 }
 
-// +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions;proxies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions;proxies;networks,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=servicecidrs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=imagedigestmirrorsets;imagetagmirrorsets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=use,resourceNames=privileged
 // +kubebuilder:rbac:groups=rebellions.ai,resources=rblnclusterpolicies,verbs=get;list;watch;create;update;patch;delete
@@ -49,6 +54,18 @@ type RBLNClusterPolicyReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts;pods;configmaps;services;nodes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get;create;update;patch;delete
+// +kubebuilder:rbac:urls=/metrics,verbs=get
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=node.k8s.io,resources=runtimeclasses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadashboards,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=nfd.k8s-sigs.io,resources=nodefeatures,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingadmissionpolicies;validatingadmissionpolicybindings,verbs=get;list;watch;create;update;patch;delete
 
 func (r *RBLNClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	r.Log.Info("Reconciling RBLNClusterPolicy", "name", req.Name)
@@ -181,6 +198,7 @@ func (r *RBLNClusterPolicyReconciler) reconcileStatus(ctx context.Context, rblnP
 	}
 
 	instance.Status.Components = componentsStatus
+	instance.Status.Images = cpScope.Images()
 
 	if allComponentsReady(componentsStatus) {
 		r.setClusterReadyStatus(instance, len(componentsStatus))
@@ -216,7 +234,7 @@ func (r *RBLNClusterPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// initialize condition updater
 	r.conditionUpdater = conditions.NewClusterPolicyConditionMgr(mgr.GetClient())
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&rblnv1beta1.RBLNClusterPolicy{}).
 		Owns(&appsv1.DaemonSet{}).
 		Owns(&corev1.ConfigMap{}).
@@ -227,7 +245,30 @@ func (r *RBLNClusterPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(r.singletonRequest),
 			builder.WithPredicates(r.rblnNodeLabelUpdated()),
 		).
-		Complete(r)
+		// re-materialize the trusted CA bundle when its source changes
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.trustedCASourceRequest))
+
+	// propagate changes of the cluster-wide proxy to the operands
+	if r.ClusterInfo != nil && r.ClusterInfo.OpenshiftVersion != "" {
+		clusterProxy := &unstructured.Unstructured{}
+		clusterProxy.SetGroupVersionKind(schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "Proxy"})
+		b = b.Watches(clusterProxy, handler.EnqueueRequestsFromMapFunc(r.singletonRequest))
+	}
+
+	// relabel the products of a node when node-feature-discovery publishes new devices
+	if _, err := mgr.GetRESTMapper().RESTMapping(nodefeature.GroupVersionKind.GroupKind(), nodefeature.GroupVersionKind.Version); err == nil {
+		nodeFeature := &unstructured.Unstructured{}
+		nodeFeature.SetGroupVersionKind(nodefeature.GroupVersionKind)
+		b = b.Watches(
+			nodeFeature,
+			handler.EnqueueRequestsFromMapFunc(r.singletonRequest),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		)
+	} else if !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to look up the NodeFeature API: %w", err)
+	}
+
+	return b.Complete(r)
 }
 
 func (r *RBLNClusterPolicyReconciler) singletonRequest(_ context.Context, o client.Object) []ctrl.Request {
@@ -244,6 +285,23 @@ func (r *RBLNClusterPolicyReconciler) singletonRequest(_ context.Context, o clie
 	return nil
 }
 
+// trustedCASourceRequest enqueues the singleton when obj is the source of its trusted CA bundle.
+func (r *RBLNClusterPolicyReconciler) trustedCASourceRequest(ctx context.Context, obj client.Object) []ctrl.Request {
+	if r.SingletonCRName == "" {
+		return nil
+	}
+	instance := &rblnv1beta1.RBLNClusterPolicy{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: r.SingletonCRName}, instance); err != nil {
+		return nil
+	}
+	trustedCA := instance.Spec.TrustedCA
+	if trustedCA == nil || trustedCA.ConfigMap != obj.GetName() ||
+		(trustedCA.Namespace != "" && trustedCA.Namespace != obj.GetNamespace()) {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Name: r.SingletonCRName}}}
+}
+
 func (r *RBLNClusterPolicyReconciler) rblnNodeLabelUpdated() predicate.Funcs {
 	return predicate.Funcs{
 		CreateFunc:  func(e event.CreateEvent) bool { return true },
@@ -19,14 +19,18 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,7 +42,10 @@ import (
 	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
 	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
 	"github.com/rebellions-sw/rbln-npu-operator/internal/conditions"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/registry"
 	"github.com/rebellions-sw/rbln-npu-operator/internal/scope"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/scope/patch"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/trustedca"
 	"github.com/rebellions-sw/rbln-npu-operator/internal/validator"
 )
 
@@ -49,8 +56,14 @@ type RBLNDriverReconciler struct {
 	Scheme                *runtime.Scheme
 	ClusterInfo           *ClusterInfo
 	nodeSelectorValidator validator.NodeSelectorValidator
+	// registry resolves the precompiled driver images; it is set up with the manager, so the
+	// image preflight is skipped by reconcilers built without one
+	registry *registry.Client
 }
 
+// precompiledImageRequeue is how often node pools without a precompiled driver image are checked again
+const precompiledImageRequeue = 2 * time.Minute
+
 const (
 	driverNodeDeployLabelKey = "rebellions.ai/npu.deploy.driver"
 	nfdOSReleaseIDLabelKey   = "feature.node.kubernetes.io/system-os_release.ID"
@@ -63,6 +76,7 @@ const (
 // +kubebuilder:rbac:groups=rebellions.ai,resources=rblndrivers/finalizers,verbs=update
 // +kubebuilder:rbac:groups=rebellions.ai,resources=rblnclusterpolicies,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=image.openshift.io,resources=imagestreams,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=serviceaccounts;nodes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
@@ -121,7 +135,7 @@ func (r *RBLNDriverReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if r.ClusterInfo != nil {
 		openshiftVersion = r.ClusterInfo.OpenshiftVersion
 	}
-	driverScope, err := scope.NewRBLNDriverScope(ctx, r.Client, r.Log, r.Scheme, instance, &clusterPolicyInstance, openshiftVersion)
+	driverScope, err := scope.NewRBLNDriverScope(ctx, r.Client, r.Log, r.Scheme, instance, &clusterPolicyInstance, openshiftVersion, r.registry)
 	if err != nil {
 		r.Log.Error(err, "failed to initialize RBLNDriver scope")
 		r.setDriverStatusError(ctx, instance, err)
@@ -134,6 +148,41 @@ func (r *RBLNDriverReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	conds, err := driverScope.ConditionReport(ctx)
+	if err != nil {
+		r.Log.Error(err, "failed to get driver manager condition report")
+		return ctrl.Result{}, err
+	}
+
+	statusChanged := false
+	// node pools skipped by the driver manager are reported without failing the other pools.
+	// They and the effective images are only known when the driver manager resources were patched.
+	if driverScope.IsManaged() {
+		for _, condType := range []string{patch.NodePoolsResolved, patch.PrecompiledImageMissing} {
+			if cond := meta.FindStatusCondition(conds, condType); cond != nil {
+				if shouldUpdateCondition(&instance.Status.Conditions, *cond) {
+					statusChanged = true
+				}
+			} else if meta.RemoveStatusCondition(&instance.Status.Conditions, condType) {
+				statusChanged = true
+			}
+		}
+		if images := driverScope.Images(); !equality.Semantic.DeepEqual(images, instance.Status.Images) {
+			instance.Status.Images = images
+			statusChanged = true
+		}
+	}
+	if statusChanged {
+		if err := r.Status().Update(ctx, instance); err != nil {
+			r.Log.Error(err, "failed to update RBLNDriver status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// check again for images pushed after the pool was skipped
+	if meta.IsStatusConditionTrue(instance.Status.Conditions, patch.PrecompiledImageMissing) {
+		return ctrl.Result{RequeueAfter: precompiledImageRequeue}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -194,6 +243,7 @@ func shouldUpdateCondition(conditionsList *[]metav1.Condition, desired metav1.Co
 // SetupWithManager sets up the controller with the Manager.
 func (r *RBLNDriverReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.nodeSelectorValidator = validator.NewNodeSelectorValidator(mgr.GetClient())
+	r.registry = registry.NewClient(nil)
 
 	mapFn := func(ctx context.Context, _ client.Object) []reconcile.Request {
 		list := &rebellionsaiv1alpha1.RBLNDriverList{}
@@ -210,7 +260,25 @@ func (r *RBLNDriverReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return requests
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	// digestConfigMapMapFn enqueues the drivers pinning their image digests in the ConfigMap
+	digestConfigMapMapFn := func(ctx context.Context, obj client.Object) []reconcile.Request {
+		list := &rebellionsaiv1alpha1.RBLNDriverList{}
+		if err := mgr.GetClient().List(ctx, list); err != nil {
+			r.Log.Error(err, "unable to list RBLNDriver resources for ConfigMap event")
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, driver := range list.Items {
+			if driver.Spec.Digests != nil && driver.Spec.Digests.ConfigMap == obj.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: client.ObjectKey{Name: driver.GetName()},
+				})
+			}
+		}
+		return requests
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&rebellionsaiv1alpha1.RBLNDriver{}).
 		Owns(&appsv1.DaemonSet{}).
 		Watches(&rblnv1beta1.RBLNClusterPolicy{}, handler.EnqueueRequestsFromMapFunc(mapFn)).
@@ -219,7 +287,55 @@ func (r *RBLNDriverReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(mapFn),
 			builder.WithPredicates(r.driverRelevantNodeLabelUpdated()),
 		).
-		Complete(r)
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(digestConfigMapMapFn)).
+		// roll the driver when the trusted CA bundle changes
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(mapFn),
+			builder.WithPredicates(predicate.NewPredicateFuncs(trustedca.IsBundle)),
+		)
+
+	// pre-stage the driver of the RHCOS version a cluster update moves to,
+	// and propagate changes of the cluster-wide proxy to the driver
+	if r.ClusterInfo != nil && r.ClusterInfo.OpenshiftVersion != "" {
+		clusterVersion := &unstructured.Unstructured{}
+		clusterVersion.SetGroupVersionKind(schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "ClusterVersion"})
+		b = b.Watches(clusterVersion, handler.EnqueueRequestsFromMapFunc(mapFn), builder.WithPredicates(clusterVersionUpdated()))
+
+		clusterProxy := &unstructured.Unstructured{}
+		clusterProxy.SetGroupVersionKind(schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "Proxy"})
+		b = b.Watches(clusterProxy, handler.EnqueueRequestsFromMapFunc(mapFn))
+	}
+
+	return b.Complete(r)
+}
+
+// clusterVersionUpdated filters ClusterVersion events to changes of the desired release or of the update state.
+func clusterVersionUpdated() predicate.Funcs {
+	updateState := func(obj client.Object) string {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return ""
+		}
+		desired, _, _ := unstructured.NestedString(u.Object, "status", "desired", "version")
+		history, _, _ := unstructured.NestedSlice(u.Object, "status", "history")
+		state := ""
+		if len(history) > 0 {
+			if latest, ok := history[0].(map[string]interface{}); ok {
+				state, _, _ = unstructured.NestedString(latest, "state")
+			}
+		}
+		return desired + "/" + state
+	}
+
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return updateState(e.ObjectOld) != updateState(e.ObjectNew)
+		},
+	}
 }
 
 func (r *RBLNDriverReconciler) driverRelevantNodeLabelUpdated() predicate.Funcs {
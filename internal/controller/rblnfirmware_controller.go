@@ -0,0 +1,330 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/conditions"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/firmware"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/scope"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/trustedca"
+)
+
+const (
+	// firmwareConditionCompliant reports whether every selected node runs the desired firmware.
+	firmwareConditionCompliant = "Compliant"
+
+	firmwareReasonCompliant        = "Compliant"
+	firmwareReasonNonCompliant     = "NonCompliant"
+	firmwareReasonVersionUnknown   = "VersionUnknown"
+	firmwareReasonInventoryPending = "InventoryPending"
+	firmwareReasonNoNodes          = "NoNodes"
+
+	// firmwareUpdateRequeue bounds how long an update phase waits before its timeout is checked again.
+	firmwareUpdateRequeue = 15 * time.Second
+)
+
+// RBLNFirmwareReconciler reconciles a RBLNFirmware object
+type RBLNFirmwareReconciler struct {
+	client.Client
+	Log         logr.Logger
+	Scheme      *runtime.Scheme
+	ClusterInfo *ClusterInfo
+}
+
+// +kubebuilder:rbac:groups=rebellions.ai,resources=rblnfirmwares,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rebellions.ai,resources=rblnfirmwares/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=rebellions.ai,resources=rblnfirmwares/finalizers,verbs=update
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
+
+// Reconcile publishes the firmware inventory of the selected nodes in the RBLNFirmware status
+// and, when enabled, updates non-compliant nodes one at a time.
+func (r *RBLNFirmwareReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log.Info("Reconciling RBLNFirmware", "name", req.Name)
+
+	instance := &rebellionsaiv1alpha1.RBLNFirmware{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("error getting RBLNFirmware object: %w", err)
+	}
+	original := instance.DeepCopy()
+
+	clusterPolicyList := &rblnv1beta1.RBLNClusterPolicyList{}
+	if err := r.List(ctx, clusterPolicyList); err != nil {
+		wrappedErr := fmt.Errorf("error getting RBLNClusterPolicy list: %w", err)
+		r.setFirmwareStatusError(ctx, instance, original, wrappedErr)
+		return ctrl.Result{}, wrappedErr
+	}
+	if len(clusterPolicyList.Items) == 0 {
+		r.Log.Info("RBLNClusterPolicy not found yet; skipping firmware reconcile")
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Type:    conditions.ConditionReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  "MissingClusterPolicy",
+			Message: "RBLNClusterPolicy not found in the cluster",
+		})
+		return ctrl.Result{}, r.patchFirmwareStatus(ctx, instance, original)
+	}
+
+	openshiftVersion := ""
+	if r.ClusterInfo != nil {
+		openshiftVersion = r.ClusterInfo.OpenshiftVersion
+	}
+	firmwareScope, err := scope.NewRBLNFirmwareScope(ctx, r.Client, r.Log, r.Scheme, instance, &clusterPolicyList.Items[0], openshiftVersion)
+	if err != nil {
+		r.setFirmwareStatusError(ctx, instance, original, err)
+		return ctrl.Result{}, err
+	}
+	if err := firmwareScope.PatchComponents(ctx); err != nil {
+		r.Log.Error(err, "failed to patch firmware inventory resources")
+		r.setFirmwareStatusError(ctx, instance, original, err)
+		return ctrl.Result{}, err
+	}
+	conds, err := firmwareScope.ConditionReport(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.setReadyCondition(instance, conds)
+
+	nodes, err := r.selectedNodes(ctx, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.setInventory(instance, nodes)
+
+	var result ctrl.Result
+	if firmwareScope.IsPaused() {
+		// an update in progress resumes where it stopped once the policy is unpaused
+		r.Log.Info("RBLNClusterPolicy is paused. Skip firmware update", "node", updateNodeName(instance))
+	} else {
+		result, err = r.updateFirmware(ctx, instance, &clusterPolicyList.Items[0].Spec.Validator, firmwareScope.Namespace(), firmwareScope.WorkloadClient(), nodes)
+		if err != nil {
+			r.Log.Error(err, "failed to update firmware", "node", updateNodeName(instance))
+		}
+	}
+	instance.Status.State = firmwareState(instance)
+
+	if statusErr := r.patchFirmwareStatus(ctx, instance, original); statusErr != nil {
+		return ctrl.Result{}, statusErr
+	}
+	return result, err
+}
+
+func (r *RBLNFirmwareReconciler) selectedNodes(ctx context.Context, instance *rebellionsaiv1alpha1.RBLNFirmware) ([]corev1.Node, error) {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabels(instance.GetNodeSelector())); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+	return nodes.Items, nil
+}
+
+// setInventory evaluates the inventory published on each node and sets the Compliant condition.
+func (r *RBLNFirmwareReconciler) setInventory(instance *rebellionsaiv1alpha1.RBLNFirmware, nodes []corev1.Node) {
+	desired := instance.Spec.DesiredVersions()
+	statuses := make([]rebellionsaiv1alpha1.NodeFirmwareStatus, 0, len(nodes))
+	pending, nonCompliant, unknown := 0, 0, 0
+	for _, node := range nodes {
+		value, ok := node.Annotations[firmware.AnnotationKey]
+		if !ok {
+			pending++
+			continue
+		}
+		inventory, err := firmware.Decode(value)
+		if err != nil {
+			r.Log.Error(err, "Invalid firmware inventory", "node", node.Name)
+			pending++
+			continue
+		}
+		status := firmware.NodeStatus(node.Name, inventory, desired)
+		switch {
+		case status.VersionUnknown:
+			unknown++
+		case !status.Compliant:
+			nonCompliant++
+		}
+		statuses = append(statuses, status)
+	}
+	instance.Status.Nodes = statuses
+
+	cond := metav1.Condition{
+		Type:    firmwareConditionCompliant,
+		Status:  metav1.ConditionTrue,
+		Reason:  firmwareReasonCompliant,
+		Message: fmt.Sprintf("%d nodes run the desired firmware", len(statuses)),
+	}
+	switch {
+	case nonCompliant > 0:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = firmwareReasonNonCompliant
+		cond.Message = fmt.Sprintf("%d of %d nodes run another firmware version", nonCompliant, len(nodes))
+	case unknown > 0:
+		cond.Status = metav1.ConditionUnknown
+		cond.Reason = firmwareReasonVersionUnknown
+		cond.Message = fmt.Sprintf("%d of %d nodes have cards that do not report their firmware version", unknown, len(nodes))
+	case pending > 0:
+		cond.Status = metav1.ConditionUnknown
+		cond.Reason = firmwareReasonInventoryPending
+		cond.Message = fmt.Sprintf("%d of %d nodes have not reported their firmware inventory", pending, len(nodes))
+	case len(nodes) == 0:
+		cond.Status = metav1.ConditionUnknown
+		cond.Reason = firmwareReasonNoNodes
+		cond.Message = "no node matches the node selector"
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, cond)
+}
+
+func (r *RBLNFirmwareReconciler) setReadyCondition(instance *rebellionsaiv1alpha1.RBLNFirmware, conds []metav1.Condition) {
+	ready := metav1.Condition{
+		Type:    conditions.ConditionReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  conditions.Reconciled,
+		Message: "Firmware inventory is running",
+	}
+	for _, c := range conds {
+		if c.Status != metav1.ConditionTrue {
+			ready.Status = metav1.ConditionFalse
+			ready.Reason = c.Reason
+			ready.Message = c.Message
+			break
+		}
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, ready)
+	meta.RemoveStatusCondition(&instance.Status.Conditions, conditions.ConditionError)
+}
+
+func (r *RBLNFirmwareReconciler) setFirmwareStatusError(ctx context.Context, instance, original *rebellionsaiv1alpha1.RBLNFirmware, err error) {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:   conditions.ConditionReady,
+		Status: metav1.ConditionFalse,
+		Reason: conditions.ConditionError,
+	})
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:    conditions.ConditionError,
+		Status:  metav1.ConditionTrue,
+		Reason:  conditions.ReconcileFailed,
+		Message: err.Error(),
+	})
+	if statusErr := r.patchFirmwareStatus(ctx, instance, original); statusErr != nil {
+		r.Log.Error(statusErr, "failed to update RBLNFirmware status")
+	}
+}
+
+func (r *RBLNFirmwareReconciler) patchFirmwareStatus(ctx context.Context, instance, original *rebellionsaiv1alpha1.RBLNFirmware) error {
+	if equality.Semantic.DeepEqual(original.Status, instance.Status) {
+		return nil
+	}
+	if err := r.Status().Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to update RBLNFirmware status: %w", err)
+	}
+	return nil
+}
+
+func firmwareState(instance *rebellionsaiv1alpha1.RBLNFirmware) rebellionsaiv1alpha1.FirmwareState {
+	if update := instance.Status.Update; update != nil {
+		if update.Phase == rebellionsaiv1alpha1.FirmwareUpdateFailed {
+			return rebellionsaiv1alpha1.FirmwareStateFailed
+		}
+		return rebellionsaiv1alpha1.FirmwareStateUpdating
+	}
+	if meta.IsStatusConditionTrue(instance.Status.Conditions, firmwareConditionCompliant) {
+		return rebellionsaiv1alpha1.FirmwareStateCompliant
+	}
+	return rebellionsaiv1alpha1.FirmwareStateNonCompliant
+}
+
+func updateNodeName(instance *rebellionsaiv1alpha1.RBLNFirmware) string {
+	if instance.Status.Update == nil {
+		return ""
+	}
+	return instance.Status.Update.NodeName
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RBLNFirmwareReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	mapFn := func(ctx context.Context, _ client.Object) []reconcile.Request {
+		list := &rebellionsaiv1alpha1.RBLNFirmwareList{}
+		if err := mgr.GetClient().List(ctx, list); err != nil {
+			r.Log.Error(err, "unable to list RBLNFirmware resources")
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(list.Items))
+		for _, fw := range list.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKey{Name: fw.GetName()},
+			})
+		}
+		return requests
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rebellionsaiv1alpha1.RBLNFirmware{}).
+		Owns(&appsv1.DaemonSet{}).
+		Owns(&batchv1.Job{}).
+		Watches(&rblnv1beta1.RBLNClusterPolicy{}, handler.EnqueueRequestsFromMapFunc(mapFn)).
+		Watches(
+			&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(mapFn),
+			builder.WithPredicates(firmwareRelevantNodeUpdated()),
+		).
+		// roll the firmware workloads when the trusted CA bundle changes
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(mapFn),
+			builder.WithPredicates(predicate.NewPredicateFuncs(trustedca.IsBundle)),
+		).
+		Complete(r)
+}
+
+func firmwareRelevantNodeUpdated() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldAnnotations, newAnnotations := e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()
+			return oldAnnotations[firmware.AnnotationKey] != newAnnotations[firmware.AnnotationKey] ||
+				!equality.Semantic.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+		},
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/firmware"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/scope/patch"
+)
+
+var _ = Describe("RBLNFirmware Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-firmware"
+
+		ctx := context.Background()
+
+		firmwareKey := types.NamespacedName{Name: resourceName}
+		clusterPolicyKey := types.NamespacedName{Name: "firmware-cluster-policy"}
+		nodeKey := types.NamespacedName{Name: "firmware-node"}
+		jobKey := types.NamespacedName{Name: firmwareFlashJobName + "-" + resourceName, Namespace: "default"}
+
+		var controllerReconciler *RBLNFirmwareReconciler
+
+		reconcileFirmware := func() *rebellionsaiv1alpha1.RBLNFirmware {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: firmwareKey})
+			Expect(err).NotTo(HaveOccurred())
+			instance := &rebellionsaiv1alpha1.RBLNFirmware{}
+			Expect(k8sClient.Get(ctx, firmwareKey, instance)).To(Succeed())
+			return instance
+		}
+
+		BeforeEach(func() {
+			controllerReconciler = &RBLNFirmwareReconciler{
+				Client: k8sClient,
+				Log:    logr.Discard(),
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("creating the custom resource for the Kind RBLNClusterPolicy")
+			Expect(k8sClient.Create(ctx, &rblnv1beta1.RBLNClusterPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterPolicyKey.Name},
+				Spec: rblnv1beta1.RBLNClusterPolicySpec{
+					Namespace:    "default",
+					WorkloadType: "container",
+					Validator: rblnv1beta1.ValidatorSpec{
+						Registry: "repo.rebellions.ai",
+						Image:    "rebellions/rbln-npu-operator-validator",
+						Version:  "1.0.0",
+					},
+				},
+			})).To(Succeed())
+
+			By("creating a node that reported one card running an older firmware")
+			inventory, err := firmware.Inventory{
+				Cards:     []firmware.Card{{Address: "0000:01:00.0", Product: "RBLN-CA22", Version: "1.0.0"}},
+				Timestamp: time.Now(),
+			}.Encode()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        nodeKey.Name,
+					Labels:      map[string]string{"rebellions.ai/npu.present": "true"},
+					Annotations: map[string]string{firmware.AnnotationKey: inventory},
+				},
+			})).To(Succeed())
+
+			By("creating the custom resource for the Kind RBLNFirmware")
+			Expect(k8sClient.Create(ctx, &rebellionsaiv1alpha1.RBLNFirmware{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName},
+				Spec: rebellionsaiv1alpha1.RBLNFirmwareSpec{
+					Cards:   []rebellionsaiv1alpha1.FirmwareCardSpec{{Product: "RBLN-CA22", Version: "1.2.0"}},
+					Version: "1.2.0",
+				},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("Cleanup the RBLNFirmware, RBLNClusterPolicy, node and flash job")
+			objects := []client.Object{
+				&rebellionsaiv1alpha1.RBLNFirmware{ObjectMeta: metav1.ObjectMeta{Name: resourceName}},
+				&rblnv1beta1.RBLNClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: clusterPolicyKey.Name}},
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeKey.Name}},
+				&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: jobKey.Name, Namespace: jobKey.Namespace}},
+			}
+			for _, obj := range objects {
+				err := k8sClient.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+				Expect(err == nil || errors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+
+		It("should report a node running another firmware as non-compliant", func() {
+			instance := reconcileFirmware()
+			Expect(instance.Status.State).To(Equal(rebellionsaiv1alpha1.FirmwareStateNonCompliant))
+			Expect(instance.Status.Nodes).To(HaveLen(1))
+			Expect(instance.Status.Nodes[0].Compliant).To(BeFalse())
+			Expect(instance.Status.Nodes[0].Cards).To(HaveLen(1))
+			Expect(instance.Status.Update).To(BeNil())
+
+			cond := meta.FindStatusCondition(instance.Status.Conditions, firmwareConditionCompliant)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal(firmwareReasonNonCompliant))
+		})
+
+		It("should drain, flash, verify and uncordon a non-compliant node", func() {
+			instance := &rebellionsaiv1alpha1.RBLNFirmware{}
+			Expect(k8sClient.Get(ctx, firmwareKey, instance)).To(Succeed())
+			instance.Spec.Update.Enabled = true
+			Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+			By("starting the update of the node")
+			instance = reconcileFirmware()
+			Expect(instance.Status.Update).NotTo(BeNil())
+			Expect(instance.Status.Update.NodeName).To(Equal(nodeKey.Name))
+			Expect(instance.Status.Update.Phase).To(Equal(rebellionsaiv1alpha1.FirmwareUpdateDraining))
+			Expect(instance.Status.Update.Cards).To(Equal(int32(1)))
+
+			By("draining the node and creating the flash job")
+			instance = reconcileFirmware()
+			Expect(instance.Status.Update.Phase).To(Equal(rebellionsaiv1alpha1.FirmwareUpdateFlashing))
+			node := &corev1.Node{}
+			Expect(k8sClient.Get(ctx, nodeKey, node)).To(Succeed())
+			Expect(node.Spec.Unschedulable).To(BeTrue())
+
+			job := &batchv1.Job{}
+			Expect(k8sClient.Get(ctx, jobKey, job)).To(Succeed())
+			Expect(job.Spec.Template.Spec.NodeName).To(Equal(nodeKey.Name))
+			Expect(job.Spec.Template.Spec.ServiceAccountName).To(Equal(patch.FirmwareFlashName))
+			Expect(job.Spec.Template.Spec.AutomountServiceAccountToken).To(HaveValue(BeFalse()))
+			Expect(job.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+			Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(job.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"reset-devices"}))
+
+			By("completing the flash job")
+			now := metav1.Now()
+			job.Status.StartTime = &now
+			job.Status.CompletionTime = &now
+			job.Status.Succeeded = 1
+			job.Status.Conditions = []batchv1.JobCondition{{
+				Type:               batchv1.JobComplete,
+				Status:             corev1.ConditionTrue,
+				LastProbeTime:      now,
+				LastTransitionTime: now,
+			}}
+			Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+
+			instance = reconcileFirmware()
+			Expect(instance.Status.Update.Phase).To(Equal(rebellionsaiv1alpha1.FirmwareUpdateVerifying))
+
+			By("waiting for the card to report the flashed firmware")
+			instance = reconcileFirmware()
+			Expect(instance.Status.Update.Phase).To(Equal(rebellionsaiv1alpha1.FirmwareUpdateVerifying))
+
+			By("verifying the node once its card reports the desired version")
+			inventory, err := firmware.Inventory{
+				Cards:     []firmware.Card{{Address: "0000:01:00.0", Product: "RBLN-CA22", Version: "1.2.0"}},
+				Timestamp: time.Now(),
+			}.Encode()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, nodeKey, node)).To(Succeed())
+			node.Annotations[firmware.AnnotationKey] = inventory
+			Expect(k8sClient.Update(ctx, node)).To(Succeed())
+
+			instance = reconcileFirmware()
+			Expect(instance.Status.Update).To(BeNil())
+			Expect(instance.Status.State).To(Equal(rebellionsaiv1alpha1.FirmwareStateCompliant))
+			Expect(instance.Status.Nodes[0].Cards[0].Version).To(Equal("1.2.0"))
+
+			Expect(k8sClient.Get(ctx, nodeKey, node)).To(Succeed())
+			Expect(node.Spec.Unschedulable).To(BeFalse())
+			Expect(node.Annotations).NotTo(HaveKey(firmwareCordonAnnotationKey))
+		})
+
+		It("should not update a node whose card does not report its firmware version", func() {
+			inventory, err := firmware.Inventory{
+				Cards:     []firmware.Card{{Address: "0000:01:00.0", Product: "RBLN-CA22"}},
+				Timestamp: time.Now(),
+			}.Encode()
+			Expect(err).NotTo(HaveOccurred())
+			node := &corev1.Node{}
+			Expect(k8sClient.Get(ctx, nodeKey, node)).To(Succeed())
+			node.Annotations[firmware.AnnotationKey] = inventory
+			Expect(k8sClient.Update(ctx, node)).To(Succeed())
+
+			instance := &rebellionsaiv1alpha1.RBLNFirmware{}
+			Expect(k8sClient.Get(ctx, firmwareKey, instance)).To(Succeed())
+			instance.Spec.Update.Enabled = true
+			Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+			instance = reconcileFirmware()
+			Expect(instance.Status.Update).To(BeNil())
+			Expect(instance.Status.Nodes).To(HaveLen(1))
+			Expect(instance.Status.Nodes[0].VersionUnknown).To(BeTrue())
+			Expect(instance.Status.Nodes[0].Cards[0].Version).To(Equal(firmware.VersionUnknown))
+
+			cond := meta.FindStatusCondition(instance.Status.Conditions, firmwareConditionCompliant)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(cond.Reason).To(Equal(firmwareReasonVersionUnknown))
+		})
+	})
+})
@@ -0,0 +1,324 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/scope/patch"
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+const (
+	// firmwareCordonAnnotationKey marks nodes cordoned by the firmware update, so only those are uncordoned.
+	firmwareCordonAnnotationKey = "rebellions.ai/npu.firmware.cordoned"
+	firmwareFlashJobName        = "rbln-firmware-flash"
+	firmwareResetContainerName  = "rbln-firmware-reset"
+	firmwareFlashNodeLabelKey   = "rebellions.ai/firmware-node"
+)
+
+// updateFirmware advances the rolling update by one step. Nodes are updated one at a time;
+// a failed node stops the rollout until the RBLNFirmware spec changes. Flash jobs are created
+// through workloads so they follow the cluster image mirrors and mount the trusted CA bundle.
+func (r *RBLNFirmwareReconciler) updateFirmware(ctx context.Context, instance *rebellionsaiv1alpha1.RBLNFirmware, validator *rblnv1beta1.ValidatorSpec, namespace string, workloads client.Writer, nodes []corev1.Node) (ctrl.Result, error) {
+	update := instance.Status.Update
+	now := time.Now()
+
+	if update == nil {
+		if !instance.Spec.Update.Enabled {
+			return ctrl.Result{}, nil
+		}
+		node := nextNonCompliantNode(instance.Status.Nodes)
+		if node == "" {
+			return ctrl.Result{}, nil
+		}
+		r.Log.Info("Starting firmware update", "node", node)
+		instance.Status.Update = &rebellionsaiv1alpha1.FirmwareUpdateStatus{
+			NodeName:           node,
+			Phase:              rebellionsaiv1alpha1.FirmwareUpdateDraining,
+			PhaseStartTime:     metav1.NewTime(now),
+			ObservedGeneration: instance.Generation,
+			Cards:              int32(len(findNodeStatus(instance.Status.Nodes, node).Cards)),
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if update.Phase == rebellionsaiv1alpha1.FirmwareUpdateFailed {
+		if update.ObservedGeneration == instance.Generation {
+			return ctrl.Result{}, nil
+		}
+		// the spec changed; retry the failed node, which is still cordoned
+		r.Log.Info("RBLNFirmware changed, retrying the failed firmware update", "node", update.NodeName)
+		instance.Status.Update = nil
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	node := findNode(nodes, update.NodeName)
+	if node == nil {
+		r.Log.Info("Node of the firmware update is gone", "node", update.NodeName)
+		instance.Status.Update = nil
+		return ctrl.Result{Requeue: true}, r.deleteFlashJob(ctx, instance, namespace)
+	}
+
+	var (
+		done bool
+		err  error
+	)
+	switch update.Phase {
+	case rebellionsaiv1alpha1.FirmwareUpdateDraining:
+		done, err = r.drainNode(ctx, update, node)
+		if done {
+			err = r.createFlashJob(ctx, workloads, instance, validator, namespace, node.Name)
+		}
+	case rebellionsaiv1alpha1.FirmwareUpdateFlashing:
+		done, err = r.flashJobDone(ctx, workloads, instance, validator, namespace, update)
+	case rebellionsaiv1alpha1.FirmwareUpdateVerifying:
+		done = nodeVerified(instance.Status.Nodes, update)
+		if done {
+			err = r.finishUpdate(ctx, instance, namespace, node)
+		}
+	}
+	if err != nil {
+		update.Message = err.Error()
+		return ctrl.Result{RequeueAfter: firmwareUpdateRequeue}, err
+	}
+
+	if done {
+		r.advanceUpdate(instance, now)
+		return ctrl.Result{Requeue: true}, nil
+	}
+	if timeout := phaseTimeout(instance.Spec.Update, update.Phase); timeout > 0 && now.Sub(update.PhaseStartTime.Time) > timeout {
+		r.Log.Info("Firmware update timed out", "node", update.NodeName, "phase", update.Phase)
+		update.Message = fmt.Sprintf("%s did not complete within %s", update.Phase, timeout)
+		update.Phase = rebellionsaiv1alpha1.FirmwareUpdateFailed
+		update.PhaseStartTime = metav1.NewTime(now)
+		update.ObservedGeneration = instance.Generation
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: firmwareUpdateRequeue}, nil
+}
+
+func (r *RBLNFirmwareReconciler) advanceUpdate(instance *rebellionsaiv1alpha1.RBLNFirmware, now time.Time) {
+	update := instance.Status.Update
+	switch update.Phase {
+	case rebellionsaiv1alpha1.FirmwareUpdateDraining:
+		update.Phase = rebellionsaiv1alpha1.FirmwareUpdateFlashing
+		update.Message = "flash job created"
+	case rebellionsaiv1alpha1.FirmwareUpdateFlashing:
+		update.Phase = rebellionsaiv1alpha1.FirmwareUpdateVerifying
+		update.Message = "waiting for the cards to report the desired firmware after the reset"
+	case rebellionsaiv1alpha1.FirmwareUpdateVerifying:
+		r.Log.Info("Firmware update completed", "node", update.NodeName)
+		instance.Status.Update = nil
+		return
+	}
+	update.PhaseStartTime = metav1.NewTime(now)
+	r.Log.Info("Firmware update progressed", "node", update.NodeName, "phase", update.Phase)
+}
+
+// drainNode cordons the node and evicts its pods, except DaemonSet and mirror pods.
+// It returns true once no pod is left to evict.
+func (r *RBLNFirmwareReconciler) drainNode(ctx context.Context, update *rebellionsaiv1alpha1.FirmwareUpdateStatus, node *corev1.Node) (bool, error) {
+	if !node.Spec.Unschedulable {
+		original := node.DeepCopy()
+		node.Spec.Unschedulable = true
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[firmwareCordonAnnotationKey] = "true"
+		if err := r.Patch(ctx, node, client.MergeFrom(original)); err != nil {
+			return false, fmt.Errorf("failed to cordon node %s: %w", node.Name, err)
+		}
+	}
+
+	remaining, err := evictNodePods(ctx, r.Client, r.Log, node.Name)
+	if err != nil {
+		return false, err
+	}
+	update.Message = fmt.Sprintf("%d pods left to evict", remaining)
+	return remaining == 0, nil
+}
+
+func (r *RBLNFirmwareReconciler) createFlashJob(ctx context.Context, workloads client.Writer, instance *rebellionsaiv1alpha1.RBLNFirmware, validator *rblnv1beta1.ValidatorSpec, namespace, nodeName string) error {
+	if err := r.deleteFlashJob(ctx, instance, namespace); err != nil {
+		return err
+	}
+	job := flashJob(instance, validator, namespace, nodeName)
+	if err := ctrl.SetControllerReference(instance, job, r.Scheme); err != nil {
+		return err
+	}
+	if err := workloads.Create(ctx, job); err != nil {
+		return fmt.Errorf("failed to create firmware flash job: %w", err)
+	}
+	r.Log.Info("Created firmware flash job", "namespace", job.Namespace, "name", job.Name, "node", nodeName)
+	return nil
+}
+
+func (r *RBLNFirmwareReconciler) flashJobDone(ctx context.Context, workloads client.Writer, instance *rebellionsaiv1alpha1.RBLNFirmware, validator *rblnv1beta1.ValidatorSpec, namespace string, update *rebellionsaiv1alpha1.FirmwareUpdateStatus) (bool, error) {
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, client.ObjectKey{Name: flashJobName(instance), Namespace: namespace}, job); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return false, r.createFlashJob(ctx, workloads, instance, validator, namespace, update.NodeName)
+		}
+		return false, err
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return true, nil
+		case batchv1.JobFailed:
+			update.Phase = rebellionsaiv1alpha1.FirmwareUpdateFailed
+			update.PhaseStartTime = metav1.Now()
+			update.ObservedGeneration = instance.Generation
+			update.Message = fmt.Sprintf("flash job failed: %s", cond.Message)
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+// nodeVerified reports whether every managed card of the node reports its desired version as
+// its running firmware, and no card was lost by the flash or the reset. The inventory is
+// republished when a version changes, so a compliant node reports a reading after the reset.
+func nodeVerified(nodes []rebellionsaiv1alpha1.NodeFirmwareStatus, update *rebellionsaiv1alpha1.FirmwareUpdateStatus) bool {
+	node := findNodeStatus(nodes, update.NodeName)
+	return node.NodeName != "" && node.Compliant && int32(len(node.Cards)) >= update.Cards
+}
+
+// findNodeStatus returns the inventory status of a node, or an empty status.
+func findNodeStatus(nodes []rebellionsaiv1alpha1.NodeFirmwareStatus, name string) rebellionsaiv1alpha1.NodeFirmwareStatus {
+	for _, node := range nodes {
+		if node.NodeName == name {
+			return node
+		}
+	}
+	return rebellionsaiv1alpha1.NodeFirmwareStatus{}
+}
+
+func (r *RBLNFirmwareReconciler) finishUpdate(ctx context.Context, instance *rebellionsaiv1alpha1.RBLNFirmware, namespace string, node *corev1.Node) error {
+	if _, ok := node.Annotations[firmwareCordonAnnotationKey]; ok {
+		original := node.DeepCopy()
+		node.Spec.Unschedulable = false
+		delete(node.Annotations, firmwareCordonAnnotationKey)
+		if err := r.Patch(ctx, node, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("failed to uncordon node %s: %w", node.Name, err)
+		}
+	}
+	return r.deleteFlashJob(ctx, instance, namespace)
+}
+
+func (r *RBLNFirmwareReconciler) deleteFlashJob(ctx context.Context, instance *rebellionsaiv1alpha1.RBLNFirmware, namespace string) error {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: flashJobName(instance), Namespace: namespace}}
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kapierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete firmware flash job: %w", err)
+	}
+	return nil
+}
+
+func flashJobName(instance *rebellionsaiv1alpha1.RBLNFirmware) string {
+	return fmt.Sprintf("%s-%s", firmwareFlashJobName, instance.Name)
+}
+
+// flashJob runs the firmware image on the node and then resets its NPUs, so the devices load
+// the new firmware. The flasher entrypoint flashes the cards with the firmware the image ships
+// and exits non-zero on failure; the reset runs the validator image.
+func flashJob(instance *rebellionsaiv1alpha1.RBLNFirmware, validator *rblnv1beta1.ValidatorSpec, namespace, nodeName string) *batchv1.Job {
+	spec := instance.Spec
+	pullPolicy := spec.ImagePullPolicy
+	if pullPolicy == "" {
+		pullPolicy = corev1.PullIfNotPresent
+	}
+	validatorPullPolicy := validator.ImagePullPolicy
+	if validatorPullPolicy == "" {
+		validatorPullPolicy = corev1.PullIfNotPresent
+	}
+
+	privileged := true
+	flasher := k8sutil.NewContainerBuilder().
+		WithName(firmwareFlashJobName).
+		WithImage(patch.ComposeImageReference(spec.Registry, spec.Image), spec.Version, pullPolicy).
+		WithEnvs(spec.Env).
+		WithSecurityContext(&corev1.SecurityContext{Privileged: &privileged}).
+		WithVolumeMounts([]corev1.VolumeMount{
+			{Name: "dev", MountPath: "/dev"},
+			{Name: "sys", MountPath: "/sys"},
+		}).
+		Build()
+	reset := k8sutil.NewContainerBuilder().
+		WithName(firmwareResetContainerName).
+		WithImage(patch.ComposeImageReference(validator.Registry, validator.Image), validator.Version, validatorPullPolicy).
+		WithCommands([]string{"rbln-validator"}).
+		WithArgs([]string{"reset-devices"}).
+		WithSecurityContext(&corev1.SecurityContext{Privileged: &privileged}).
+		WithVolumeMounts([]corev1.VolumeMount{
+			{Name: "sys", MountPath: "/host-sys"},
+		}).
+		Build()
+
+	return k8sutil.NewJobBuilder(flashJobName(instance), namespace).
+		WithLabels(map[string]string{
+			"app":                     firmwareFlashJobName,
+			firmwareFlashNodeLabelKey: nodeName,
+		}).
+		WithBackoffLimit(0).
+		WithPodSpec(k8sutil.NewPodSpecBuilder().
+			WithServiceAccountName(patch.FirmwareFlashName).
+			WithAutomountServiceAccountToken(false).
+			WithNodeName(nodeName).
+			WithRestartPolicy(corev1.RestartPolicyNever).
+			WithTolerations([]corev1.Toleration{{Operator: corev1.TolerationOpExists}}).
+			WithImagePullSecrets(append(slices.Clone(spec.ImagePullSecrets), validator.ImagePullSecrets...)).
+			WithVolumes([]corev1.Volume{
+				{Name: "dev", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/dev"}}},
+				{Name: "sys", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/sys"}}},
+			}).
+			WithInitContainers([]*corev1.Container{flasher}).
+			WithContainers([]*corev1.Container{reset}).
+			Build()).
+		Build()
+}
+
+func phaseTimeout(spec rebellionsaiv1alpha1.FirmwareUpdateSpec, phase rebellionsaiv1alpha1.FirmwareUpdatePhase) time.Duration {
+	switch phase {
+	case rebellionsaiv1alpha1.FirmwareUpdateDraining:
+		return time.Duration(spec.DrainTimeoutSeconds) * time.Second
+	case rebellionsaiv1alpha1.FirmwareUpdateFlashing:
+		return time.Duration(spec.FlashTimeoutSeconds) * time.Second
+	case rebellionsaiv1alpha1.FirmwareUpdateVerifying:
+		return time.Duration(spec.VerifyTimeoutSeconds) * time.Second
+	}
+	return 0
+}
+
+// nextNonCompliantNode skips the nodes whose cards do not report their version, since flashing
+// them could neither be justified nor verified.
+func nextNonCompliantNode(nodes []rebellionsaiv1alpha1.NodeFirmwareStatus) string {
+	for _, node := range nodes {
+		if !node.Compliant && !node.VersionUnknown {
+			return node.NodeName
+		}
+	}
+	return ""
+}
+
+func findNode(nodes []corev1.Node, name string) *corev1.Node {
+	for i := range nodes {
+		if nodes[i].Name == name {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
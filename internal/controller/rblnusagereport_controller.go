@@ -0,0 +1,218 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/conditions"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/usage"
+)
+
+const (
+	usageReasonSampled = "Sampled"
+
+	defaultUsageIntervalSeconds = 300
+	// usageMetricsInterval is how often the usage metrics are refreshed
+	usageMetricsInterval = 30 * time.Second
+)
+
+// RBLNUsageReportReconciler periodically accumulates the NPU resources allocated per namespace,
+// pod and node in the status of every RBLNUsageReport, and publishes them as metrics
+type RBLNUsageReportReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	// ledger keeps the allocations of deleted pods until every report sampled them
+	ledger *usage.Ledger
+}
+
+// +kubebuilder:rbac:groups=rebellions.ai,resources=rblnusagereports,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rebellions.ai,resources=rblnusagereports/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=rebellions.ai,resources=rblnusagereports/finalizers,verbs=update
+
+// Reconcile samples the usage into the status of a RBLNUsageReport.
+func (r *RBLNUsageReportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	instance := &rebellionsaiv1alpha1.RBLNUsageReport{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("error getting RBLNUsageReport object: %w", err)
+	}
+	original := instance.DeepCopy()
+
+	interval := usageInterval(instance)
+	// a reconcile triggered by a spec change must not shorten the sampling interval
+	if last := instance.Status.LastSampleTime; last != nil {
+		if wait := time.Until(last.Add(interval)); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list pods: %w", err)
+	}
+	snapshot := usage.NewSnapshot(pods.Items)
+	if r.ledger != nil {
+		snapshot.AddEnded(r.ledger.Ended())
+	}
+
+	usage.Sample(instance, snapshot, metav1.Now())
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:    conditions.ConditionReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  usageReasonSampled,
+		Message: fmt.Sprintf("%d pods hold NPU resources", len(snapshot.Pods)),
+	})
+	if err := r.patchUsageReportStatus(ctx, instance, original); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+func (r *RBLNUsageReportReconciler) patchUsageReportStatus(ctx context.Context, instance, original *rebellionsaiv1alpha1.RBLNUsageReport) error {
+	if equality.Semantic.DeepEqual(original.Status, instance.Status) {
+		return nil
+	}
+	if err := r.Status().Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to update RBLNUsageReport status: %w", err)
+	}
+	return nil
+}
+
+func usageInterval(instance *rebellionsaiv1alpha1.RBLNUsageReport) time.Duration {
+	seconds := instance.Spec.IntervalSeconds
+	if seconds <= 0 {
+		seconds = defaultUsageIntervalSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RBLNUsageReportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.ledger == nil {
+		r.ledger = usage.NewLedger()
+	}
+	if err := mgr.Add(&usageMetrics{
+		client: mgr.GetClient(),
+		cache:  mgr.GetCache(),
+		log:    r.Log,
+		ledger: r.ledger,
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rebellionsaiv1alpha1.RBLNUsageReport{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// usageMetrics publishes the usage metrics on a ticker and records the allocations of deleted
+// pods for the reports. It runs on the leader only, like the reconciler sampling the reports.
+type usageMetrics struct {
+	client client.Client
+	cache  cache.Cache
+	log    logr.Logger
+	ledger *usage.Ledger
+}
+
+var _ manager.LeaderElectionRunnable = &usageMetrics{}
+
+func (m *usageMetrics) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable.
+func (m *usageMetrics) Start(ctx context.Context) error {
+	informer, err := m.cache.GetInformer(ctx, &corev1.Pod{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod informer: %w", err)
+	}
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pod, ok := obj.(*corev1.Pod); ok {
+				m.ledger.Record(pod, time.Now())
+			}
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to watch pod deletions: %w", err)
+	}
+
+	ticker := time.NewTicker(usageMetricsInterval)
+	defer ticker.Stop()
+	for {
+		if err := m.refresh(ctx); err != nil {
+			m.log.Error(err, "Failed to refresh NPU usage metrics")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh publishes the metrics and forgets the deleted pods every report sampled.
+func (m *usageMetrics) refresh(ctx context.Context) error {
+	pods := &corev1.PodList{}
+	if err := m.client.List(ctx, pods); err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	nodes := &corev1.NodeList{}
+	if err := m.client.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	usage.Publish(usage.NewSnapshot(pods.Items), nodes.Items)
+
+	reports := &rebellionsaiv1alpha1.RBLNUsageReportList{}
+	if err := m.client.List(ctx, reports); err != nil {
+		return fmt.Errorf("failed to list RBLNUsageReports: %w", err)
+	}
+	// reports that were never sampled start their period at the first sample
+	sampled := time.Now()
+	for _, report := range reports.Items {
+		if last := report.Status.LastSampleTime; last != nil && last.Time.Before(sampled) {
+			sampled = last.Time
+		}
+	}
+	m.ledger.Prune(sampled)
+	return nil
+}
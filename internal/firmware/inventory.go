@@ -0,0 +1,129 @@
+// Package firmware defines the firmware inventory published on each node and
+// evaluates it against the versions desired by an RBLNFirmware.
+//
+// The version of a card is the running firmware version the rebellions driver reports in the
+// fw_version attribute of the PCI device. Cards whose driver does not report it are listed
+// with VersionUnknown, and their nodes are neither compliant nor updated automatically.
+package firmware
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+)
+
+// AnnotationKey is the node annotation holding the JSON encoded Inventory.
+const AnnotationKey = "rebellions.ai/npu.firmware"
+
+// VersionUnknown is the version of a card that does not report its running firmware.
+const VersionUnknown = "Unknown"
+
+// Card is the firmware of an RBLN device.
+type Card struct {
+	Address string `json:"address"`
+	Product string `json:"product,omitempty"`
+	// Version is empty if the device does not report its running firmware version.
+	Version string `json:"version,omitempty"`
+}
+
+// Inventory is the firmware of every RBLN device of a node.
+type Inventory struct {
+	Cards     []Card    `json:"cards"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Equal reports whether two inventories list the same cards, ignoring the timestamp.
+func (i Inventory) Equal(other Inventory) bool {
+	return slices.Equal(i.Cards, other.Cards)
+}
+
+func (i Inventory) Encode() (string, error) {
+	out, err := json.Marshal(i)
+	if err != nil {
+		return "", fmt.Errorf("encode firmware inventory: %w", err)
+	}
+	return string(out), nil
+}
+
+func Decode(value string) (Inventory, error) {
+	var i Inventory
+	if err := json.Unmarshal([]byte(value), &i); err != nil {
+		return Inventory{}, fmt.Errorf("decode firmware inventory: %w", err)
+	}
+	return i, nil
+}
+
+// Scan reads the running firmware version of the RBLN devices under a sysfs PCI devices directory.
+func Scan(pciDevicesPath string, now time.Time) (Inventory, error) {
+	entries, err := os.ReadDir(pciDevicesPath)
+	if err != nil {
+		return Inventory{}, fmt.Errorf("read %s: %w", pciDevicesPath, err)
+	}
+
+	inventory := Inventory{Cards: []Card{}, Timestamp: now}
+	for _, entry := range entries {
+		devicePath := filepath.Join(pciDevicesPath, entry.Name())
+		vendor, err := readSysfsValue(filepath.Join(devicePath, "vendor"))
+		if err != nil || strings.TrimPrefix(vendor, "0x") != consts.RBLNVendorCode {
+			continue
+		}
+		device, _ := readSysfsValue(filepath.Join(devicePath, "device"))
+		version, _ := readSysfsValue(filepath.Join(devicePath, "fw_version"))
+		inventory.Cards = append(inventory.Cards, Card{
+			Address: entry.Name(),
+			Product: consts.ProductOf(strings.TrimPrefix(device, "0x")),
+			Version: version,
+		})
+	}
+	return inventory, nil
+}
+
+// NodeStatus evaluates the inventory of a node against the desired versions per product.
+// A node with a managed card of unknown version is not compliant and reports VersionUnknown.
+func NodeStatus(nodeName string, inventory Inventory, desired map[string]string) rebellionsaiv1alpha1.NodeFirmwareStatus {
+	status := rebellionsaiv1alpha1.NodeFirmwareStatus{
+		NodeName:          nodeName,
+		Compliant:         true,
+		LastInventoryTime: ptrTime(inventory.Timestamp),
+	}
+	for _, card := range inventory.Cards {
+		version, desiredVersion := card.Version, desired[card.Product]
+		if version == "" {
+			version = VersionUnknown
+			status.VersionUnknown = status.VersionUnknown || desiredVersion != ""
+		}
+		if desiredVersion != "" && desiredVersion != version {
+			status.Compliant = false
+		}
+		status.Cards = append(status.Cards, rebellionsaiv1alpha1.CardFirmwareStatus{
+			Address:        card.Address,
+			Product:        card.Product,
+			Version:        version,
+			DesiredVersion: desiredVersion,
+		})
+	}
+	return status
+}
+
+func readSysfsValue(path string) (string, error) {
+	// #nosec G304 -- path is built from the sysfs PCI devices directory.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func ptrTime(t time.Time) *metav1.Time {
+	mt := metav1.NewTime(t)
+	return &mt
+}
@@ -0,0 +1,110 @@
+package firmware_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/firmware"
+)
+
+func TestFirmware(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Firmware Suite")
+}
+
+var _ = Describe("Firmware", func() {
+	Describe("Scan", func() {
+		var devices string
+
+		addDevice := func(address, vendor, device, version string) {
+			devicePath := filepath.Join(devices, address)
+			Expect(os.MkdirAll(devicePath, 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(devicePath, "vendor"), []byte(vendor+"\n"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(devicePath, "device"), []byte(device+"\n"), 0o644)).To(Succeed())
+			if version != "" {
+				Expect(os.WriteFile(filepath.Join(devicePath, "fw_version"), []byte(version+"\n"), 0o644)).To(Succeed())
+			}
+		}
+
+		BeforeEach(func() {
+			devices = GinkgoT().TempDir()
+		})
+
+		It("should read the firmware version of the RBLN devices only", func() {
+			addDevice("0000:01:00.0", "0x1eff", "0x1220", "1.2.0")
+			addDevice("0000:02:00.0", "0x1eff", "0x1250", "")
+			addDevice("0000:03:00.0", "0x10de", "0x2330", "96.00.5f.00.01")
+
+			inventory, err := firmware.Scan(devices, time.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inventory.Cards).To(HaveExactElements(
+				firmware.Card{Address: "0000:01:00.0", Product: consts.RBLNCardCA22, Version: "1.2.0"},
+				firmware.Card{Address: "0000:02:00.0", Product: consts.RBLNCardCA25},
+			))
+		})
+
+		It("should round-trip an inventory", func() {
+			addDevice("0000:01:00.0", "0x1eff", "0x1220", "1.2.0")
+
+			inventory, err := firmware.Scan(devices, time.Now().UTC().Truncate(time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			value, err := inventory.Encode()
+			Expect(err).NotTo(HaveOccurred())
+			decoded, err := firmware.Decode(value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded.Equal(inventory)).To(BeTrue())
+			Expect(decoded.Timestamp.Equal(inventory.Timestamp)).To(BeTrue())
+		})
+	})
+
+	Describe("NodeStatus", func() {
+		inventory := firmware.Inventory{
+			Cards: []firmware.Card{
+				{Address: "0000:01:00.0", Product: consts.RBLNCardCA22, Version: "1.2.0"},
+				{Address: "0000:02:00.0", Product: consts.RBLNCardCA25, Version: "0.9.0"},
+			},
+			Timestamp: time.Now(),
+		}
+
+		It("should be compliant when managed cards run the desired version", func() {
+			status := firmware.NodeStatus("node-1", inventory, map[string]string{consts.RBLNCardCA22: "1.2.0"})
+			Expect(status.Compliant).To(BeTrue())
+			Expect(status.VersionUnknown).To(BeFalse())
+			Expect(status.Cards).To(HaveExactElements(
+				And(HaveField("Version", "1.2.0"), HaveField("DesiredVersion", "1.2.0")),
+				And(HaveField("Version", "0.9.0"), HaveField("DesiredVersion", "")),
+			))
+		})
+
+		It("should not be compliant when a managed card runs another version", func() {
+			status := firmware.NodeStatus("node-1", inventory, map[string]string{
+				consts.RBLNCardCA22: "1.2.0",
+				consts.RBLNCardCA25: "1.0.0",
+			})
+			Expect(status.Compliant).To(BeFalse())
+			Expect(status.VersionUnknown).To(BeFalse())
+		})
+
+		It("should report the version of a managed card that does not report it as unknown", func() {
+			unknown := firmware.Inventory{Cards: []firmware.Card{
+				{Address: "0000:01:00.0", Product: consts.RBLNCardCA22},
+				{Address: "0000:02:00.0", Product: consts.RBLNCardCA25},
+			}}
+			status := firmware.NodeStatus("node-1", unknown, map[string]string{consts.RBLNCardCA22: "1.2.0"})
+			Expect(status.Compliant).To(BeFalse())
+			Expect(status.VersionUnknown).To(BeTrue())
+			Expect(status.Cards).To(HaveEach(HaveField("Version", firmware.VersionUnknown)))
+
+			// an unmanaged card of unknown version does not matter
+			status = firmware.NodeStatus("node-1", unknown, map[string]string{consts.RBLNCardCA12: "1.2.0"})
+			Expect(status.Compliant).To(BeTrue())
+			Expect(status.VersionUnknown).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,350 @@
+// Package fleet summarizes the state of the NPU fleet for the kubectl rbln plugin: the
+// RBLNClusterPolicy and its components, the NPU nodes with their driver pool and health, and
+// the RBLNDrivers with the DaemonSets of their node pools.
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+)
+
+const (
+	// labels the driver manager sets on the DaemonSet of every node pool
+	driverInstanceLabelKey = "rebellions.ai/driver-instance"
+	nodePoolLabelKey       = "nodepool"
+
+	resourcePrefix = "rebellions.ai/"
+
+	// Healthy, Unhealthy and Unknown are the NPU health of a node
+	Healthy   = "Healthy"
+	Unhealthy = "Unhealthy"
+	Unknown   = "Unknown"
+)
+
+// ClusterStatus is the state of the RBLNClusterPolicy and its components.
+type ClusterStatus struct {
+	Name       string
+	State      string
+	Paused     bool
+	Ready      string
+	Message    string
+	Components []ComponentStatus
+}
+
+// ComponentStatus is the state of a component of the RBLNClusterPolicy.
+type ComponentStatus struct {
+	Name            string
+	Namespace       string
+	ManagementState string
+	State           string
+	Message         string
+}
+
+// NodeStatus is the state of an NPU node.
+type NodeStatus struct {
+	Name           string
+	Present        bool
+	WorkloadConfig string
+	Resources      []string
+	DriverPool     string
+	Health         string
+	Issues         []string
+}
+
+// Healthy reports whether nothing is wrong with the node.
+func (n NodeStatus) Healthy() bool {
+	return n.Health != Unhealthy && len(n.Issues) == 0
+}
+
+// DriverStatus is the state of a RBLNDriver and its node pools.
+type DriverStatus struct {
+	Name            string
+	ManagementState string
+	State           string
+	Message         string
+	Pools           []PoolStatus
+}
+
+// PoolStatus is the state of the driver DaemonSet of a node pool.
+type PoolStatus struct {
+	Name      string
+	DaemonSet string
+	Desired   int32
+	Ready     int32
+	UpToDate  int32
+	Images    []string
+}
+
+// GetClusterStatus returns the state of the RBLNClusterPolicy. It returns nil if there is none.
+func GetClusterStatus(ctx context.Context, c client.Reader) (*ClusterStatus, error) {
+	policy, err := getClusterPolicy(ctx, c)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+
+	status := &ClusterStatus{
+		Name:   policy.Name,
+		State:  string(policy.Status.State),
+		Paused: policy.Spec.Paused,
+		Ready:  string(metav1.ConditionUnknown),
+	}
+	if ready := meta.FindStatusCondition(policy.Status.Conditions, consts.RBLNConditionTypeReady); ready != nil {
+		status.Ready = string(ready.Status)
+		status.Message = ready.Message
+	}
+	for _, component := range policy.Status.Components {
+		status.Components = append(status.Components, ComponentStatus{
+			Name:            component.Name,
+			Namespace:       component.Namespace,
+			ManagementState: string(component.ManagementState),
+			State:           string(component.State),
+			Message:         conditionMessage(component.Condition),
+		})
+	}
+	return status, nil
+}
+
+// GetNodeStatuses returns the state of the nodes labelled by the operator or advertising NPU
+// resources. Pods of the operator namespace are reported as issues of their node while not ready.
+func GetNodeStatuses(ctx context.Context, c client.Reader, namespace string) ([]NodeStatus, error) {
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	policy, err := getClusterPolicy(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	defaultWorkload := ""
+	remediations := map[string]rblnv1beta1.NodeRemediationStatus{}
+	if policy != nil {
+		defaultWorkload = policy.Spec.WorkloadType
+		for _, remediation := range policy.Status.Remediations {
+			remediations[remediation.NodeName] = remediation
+		}
+	}
+
+	driverSets, err := listDriverDaemonSets(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	podIssues := map[string][]string{}
+	if namespace != "" {
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.Spec.NodeName == "" {
+				continue
+			}
+			if issue := podIssue(pod); issue != "" {
+				podIssues[pod.Spec.NodeName] = append(podIssues[pod.Spec.NodeName], issue)
+			}
+		}
+	}
+
+	statuses := make([]NodeStatus, 0, len(nodes.Items))
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		_, labelled := node.Labels[consts.RBLNPresentLabelKey]
+		resources := npuResources(node)
+		if !labelled && len(resources) == 0 {
+			continue
+		}
+		status := NodeStatus{
+			Name:           node.Name,
+			Present:        node.Labels[consts.RBLNPresentLabelKey] == "true",
+			WorkloadConfig: node.Labels[consts.RBLNWorkloadConfigLabelKey],
+			Resources:      resources,
+			DriverPool:     driverPool(node, driverSets),
+			Health:         Unknown,
+		}
+		if status.WorkloadConfig == "" {
+			status.WorkloadConfig = defaultWorkload
+		}
+
+		for _, cond := range node.Status.Conditions {
+			switch {
+			case cond.Type == corev1.NodeReady && cond.Status != corev1.ConditionTrue:
+				status.Issues = append(status.Issues, fmt.Sprintf("node is not ready: %s", cond.Reason))
+			case cond.Type == consts.RBLNNodeConditionTypeNPUHealthy && cond.Status == corev1.ConditionTrue:
+				status.Health = Healthy
+			case cond.Type == consts.RBLNNodeConditionTypeNPUHealthy && cond.Status == corev1.ConditionFalse:
+				status.Health = Unhealthy
+				status.Issues = append(status.Issues, cond.Message)
+			}
+		}
+		if remediation, ok := remediations[node.Name]; ok && remediation.Phase != rblnv1beta1.RemediationSucceeded {
+			status.Issues = append(status.Issues, fmt.Sprintf("remediation %s", remediation.Phase))
+		}
+		status.Issues = append(status.Issues, podIssues[node.Name]...)
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses, nil
+}
+
+// GetDriverStatuses returns the state of every RBLNDriver and the DaemonSets of its node pools.
+func GetDriverStatuses(ctx context.Context, c client.Reader) ([]DriverStatus, error) {
+	drivers := &rebellionsaiv1alpha1.RBLNDriverList{}
+	if err := c.List(ctx, drivers); err != nil {
+		return nil, fmt.Errorf("failed to list RBLNDriver: %w", err)
+	}
+	driverSets, err := listDriverDaemonSets(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]DriverStatus, 0, len(drivers.Items))
+	for i := range drivers.Items {
+		driver := &drivers.Items[i]
+		status := DriverStatus{
+			Name:            driver.Name,
+			ManagementState: string(driver.Spec.ManagementState),
+			State:           string(driver.Status.State),
+			Message:         conditionMessage(driver.Status.Conditions),
+		}
+		for j := range driverSets {
+			ds := &driverSets[j]
+			if ds.Labels[driverInstanceLabelKey] != driver.Name {
+				continue
+			}
+			status.Pools = append(status.Pools, PoolStatus{
+				Name:      ds.Labels[nodePoolLabelKey],
+				DaemonSet: ds.Namespace + "/" + ds.Name,
+				Desired:   ds.Status.DesiredNumberScheduled,
+				Ready:     ds.Status.NumberReady,
+				UpToDate:  ds.Status.UpdatedNumberScheduled,
+				Images:    poolImages(driver, ds),
+			})
+		}
+		sort.Slice(status.Pools, func(i, j int) bool { return status.Pools[i].Name < status.Pools[j].Name })
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses, nil
+}
+
+func getClusterPolicy(ctx context.Context, c client.Reader) (*rblnv1beta1.RBLNClusterPolicy, error) {
+	policies := &rblnv1beta1.RBLNClusterPolicyList{}
+	if err := c.List(ctx, policies); err != nil {
+		return nil, fmt.Errorf("failed to list RBLNClusterPolicy: %w", err)
+	}
+	if len(policies.Items) == 0 {
+		return nil, nil
+	}
+	return &policies.Items[0], nil
+}
+
+func listDriverDaemonSets(ctx context.Context, c client.Reader) ([]appsv1.DaemonSet, error) {
+	selector, err := labels.Parse(driverInstanceLabelKey)
+	if err != nil {
+		return nil, err
+	}
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := c.List(ctx, daemonSets, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list driver DaemonSets: %w", err)
+	}
+	return daemonSets.Items, nil
+}
+
+// conditionMessage returns the message of the first condition that is not true.
+func conditionMessage(conditions []metav1.Condition) string {
+	for _, cond := range conditions {
+		if cond.Status != metav1.ConditionTrue && cond.Message != "" {
+			return cond.Message
+		}
+	}
+	return ""
+}
+
+func npuResources(node *corev1.Node) []string {
+	var resources []string
+	for name, quantity := range node.Status.Allocatable {
+		if strings.HasPrefix(string(name), resourcePrefix) {
+			resources = append(resources, fmt.Sprintf("%s=%s", name, quantity.String()))
+		}
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// driverPool returns the node pool of the driver DaemonSet whose node selector matches the node.
+func driverPool(node *corev1.Node, driverSets []appsv1.DaemonSet) string {
+	for i := range driverSets {
+		selector := driverSets[i].Spec.Template.Spec.NodeSelector
+		if len(selector) > 0 && labels.SelectorFromSet(selector).Matches(labels.Set(node.Labels)) {
+			return driverSets[i].Labels[nodePoolLabelKey]
+		}
+	}
+	return ""
+}
+
+// poolImages returns the images of the pool reported in the RBLNDriver status,
+// or those of the DaemonSet if the status has not caught up yet.
+func poolImages(driver *rebellionsaiv1alpha1.RBLNDriver, ds *appsv1.DaemonSet) []string {
+	var images []string
+	workload := fmt.Sprintf("DaemonSet/%s/%s", ds.Namespace, ds.Name)
+	for _, image := range driver.Status.Images {
+		if image.Workload == workload {
+			images = append(images, image.Image)
+		}
+	}
+	if len(images) == 0 {
+		for _, container := range ds.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+	}
+	return images
+}
+
+// podIssue describes why a pod is not ready, or returns an empty string if it is.
+func podIssue(pod *corev1.Pod) string {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return ""
+	case corev1.PodFailed, corev1.PodPending, corev1.PodUnknown:
+		reason := string(pod.Status.Phase)
+		if pod.Status.Reason != "" {
+			reason = pod.Status.Reason
+		}
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+				reason = fmt.Sprintf("%s %s", status.Name, status.State.Waiting.Reason)
+				break
+			}
+		}
+		return fmt.Sprintf("pod %s: %s", pod.Name, reason)
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			continue
+		}
+		reason := "not ready"
+		switch {
+		case status.State.Waiting != nil && status.State.Waiting.Reason != "":
+			reason = status.State.Waiting.Reason
+		case status.State.Terminated != nil && status.State.Terminated.Reason != "":
+			reason = status.State.Terminated.Reason
+		}
+		return fmt.Sprintf("pod %s: %s %s", pod.Name, status.Name, reason)
+	}
+	return ""
+}
@@ -0,0 +1,229 @@
+package fleet_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/fleet"
+)
+
+func TestFleet(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fleet Suite")
+}
+
+var _ = Describe("Fleet", func() {
+	const (
+		namespace = "rbln-system"
+		pool      = "rbln-driver-ubuntu22.04-5.15.0-100-generic"
+	)
+
+	var (
+		scheme  *runtime.Scheme
+		objects []client.Object
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(rebellionsaiv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		osLabels := map[string]string{
+			"feature.node.kubernetes.io/system-os_release.ID":         "ubuntu",
+			"feature.node.kubernetes.io/system-os_release.VERSION_ID": "22.04",
+			"feature.node.kubernetes.io/kernel-version.full":          "5.15.0-100-generic",
+		}
+		npuNode := func(name string, healthy corev1.ConditionStatus, message string) *corev1.Node {
+			nodeLabels := map[string]string{
+				consts.RBLNPresentLabelKey:        "true",
+				consts.RBLNWorkloadConfigLabelKey: consts.RBLNWorkloadConfigContainer,
+			}
+			for k, v := range osLabels {
+				nodeLabels[k] = v
+			}
+			return &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						"rebellions.ai/ATOM": resource.MustParse("4"),
+						"cpu":                resource.MustParse("32"),
+					},
+					Conditions: []corev1.NodeCondition{
+						{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+						{Type: consts.RBLNNodeConditionTypeNPUHealthy, Status: healthy, Message: message},
+					},
+				},
+			}
+		}
+
+		objects = []client.Object{
+			&rblnv1beta1.RBLNClusterPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "rbln-cluster-policy"},
+				Spec:       rblnv1beta1.RBLNClusterPolicySpec{WorkloadType: consts.RBLNWorkloadConfigContainer},
+				Status: rblnv1beta1.RBLNClusterPolicyStatus{
+					State: rblnv1beta1.ClusterNotReady,
+					Conditions: []metav1.Condition{{
+						Type:    consts.RBLNConditionTypeReady,
+						Status:  metav1.ConditionFalse,
+						Message: "device-plugin is not ready",
+					}},
+					Components: []rblnv1beta1.RBLNComponentStatus{
+						{
+							Name:            "device-plugin",
+							Namespace:       namespace,
+							State:           rblnv1beta1.ComponentStateNotReady,
+							ManagementState: rblnv1beta1.ManagementStateManaged,
+							Condition: []metav1.Condition{{
+								Type:    "Ready",
+								Status:  metav1.ConditionFalse,
+								Message: "1 of 2 pods are not ready",
+							}},
+						},
+						{Name: "metrics-exporter", Namespace: namespace, State: rblnv1beta1.ComponentStateReady},
+					},
+					Remediations: []rblnv1beta1.NodeRemediationStatus{
+						{NodeName: "node-2", Phase: rblnv1beta1.RemediationInProgress},
+					},
+				},
+			},
+			&rebellionsaiv1alpha1.RBLNDriver{
+				ObjectMeta: metav1.ObjectMeta{Name: "rbln-driver"},
+				Spec:       rebellionsaiv1alpha1.RBLNDriverSpec{ManagementState: rebellionsaiv1alpha1.ManagementStateManaged},
+				Status: rebellionsaiv1alpha1.RBLNDriverStatus{
+					State: rebellionsaiv1alpha1.DriverStateReady,
+					Images: []rebellionsaiv1alpha1.ContainerImage{{
+						Workload:  "DaemonSet/" + namespace + "/" + pool,
+						Container: "rbln-driver-container",
+						Image:     "mirror.corp/rebellions/rbln-driver:3.0.0",
+					}},
+				},
+			},
+			&rebellionsaiv1alpha1.RBLNDriver{
+				ObjectMeta: metav1.ObjectMeta{Name: "rbln-driver-rhel"},
+				Status: rebellionsaiv1alpha1.RBLNDriverStatus{
+					State: rebellionsaiv1alpha1.DriverStateNotReady,
+					Conditions: []metav1.Condition{{
+						Type:    "NodePoolsResolved",
+						Status:  metav1.ConditionFalse,
+						Message: "Node pools skipped: rhel9.4",
+					}},
+				},
+			},
+			&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      pool,
+					Namespace: namespace,
+					Labels: map[string]string{
+						"rebellions.ai/driver-instance": "rbln-driver",
+						"nodepool":                      pool,
+					},
+				},
+				Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					NodeSelector: osLabels,
+					Containers:   []corev1.Container{{Name: "rbln-driver-container", Image: "rebellions/rbln-driver:3.0.0"}},
+				}}},
+				Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 1, UpdatedNumberScheduled: 2},
+			},
+			npuNode("node-1", corev1.ConditionTrue, ""),
+			npuNode("node-2", corev1.ConditionFalse, "card 0: heartbeat lost"),
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cpu-node"}},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "rbln-device-plugin-abcde", Namespace: namespace},
+				Spec:       corev1.PodSpec{NodeName: "node-1"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  "device-plugin",
+						State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					}},
+				},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "rbln-validator-fghij", Namespace: namespace},
+				Spec:       corev1.PodSpec{NodeName: "node-2"},
+				Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+			},
+		}
+	})
+
+	newClient := func() client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	}
+
+	It("should report the cluster policy and the readiness of its components", func() {
+		status, err := fleet.GetClusterStatus(context.Background(), newClient())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.State).To(Equal("notReady"))
+		Expect(status.Ready).To(Equal("False"))
+		Expect(status.Message).To(Equal("device-plugin is not ready"))
+		Expect(status.Components).To(Equal([]fleet.ComponentStatus{
+			{Name: "device-plugin", Namespace: namespace, ManagementState: "Managed", State: "notReady", Message: "1 of 2 pods are not ready"},
+			{Name: "metrics-exporter", Namespace: namespace, State: "ready"},
+		}))
+	})
+
+	It("should return nil without a cluster policy", func() {
+		status, err := fleet.GetClusterStatus(context.Background(), fake.NewClientBuilder().WithScheme(scheme).Build())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(BeNil())
+	})
+
+	It("should report the driver pool and the issues of NPU nodes", func() {
+		nodes, err := fleet.GetNodeStatuses(context.Background(), newClient(), namespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodes).To(HaveLen(2))
+
+		Expect(nodes[0].Name).To(Equal("node-1"))
+		Expect(nodes[0].Present).To(BeTrue())
+		Expect(nodes[0].WorkloadConfig).To(Equal(consts.RBLNWorkloadConfigContainer))
+		Expect(nodes[0].Resources).To(Equal([]string{"rebellions.ai/ATOM=4"}))
+		Expect(nodes[0].DriverPool).To(Equal(pool))
+		Expect(nodes[0].Health).To(Equal(fleet.Healthy))
+		Expect(nodes[0].Issues).To(Equal([]string{"pod rbln-device-plugin-abcde: device-plugin CrashLoopBackOff"}))
+		Expect(nodes[0].Healthy()).To(BeFalse())
+
+		Expect(nodes[1].Name).To(Equal("node-2"))
+		Expect(nodes[1].Health).To(Equal(fleet.Unhealthy))
+		Expect(nodes[1].Issues).To(Equal([]string{"card 0: heartbeat lost", "remediation InProgress"}))
+	})
+
+	It("should report the pools and images of every driver", func() {
+		drivers, err := fleet.GetDriverStatuses(context.Background(), newClient())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drivers).To(Equal([]fleet.DriverStatus{
+			{
+				Name:            "rbln-driver",
+				ManagementState: "Managed",
+				State:           "ready",
+				Pools: []fleet.PoolStatus{{
+					Name:      pool,
+					DaemonSet: namespace + "/" + pool,
+					Desired:   2,
+					Ready:     1,
+					UpToDate:  2,
+					Images:    []string{"mirror.corp/rebellions/rbln-driver:3.0.0"},
+				}},
+			},
+			{
+				Name:    "rbln-driver-rhel",
+				State:   "notReady",
+				Message: "Node pools skipped: rhel9.4",
+			},
+		}))
+	})
+})
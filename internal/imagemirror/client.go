@@ -0,0 +1,140 @@
+package imagemirror
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Image is the effective image of a container of a managed workload.
+type Image struct {
+	Workload  string
+	Container string
+	Image     string
+}
+
+// Client rewrites the images of the workloads written through it and records the
+// effective images of the workloads it reads or writes.
+type Client struct {
+	client.Client
+	rewriter *Rewriter
+
+	mu     sync.Mutex
+	images map[string]Image
+}
+
+func NewClient(c client.Client, rewriter *Rewriter) *Client {
+	return &Client{
+		Client:   c,
+		rewriter: rewriter,
+		images:   map[string]Image{},
+	}
+}
+
+func (c *Client) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.Client.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+	c.record(obj)
+	return nil
+}
+
+func (c *Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.rewrite(obj)
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.record(obj)
+	return nil
+}
+
+func (c *Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.rewrite(obj)
+	if err := c.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.record(obj)
+	return nil
+}
+
+func (c *Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.rewrite(obj)
+	// the builders render source images; once rewritten the object often equals the live one
+	if data, err := patch.Data(obj); err == nil && patch.Type() != types.ApplyPatchType && string(data) == "{}" {
+		c.record(obj)
+		return nil
+	}
+	if err := c.Client.Patch(ctx, obj, patch, opts...); err != nil {
+		return err
+	}
+	c.record(obj)
+	return nil
+}
+
+// Images returns the recorded images sorted by workload and container.
+func (c *Client) Images() []Image {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	images := make([]Image, 0, len(c.images))
+	for _, image := range c.images {
+		images = append(images, image)
+	}
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Workload != images[j].Workload {
+			return images[i].Workload < images[j].Workload
+		}
+		return images[i].Container < images[j].Container
+	})
+	return images
+}
+
+func (c *Client) rewrite(obj client.Object) {
+	if _, spec := podSpecOf(obj); spec != nil {
+		c.rewriter.RewritePodSpec(spec)
+	}
+}
+
+func (c *Client) record(obj client.Object) {
+	kind, spec := podSpecOf(obj)
+	if spec == nil {
+		return
+	}
+	workload := fmt.Sprintf("%s/%s", kind, obj.GetName())
+	if obj.GetNamespace() != "" {
+		workload = fmt.Sprintf("%s/%s/%s", kind, obj.GetNamespace(), obj.GetName())
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for _, container := range containers {
+			c.images[workload+"/"+container.Name] = Image{
+				Workload:  workload,
+				Container: container.Name,
+				Image:     container.Image,
+			}
+		}
+	}
+}
+
+func podSpecOf(obj client.Object) (string, *corev1.PodSpec) {
+	switch o := obj.(type) {
+	case *appsv1.DaemonSet:
+		return "DaemonSet", &o.Spec.Template.Spec
+	case *appsv1.Deployment:
+		return "Deployment", &o.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		return "StatefulSet", &o.Spec.Template.Spec
+	case *batchv1.Job:
+		return "Job", &o.Spec.Template.Spec
+	case *corev1.Pod:
+		return "Pod", &o.Spec
+	}
+	return "", nil
+}
@@ -0,0 +1,122 @@
+// Package imagemirror rewrites the container images of managed workloads to their mirrors,
+// so air-gapped clusters configure the mirror registry once instead of every component.
+package imagemirror
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+)
+
+// Scope restricts a mapping to digest or tag references, as OpenShift mirror sets do.
+type Scope string
+
+const (
+	// ScopeAll applies the mapping to every reference.
+	ScopeAll Scope = ""
+	// ScopeDigest applies the mapping to digest references only (ImageDigestMirrorSet).
+	ScopeDigest Scope = "digest"
+	// ScopeTag applies the mapping to tag references only (ImageTagMirrorSet).
+	ScopeTag Scope = "tag"
+)
+
+// Mapping replaces the Source prefix of an image reference with Mirror.
+type Mapping struct {
+	Source string
+	Mirror string
+	Scope  Scope
+	// Runtime marks a source the container runtime already pulls from Mirror, as CRI-O does
+	// for the OpenShift mirror sets. Its images are kept, so the runtime resolves them.
+	Runtime bool
+}
+
+// Rewriter applies the most specific mapping matching an image.
+type Rewriter struct {
+	mappings []Mapping
+}
+
+func NewRewriter(mappings []Mapping) *Rewriter {
+	sorted := make([]Mapping, 0, len(mappings))
+	for _, m := range mappings {
+		m.Source = strings.TrimSuffix(strings.TrimSpace(m.Source), "/")
+		m.Mirror = strings.TrimSuffix(strings.TrimSpace(m.Mirror), "/")
+		if m.Source == "" || m.Mirror == "" {
+			continue
+		}
+		sorted = append(sorted, m)
+	}
+	// the longest source wins, e.g. repo.rebellions.ai/rebellions over repo.rebellions.ai
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i].Source) > len(sorted[j].Source) })
+	return &Rewriter{mappings: sorted}
+}
+
+// Rewrite returns the mirrored reference of image, or image if no mapping matches.
+func (r *Rewriter) Rewrite(image string) string {
+	if r == nil {
+		return image
+	}
+	digest := strings.Contains(image, "@")
+	for _, m := range r.mappings {
+		if (m.Scope == ScopeDigest && !digest) || (m.Scope == ScopeTag && digest) {
+			continue
+		}
+		if !hasRepositoryPrefix(image, m.Source) {
+			continue
+		}
+		if m.Runtime {
+			return image
+		}
+		return m.Mirror + image[len(m.Source):]
+	}
+	return image
+}
+
+// RewritePodSpec rewrites the images of every container of spec in place.
+func (r *Rewriter) RewritePodSpec(spec *corev1.PodSpec) {
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].Image = r.Rewrite(spec.InitContainers[i].Image)
+	}
+	for i := range spec.Containers {
+		spec.Containers[i].Image = r.Rewrite(spec.Containers[i].Image)
+	}
+}
+
+// hasRepositoryPrefix matches whole path components, so repo.io/a does not match repo.io/ab.
+func hasRepositoryPrefix(image, source string) bool {
+	if !strings.HasPrefix(image, source) {
+		return false
+	}
+	if len(image) == len(source) {
+		return true
+	}
+	switch image[len(source)] {
+	case '/', ':', '@':
+		return true
+	}
+	return false
+}
+
+// Load builds the rewriter of the image mirrors of a cluster policy. On OpenShift the sources
+// of the cluster mirror sets are left to CRI-O, unless an explicit mirror of the same source
+// overrides them.
+func Load(ctx context.Context, c client.Reader, spec *rblnv1beta1.ImageMirrorsSpec, openshiftVersion string) (*Rewriter, error) {
+	var mappings []Mapping
+	if spec != nil {
+		for _, m := range spec.Mirrors {
+			mappings = append(mappings, Mapping{Source: m.Source, Mirror: m.Mirror})
+		}
+	}
+	if openshiftVersion != "" && spec.IsOpenShiftMirrorSetsEnabled() {
+		openshift, err := OpenShiftMappings(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, openshift...)
+	}
+	return NewRewriter(mappings), nil
+}
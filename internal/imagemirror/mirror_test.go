@@ -0,0 +1,157 @@
+package imagemirror_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/imagemirror"
+)
+
+func TestImageMirror(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Image Mirror Suite")
+}
+
+var _ = Describe("ImageMirror", func() {
+	Describe("Rewrite", func() {
+		rewriter := imagemirror.NewRewriter([]imagemirror.Mapping{
+			{Source: "docker.io", Mirror: "mirror.local/docker"},
+			{Source: "docker.io/rebellions", Mirror: "mirror.local/rbln/"},
+			{Source: "quay.io/digest", Mirror: "mirror.local/digest", Scope: imagemirror.ScopeDigest},
+			{Source: "quay.io/tag", Mirror: "mirror.local/tag", Scope: imagemirror.ScopeTag},
+		})
+
+		DescribeTable("should apply the most specific mapping",
+			func(image, expected string) {
+				Expect(rewriter.Rewrite(image)).To(Equal(expected))
+			},
+			Entry("longest source", "docker.io/rebellions/rbln-driver:3.0.0", "mirror.local/rbln/rbln-driver:3.0.0"),
+			Entry("shorter source", "docker.io/library/busybox:1.36", "mirror.local/docker/library/busybox:1.36"),
+			Entry("partial component", "docker.io/rebellions-sw/tool:1", "mirror.local/docker/rebellions-sw/tool:1"),
+			Entry("no match", "registry.k8s.io/pause:3.9", "registry.k8s.io/pause:3.9"),
+			Entry("digest mapping with digest", "quay.io/digest/img@sha256:abc", "mirror.local/digest/img@sha256:abc"),
+			Entry("digest mapping with tag", "quay.io/digest/img:1", "quay.io/digest/img:1"),
+			Entry("tag mapping with tag", "quay.io/tag/img:1", "mirror.local/tag/img:1"),
+			Entry("tag mapping with digest", "quay.io/tag/img@sha256:abc", "quay.io/tag/img@sha256:abc"),
+		)
+
+		It("should keep the images of sources the container runtime mirrors", func() {
+			rewriter := imagemirror.NewRewriter([]imagemirror.Mapping{
+				{Source: "docker.io", Mirror: "mirror.local/docker"},
+				{Source: "docker.io/rebellions", Mirror: "mirror.local/idms", Runtime: true},
+				{Source: "quay.io/rebellions", Mirror: "mirror.local/quay"},
+				{Source: "quay.io/rebellions", Mirror: "mirror.local/idms", Runtime: true},
+			})
+			Expect(rewriter.Rewrite("docker.io/rebellions/rbln-driver:3.0.0")).To(Equal("docker.io/rebellions/rbln-driver:3.0.0"))
+			Expect(rewriter.Rewrite("docker.io/library/busybox:1.36")).To(Equal("mirror.local/docker/library/busybox:1.36"))
+			Expect(rewriter.Rewrite("quay.io/rebellions/validator:1")).To(Equal("mirror.local/quay/validator:1"))
+		})
+
+		It("should keep images without a rewriter", func() {
+			var nilRewriter *imagemirror.Rewriter
+			Expect(nilRewriter.Rewrite("docker.io/rebellions/rbln-driver:3.0.0")).To(Equal("docker.io/rebellions/rbln-driver:3.0.0"))
+		})
+	})
+
+	Describe("Load", func() {
+		It("should use the explicit mirrors off OpenShift", func() {
+			rewriter, err := imagemirror.Load(context.Background(), fake.NewClientBuilder().Build(), &rblnv1beta1.ImageMirrorsSpec{
+				Mirrors: []rblnv1beta1.ImageMirror{{Source: "docker.io/rebellions", Mirror: "mirror.local/rbln"}},
+			}, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rewriter.Rewrite("docker.io/rebellions/validator:1")).To(Equal("mirror.local/rbln/validator:1"))
+		})
+
+		It("should leave the sources of the OpenShift mirror sets to CRI-O", func() {
+			idms := &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"imageDigestMirrors": []interface{}{
+						map[string]interface{}{"source": "docker.io/rebellions", "mirrors": []interface{}{"mirror.local/idms"}},
+					},
+				},
+			}}
+			idms.SetGroupVersionKind(schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "ImageDigestMirrorSet"})
+			idms.SetName("rebellions")
+			c := fake.NewClientBuilder().WithObjects(idms).Build()
+
+			rewriter, err := imagemirror.Load(context.Background(), c, &rblnv1beta1.ImageMirrorsSpec{
+				Mirrors: []rblnv1beta1.ImageMirror{{Source: "docker.io", Mirror: "mirror.local/docker"}},
+			}, "4.14")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rewriter.Rewrite("docker.io/rebellions/validator@sha256:abc")).To(Equal("docker.io/rebellions/validator@sha256:abc"))
+			Expect(rewriter.Rewrite("docker.io/library/busybox:1.36")).To(Equal("mirror.local/docker/library/busybox:1.36"))
+		})
+	})
+
+	Describe("Client", func() {
+		var scheme *runtime.Scheme
+
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		})
+
+		daemonSet := func(image string) *appsv1.DaemonSet {
+			return &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "rbln-driver", Namespace: "rbln-system"},
+				Spec: appsv1.DaemonSetSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							InitContainers: []corev1.Container{{Name: "init", Image: "docker.io/rebellions/validator:1"}},
+							Containers:     []corev1.Container{{Name: "driver", Image: image}},
+						},
+					},
+				},
+			}
+		}
+
+		It("should rewrite and record the images of patched workloads", func() {
+			rewriter := imagemirror.NewRewriter([]imagemirror.Mapping{{Source: "docker.io/rebellions", Mirror: "mirror.local/rbln"}})
+			c := imagemirror.NewClient(fake.NewClientBuilder().WithScheme(scheme).Build(), rewriter)
+
+			for _, version := range []string{"3.0.0", "3.0.0", "3.1.0"} {
+				ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "rbln-driver", Namespace: "rbln-system"}}
+				_, err := controllerutil.CreateOrPatch(context.Background(), c, ds, func() error {
+					ds.Spec = daemonSet("docker.io/rebellions/driver:" + version).Spec
+					return nil
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			live := &appsv1.DaemonSet{}
+			Expect(c.Client.Get(context.Background(), client.ObjectKey{Name: "rbln-driver", Namespace: "rbln-system"}, live)).To(Succeed())
+			Expect(live.Spec.Template.Spec.InitContainers[0].Image).To(Equal("mirror.local/rbln/validator:1"))
+			Expect(live.Spec.Template.Spec.Containers[0].Image).To(Equal("mirror.local/rbln/driver:3.1.0"))
+
+			Expect(c.Images()).To(HaveExactElements(
+				imagemirror.Image{Workload: "DaemonSet/rbln-system/rbln-driver", Container: "driver", Image: "mirror.local/rbln/driver:3.1.0"},
+				imagemirror.Image{Workload: "DaemonSet/rbln-system/rbln-driver", Container: "init", Image: "mirror.local/rbln/validator:1"},
+			))
+		})
+
+		It("should record the images of workloads it reads", func() {
+			c := imagemirror.NewClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(daemonSet("docker.io/rebellions/driver:3.0.0")).Build(), nil)
+
+			Expect(c.Get(context.Background(), client.ObjectKey{Name: "rbln-driver", Namespace: "rbln-system"}, &appsv1.DaemonSet{})).To(Succeed())
+			Expect(c.Images()).To(ContainElement(imagemirror.Image{
+				Workload:  "DaemonSet/rbln-system/rbln-driver",
+				Container: "driver",
+				Image:     "docker.io/rebellions/driver:3.0.0",
+			}))
+		})
+	})
+})
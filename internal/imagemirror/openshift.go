@@ -0,0 +1,57 @@
+package imagemirror
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var mirrorSetKinds = []struct {
+	kind  string
+	field string
+	scope Scope
+}{
+	{kind: "ImageDigestMirrorSetList", field: "imageDigestMirrors", scope: ScopeDigest},
+	{kind: "ImageTagMirrorSetList", field: "imageTagMirrors", scope: ScopeTag},
+}
+
+// OpenShiftMappings returns the runtime mappings of the ImageDigestMirrorSets and
+// ImageTagMirrorSets of the cluster. The first mirror of each source is recorded, as CRI-O
+// tries them in order.
+func OpenShiftMappings(ctx context.Context, c client.Reader) ([]Mapping, error) {
+	var mappings []Mapping
+	for _, k := range mirrorSetKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: k.kind})
+		if err := c.List(ctx, list); err != nil {
+			// mirror sets were added in OpenShift 4.13
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", k.kind, err)
+		}
+		for _, item := range list.Items {
+			entries, _, err := unstructured.NestedSlice(item.Object, "spec", k.field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %s: %w", k.kind, item.GetName(), err)
+			}
+			for _, entry := range entries {
+				fields, ok := entry.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				source, _, _ := unstructured.NestedString(fields, "source")
+				mirrors, _, _ := unstructured.NestedStringSlice(fields, "mirrors")
+				if source == "" || len(mirrors) == 0 {
+					continue
+				}
+				mappings = append(mappings, Mapping{Source: source, Mirror: mirrors[0], Scope: k.scope, Runtime: true})
+			}
+		}
+	}
+	return mappings, nil
+}
@@ -0,0 +1,423 @@
+// Package mustgather collects a support bundle of the operator: its custom resources, the
+// operand workloads and their logs, the NPU nodes, events and the validation status files of
+// each node. Collection is best effort; failures are recorded in the manifest of the bundle.
+package mustgather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+)
+
+const (
+	// ManifestPath is the path of the manifest listing the files of the bundle and the
+	// failures of the collection
+	ManifestPath = "manifest.json"
+
+	// DefaultLogLimitBytes bounds the log collected per container
+	DefaultLogLimitBytes = 10 << 20
+
+	validationsDir        = "/run/rbln/validations"
+	npuResourcePrefix     = "rebellions.ai/"
+	nodeEventsNamespace   = "default"
+	validationsOutputFile = "validations.txt"
+)
+
+// operatorLabels select the operator Deployment installed by the chart.
+var operatorLabels = map[string]string{
+	"app.kubernetes.io/name": "rbln-npu-operator",
+	"control-plane":          "controller-manager",
+}
+
+// validationsCommand prints every status file of the validations directory with its name.
+var validationsCommand = []string{"sh", "-c",
+	`cd ` + validationsDir + ` && for f in *; do [ -f "$f" ] && { echo "==> $f <=="; cat "$f"; echo; }; done`}
+
+// Options configures a collection.
+type Options struct {
+	// Namespace of the operator and its operands
+	Namespace string
+	// LogLimitBytes bounds the log collected per container, DefaultLogLimitBytes if 0
+	LogLimitBytes int64
+}
+
+// Manifest describes a bundle.
+type Manifest struct {
+	CollectedAt time.Time      `json:"collectedAt"`
+	Namespace   string         `json:"namespace"`
+	Files       []ManifestFile `json:"files"`
+	Errors      []string       `json:"errors,omitempty"`
+}
+
+// ManifestFile is a file of a bundle.
+type ManifestFile struct {
+	Path string `json:"path"`
+	Size int    `json:"size"`
+}
+
+type gatherer struct {
+	client client.Reader
+	pods   PodReader
+	scheme *runtime.Scheme
+	sink   Sink
+	opts   Options
+
+	manifest Manifest
+}
+
+// Gather collects the bundle into sink and closes it. Failures to collect a part of the bundle
+// are recorded in its manifest; only failures to write the bundle are returned.
+func Gather(ctx context.Context, c client.Reader, pods PodReader, scheme *runtime.Scheme, sink Sink, opts Options) error {
+	if opts.LogLimitBytes == 0 {
+		opts.LogLimitBytes = DefaultLogLimitBytes
+	}
+	g := &gatherer{
+		client: c,
+		pods:   pods,
+		scheme: scheme,
+		sink:   sink,
+		opts:   opts,
+		manifest: Manifest{
+			CollectedAt: time.Now().UTC(),
+			Namespace:   opts.Namespace,
+		},
+	}
+
+	err := g.gather(ctx)
+	if err == nil {
+		err = g.writeManifest()
+	}
+	if closeErr := sink.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (g *gatherer) gather(ctx context.Context) error {
+	for _, list := range []client.ObjectList{
+		&rblnv1beta1.RBLNClusterPolicyList{},
+		&rebellionsaiv1alpha1.RBLNDriverList{},
+		&rebellionsaiv1alpha1.RBLNFirmwareList{},
+	} {
+		if err := g.gatherList(ctx, list); err != nil {
+			return err
+		}
+	}
+
+	nodes, err := g.gatherNodes(ctx)
+	if err != nil {
+		return err
+	}
+	if err := g.gatherNodeEvents(ctx, nodes); err != nil {
+		return err
+	}
+
+	if g.opts.Namespace == "" {
+		g.recordError(fmt.Errorf("operator namespace is unknown; skip the operand workloads"))
+		return nil
+	}
+	inNamespace := client.InNamespace(g.opts.Namespace)
+	for _, list := range []client.ObjectList{
+		&appsv1.DaemonSetList{},
+		&appsv1.DeploymentList{},
+		&batchv1.JobList{},
+		&corev1.ServiceList{},
+		&corev1.ConfigMapList{},
+		&corev1.SecretList{},
+		&corev1.EventList{},
+	} {
+		if err := g.gatherList(ctx, list, inNamespace); err != nil {
+			return err
+		}
+	}
+	return g.gatherPods(ctx)
+}
+
+// gatherList writes every object of list, one file per object.
+func (g *gatherer) gatherList(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := g.client.List(ctx, list, opts...); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		g.recordError(fmt.Errorf("failed to list %T: %w", list, err))
+		return nil
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if obj, ok := item.(client.Object); ok {
+			if err := g.writeObject(obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// gatherNodes writes the NPU nodes and a summary of their labels and allocatable NPUs.
+func (g *gatherer) gatherNodes(ctx context.Context) ([]corev1.Node, error) {
+	nodes := &corev1.NodeList{}
+	if err := g.client.List(ctx, nodes, client.MatchingLabels{consts.RBLNPresentLabelKey: "true"}); err != nil {
+		g.recordError(fmt.Errorf("failed to list nodes: %w", err))
+		return nil, nil
+	}
+
+	var summary strings.Builder
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if err := g.writeObject(node); err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&summary, "%s\n  allocatable:\n", node.Name)
+		for _, name := range sortedKeys(node.Status.Allocatable) {
+			if strings.HasPrefix(string(name), npuResourcePrefix) {
+				quantity := node.Status.Allocatable[name]
+				fmt.Fprintf(&summary, "    %s: %s\n", name, quantity.String())
+			}
+		}
+		fmt.Fprintf(&summary, "  labels:\n")
+		labelKeys := make([]string, 0, len(node.Labels))
+		for key := range node.Labels {
+			labelKeys = append(labelKeys, key)
+		}
+		sort.Strings(labelKeys)
+		for _, key := range labelKeys {
+			fmt.Fprintf(&summary, "    %s=%s\n", key, node.Labels[key])
+		}
+	}
+	return nodes.Items, g.writeFile("nodes.txt", []byte(summary.String()))
+}
+
+// gatherNodeEvents writes the events of the NPU nodes, which are recorded in the default namespace.
+func (g *gatherer) gatherNodeEvents(ctx context.Context, nodes []corev1.Node) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	names := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		names[node.Name] = true
+	}
+
+	events := &corev1.EventList{}
+	if err := g.client.List(ctx, events, client.InNamespace(nodeEventsNamespace)); err != nil {
+		g.recordError(fmt.Errorf("failed to list node events: %w", err))
+		return nil
+	}
+	for i := range events.Items {
+		event := &events.Items[i]
+		if event.InvolvedObject.Kind == "Node" && names[event.InvolvedObject.Name] {
+			if err := g.writeObject(event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// gatherPods writes the operand pods, the logs of their containers and, once per node, the
+// validation status files read through a container mounting them.
+func (g *gatherer) gatherPods(ctx context.Context) error {
+	pods := &corev1.PodList{}
+	if err := g.client.List(ctx, pods, client.InNamespace(g.opts.Namespace)); err != nil {
+		g.recordError(fmt.Errorf("failed to list pods: %w", err))
+		return nil
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+
+	validatedNodes := map[string]bool{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if err := g.writeObject(pod); err != nil {
+			return err
+		}
+		if g.pods == nil {
+			continue
+		}
+		if err := g.gatherLogs(ctx, pod); err != nil {
+			return err
+		}
+
+		if validatedNodes[pod.Spec.NodeName] {
+			continue
+		}
+		if container := validationsContainer(pod); container != "" {
+			validatedNodes[pod.Spec.NodeName] = true
+			out, err := g.pods.Exec(ctx, pod.Namespace, pod.Name, container, validationsCommand)
+			if err != nil {
+				g.recordError(fmt.Errorf("failed to read validation status files of node %s: %w", pod.Spec.NodeName, err))
+				continue
+			}
+			if err := g.writeFile(path.Join("nodes", pod.Spec.NodeName, validationsOutputFile), redactText(out)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *gatherer) gatherLogs(ctx context.Context, pod *corev1.Pod) error {
+	statuses := map[string]corev1.ContainerStatus{}
+	for _, status := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		statuses[status.Name] = status
+	}
+
+	dir := path.Join("namespaces", pod.Namespace, "pods", pod.Name)
+	for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		status, started := statuses[container.Name]
+		if !started || (status.State.Waiting != nil && status.RestartCount == 0) {
+			continue
+		}
+		previousLogs := []bool{false}
+		if status.RestartCount > 0 {
+			previousLogs = append(previousLogs, true)
+		}
+		for _, previous := range previousLogs {
+			file := "current.log"
+			if previous {
+				file = "previous.log"
+			}
+			out, err := g.pods.Logs(ctx, pod.Namespace, pod.Name, container.Name, previous, g.opts.LogLimitBytes)
+			if err != nil {
+				g.recordError(fmt.Errorf("failed to get logs of %s/%s container %s: %w", pod.Namespace, pod.Name, container.Name, err))
+				continue
+			}
+			if err := g.writeFile(path.Join(dir, container.Name, file), redactText(out)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validationsContainer returns the running container of pod mounting the validation status files.
+func validationsContainer(pod *corev1.Pod) string {
+	if pod.Status.Phase != corev1.PodRunning || pod.Spec.NodeName == "" {
+		return ""
+	}
+	running := map[string]bool{}
+	for _, status := range pod.Status.ContainerStatuses {
+		running[status.Name] = status.State.Running != nil
+	}
+	for _, container := range pod.Spec.Containers {
+		if !running[container.Name] {
+			continue
+		}
+		for _, mount := range container.VolumeMounts {
+			if mount.MountPath == validationsDir {
+				return container.Name
+			}
+		}
+	}
+	return ""
+}
+
+// writeObject writes obj as YAML at cluster-scoped/<resource>/<name>.yaml or
+// namespaces/<namespace>/<resource>/<name>.yaml, with sensitive values redacted.
+func (g *gatherer) writeObject(obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, g.scheme)
+	if err != nil {
+		return err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	u.SetManagedFields(nil)
+	redactObject(u.Object)
+
+	data, err := yaml.Marshal(u.Object)
+	if err != nil {
+		return err
+	}
+
+	resource := strings.ToLower(gvk.Kind) + "s"
+	if strings.HasSuffix(resource, "ys") {
+		resource = strings.TrimSuffix(resource, "ys") + "ies"
+	}
+	var file string
+	switch {
+	case gvk.Kind == "Pod":
+		file = path.Join("namespaces", obj.GetNamespace(), "pods", obj.GetName(), obj.GetName()+".yaml")
+	case obj.GetNamespace() == "":
+		file = path.Join("cluster-scoped", resource, obj.GetName()+".yaml")
+	default:
+		file = path.Join("namespaces", obj.GetNamespace(), resource, obj.GetName()+".yaml")
+	}
+	return g.writeFile(file, redactText(data))
+}
+
+func (g *gatherer) writeFile(file string, data []byte) error {
+	if err := g.sink.WriteFile(file, data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	g.manifest.Files = append(g.manifest.Files, ManifestFile{Path: file, Size: len(data)})
+	return nil
+}
+
+func (g *gatherer) writeManifest() error {
+	data, err := json.MarshalIndent(g.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return g.sink.WriteFile(ManifestPath, data)
+}
+
+func (g *gatherer) recordError(err error) {
+	g.manifest.Errors = append(g.manifest.Errors, err.Error())
+}
+
+func sortedKeys(resources corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// DetectNamespace returns the namespace of the operator: the namespace of the RBLNClusterPolicy,
+// or else the namespace of the operator Deployment installed by the chart. It returns "" when
+// neither is found.
+func DetectNamespace(ctx context.Context, c client.Reader) (string, error) {
+	policies := &rblnv1beta1.RBLNClusterPolicyList{}
+	if err := c.List(ctx, policies); err != nil && !meta.IsNoMatchError(err) {
+		return "", fmt.Errorf("failed to list RBLNClusterPolicy: %w", err)
+	}
+	for _, policy := range policies.Items {
+		if policy.Spec.Namespace != "" {
+			return policy.Spec.Namespace, nil
+		}
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments, client.MatchingLabels(operatorLabels)); err != nil {
+		return "", fmt.Errorf("failed to list operator Deployment: %w", err)
+	}
+	if len(deployments.Items) > 0 {
+		return deployments.Items[0].Namespace, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,80 @@
+package mustgather
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// PodReader reads what the object API does not serve: container logs and command output.
+type PodReader interface {
+	Logs(ctx context.Context, namespace, pod, container string, previous bool, limitBytes int64) ([]byte, error)
+	Exec(ctx context.Context, namespace, pod, container string, command []string) ([]byte, error)
+}
+
+type podReader struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+// NewPodReader returns a PodReader talking to the API server of config.
+func NewPodReader(config *rest.Config) (PodReader, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+	return &podReader{
+		config:    config,
+		clientset: clientset,
+	}, nil
+}
+
+func (r *podReader) Logs(ctx context.Context, namespace, pod, container string, previous bool, limitBytes int64) ([]byte, error) {
+	opts := &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+	}
+	if limitBytes > 0 {
+		opts.LimitBytes = &limitBytes
+	}
+	stream, err := r.clientset.CoreV1().Pods(namespace).GetLogs(pod, opts).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	return io.ReadAll(stream)
+}
+
+func (r *podReader) Exec(ctx context.Context, namespace, pod, container string, command []string) ([]byte, error) {
+	req := r.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(r.config, "POST", req.URL())
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
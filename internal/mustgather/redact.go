@@ -0,0 +1,69 @@
+package mustgather
+
+import (
+	"regexp"
+)
+
+// Redacted replaces sensitive values in the bundle.
+const Redacted = "REDACTED"
+
+var (
+	// sensitiveEnvName matches the names of env vars whose values are not collected
+	sensitiveEnvName = regexp.MustCompile(`(?i)(password|passwd|token|secret|credential|api_?key|private_?key)`)
+	// urlCredentials matches the user info of URLs, e.g. of proxies
+	urlCredentials = regexp.MustCompile(`(://)[^/@\s"']+@`)
+)
+
+// redactObject redacts the content of an object in place: the data of Secrets and the values
+// of sensitive env vars anywhere in the object, e.g. in pod templates.
+func redactObject(obj map[string]interface{}) {
+	if obj["kind"] == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			data, ok := obj[field].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for key := range data {
+				data[key] = Redacted
+			}
+		}
+		// the last applied configuration repeats the data
+		if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+			if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+				delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+			}
+		}
+	}
+	redactEnv(obj)
+}
+
+func redactEnv(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if env, ok := child.([]interface{}); ok && key == "env" {
+				for _, item := range env {
+					envVar, ok := item.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					name, _ := envVar["name"].(string)
+					if _, hasValue := envVar["value"]; hasValue && sensitiveEnvName.MatchString(name) {
+						envVar["value"] = Redacted
+					}
+				}
+				continue
+			}
+			redactEnv(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactEnv(child)
+		}
+	}
+}
+
+// redactText removes the credentials of URLs from collected text, e.g. logs.
+func redactText(data []byte) []byte {
+	return urlCredentials.ReplaceAll(data, []byte("${1}"+Redacted+"@"))
+}
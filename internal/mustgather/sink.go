@@ -0,0 +1,73 @@
+package mustgather
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Sink stores the files of a bundle.
+type Sink interface {
+	WriteFile(path string, data []byte) error
+	Close() error
+}
+
+type tarSink struct {
+	gz  *gzip.Writer
+	tar *tar.Writer
+	now time.Time
+}
+
+// NewTarSink writes the bundle to w as a gzipped tarball rooted at must-gather/.
+func NewTarSink(w io.Writer) Sink {
+	gz := gzip.NewWriter(w)
+	return &tarSink{
+		gz:  gz,
+		tar: tar.NewWriter(gz),
+		now: time.Now(),
+	}
+}
+
+func (s *tarSink) WriteFile(path string, data []byte) error {
+	if err := s.tar.WriteHeader(&tar.Header{
+		Name:    filepath.ToSlash(filepath.Join("must-gather", path)),
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: s.now,
+	}); err != nil {
+		return err
+	}
+	_, err := s.tar.Write(data)
+	return err
+}
+
+func (s *tarSink) Close() error {
+	if err := s.tar.Close(); err != nil {
+		return err
+	}
+	return s.gz.Close()
+}
+
+type dirSink struct {
+	dir string
+}
+
+// NewDirSink writes the bundle into dir, e.g. the /must-gather volume of oc adm must-gather.
+func NewDirSink(dir string) Sink {
+	return &dirSink{dir: dir}
+}
+
+func (s *dirSink) WriteFile(path string, data []byte) error {
+	path = filepath.Join(s.dir, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (s *dirSink) Close() error {
+	return nil
+}
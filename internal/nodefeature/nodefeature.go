@@ -0,0 +1,148 @@
+// Package nodefeature reads the RBLN PCI devices of the nodes from the NodeFeature objects
+// published by node-feature-discovery, and derives the product labels of the nodes from them.
+// Unlike the feature.node.kubernetes.io labels, NodeFeature objects list every PCI function
+// with its device ID, so the cards can be counted per product.
+package nodefeature
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+)
+
+// GroupVersionKind of the NodeFeature objects of node-feature-discovery.
+var GroupVersionKind = schema.GroupVersionKind{Group: "nfd.k8s-sigs.io", Version: "v1alpha1", Kind: "NodeFeature"}
+
+// nodeNameLabelKey names the node a NodeFeature object describes.
+const nodeNameLabelKey = "nfd.node.kubernetes.io/node-name"
+
+// Device is an RBLN PCI function of a known product.
+type Device struct {
+	Product  string
+	Physical bool
+}
+
+// Devices returns the RBLN devices per node name. Nodes whose PCI devices are not published
+// are left out, and false is returned if the NodeFeature API is not served.
+func Devices(ctx context.Context, c client.Reader) (map[string][]Device, bool, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(GroupVersionKind.GroupVersion().WithKind(GroupVersionKind.Kind + "List"))
+	if err := c.List(ctx, list); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to list NodeFeatures: %w", err)
+	}
+
+	devices := map[string][]Device{}
+	for _, item := range list.Items {
+		elements, found, err := unstructured.NestedSlice(item.Object, "spec", "features", "instances", "pci.device", "elements")
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid NodeFeature %s: %w", item.GetName(), err)
+		}
+		// NodeFeatures of other sources do not describe the PCI devices
+		if !found {
+			continue
+		}
+		nodeName := item.GetLabels()[nodeNameLabelKey]
+		if nodeName == "" {
+			nodeName = item.GetName()
+		}
+		nodeDevices := devices[nodeName]
+		if nodeDevices == nil {
+			nodeDevices = []Device{}
+		}
+		for _, element := range elements {
+			fields, ok := element.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			vendor, _, _ := unstructured.NestedString(fields, "attributes", "vendor")
+			deviceID, _, _ := unstructured.NestedString(fields, "attributes", "device")
+			if vendor != consts.RBLNVendorCode {
+				continue
+			}
+			if device, ok := deviceOf(deviceID); ok {
+				nodeDevices = append(nodeDevices, device)
+			}
+		}
+		devices[nodeName] = nodeDevices
+	}
+	return devices, true, nil
+}
+
+func deviceOf(deviceID string) (Device, bool) {
+	product := consts.ProductOf(deviceID)
+	if product == "" {
+		return Device{}, false
+	}
+	return Device{Product: product, Physical: consts.DeviceMapping[product][0] == deviceID}, true
+}
+
+// Labels returns the product labels of a node with the devices. Physical functions are
+// counted as cards; virtual functions are only counted on nodes without physical functions,
+// e.g. virtual machines the functions are passed through to.
+func Labels(devices []Device) map[string]string {
+	physical := slices.ContainsFunc(devices, func(d Device) bool { return d.Physical })
+	counts := map[string]int{}
+	for _, device := range devices {
+		if device.Physical == physical {
+			counts[device.Product]++
+		}
+	}
+	if len(counts) == 0 {
+		return map[string]string{}
+	}
+
+	labels := map[string]string{consts.RBLNFunctionLabelKey: consts.RBLNFunctionVirtual}
+	if physical {
+		labels[consts.RBLNFunctionLabelKey] = consts.RBLNFunctionPhysical
+	}
+	labels[consts.RBLNProductLabelKey] = consts.RBLNProductMixed
+	for product, count := range counts {
+		if len(counts) == 1 {
+			labels[consts.RBLNProductLabelKey] = product
+		}
+		labels[consts.RBLNCountLabelKeyPrefix+product] = strconv.Itoa(count)
+	}
+	return labels
+}
+
+// UpdateLabels sets the product labels of a node to desired and removes the stale ones.
+// It returns true if the labels changed.
+func UpdateLabels(labels, desired map[string]string) bool {
+	modified := false
+	for key := range labels {
+		if _, ok := desired[key]; !ok && isProductLabel(key) {
+			delete(labels, key)
+			modified = true
+		}
+	}
+	for key, value := range desired {
+		if labels[key] != value {
+			labels[key] = value
+			modified = true
+		}
+	}
+	return modified
+}
+
+// RemoveLabels drops the product labels of a node whose RBLN devices are gone.
+func RemoveLabels(labels map[string]string) {
+	UpdateLabels(labels, nil)
+}
+
+func isProductLabel(key string) bool {
+	return key == consts.RBLNProductLabelKey ||
+		key == consts.RBLNFunctionLabelKey ||
+		strings.HasPrefix(key, consts.RBLNCountLabelKeyPrefix)
+}
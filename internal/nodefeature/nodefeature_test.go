@@ -0,0 +1,133 @@
+package nodefeature_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/nodefeature"
+)
+
+func TestNodeFeature(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Node Feature Suite")
+}
+
+func nodeFeature(name, nodeName string, devices ...[2]string) *unstructured.Unstructured {
+	elements := make([]interface{}, 0, len(devices))
+	for _, device := range devices {
+		elements = append(elements, map[string]interface{}{
+			"attributes": map[string]interface{}{"class": "1200", "vendor": device[0], "device": device[1]},
+		})
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"features": map[string]interface{}{
+				"instances": map[string]interface{}{
+					"pci.device": map[string]interface{}{"elements": elements},
+				},
+			},
+		},
+	}}
+	obj.SetGroupVersionKind(nodefeature.GroupVersionKind)
+	obj.SetName(name)
+	obj.SetNamespace("node-feature-discovery")
+	obj.SetLabels(map[string]string{"nfd.node.kubernetes.io/node-name": nodeName})
+	return obj
+}
+
+// noMatchReader fails like a client of a cluster without node-feature-discovery.
+type noMatchReader struct {
+	client.Reader
+}
+
+func (noMatchReader) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return &meta.NoKindMatchError{GroupKind: nodefeature.GroupVersionKind.GroupKind()}
+}
+
+var _ = Describe("NodeFeature", func() {
+	Describe("Devices", func() {
+		It("should return the known RBLN devices per node", func() {
+			c := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(
+				nodeFeature("nfd-worker-a", "node-1", [2]string{"1eff", "1250"}, [2]string{"1eff", "1251"}, [2]string{"10de", "1250"}, [2]string{"1eff", "ffff"}),
+				nodeFeature("nfd-worker-b", "node-2", [2]string{"8086", "1572"}),
+			).Build()
+
+			devices, discovered, err := nodefeature.Devices(context.Background(), c)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(discovered).To(BeTrue())
+			Expect(devices).To(Equal(map[string][]nodefeature.Device{
+				"node-1": {{Product: "RBLN-CA25", Physical: true}, {Product: "RBLN-CA25"}},
+				"node-2": {},
+			}))
+		})
+
+		It("should report that the NodeFeature API is not served", func() {
+			devices, discovered, err := nodefeature.Devices(context.Background(), noMatchReader{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(discovered).To(BeFalse())
+			Expect(devices).To(BeEmpty())
+		})
+	})
+
+	Describe("Labels", func() {
+		It("should count the physical cards per product", func() {
+			Expect(nodefeature.Labels([]nodefeature.Device{
+				{Product: "RBLN-CA25", Physical: true},
+				{Product: "RBLN-CA25", Physical: true},
+				{Product: "RBLN-CA25"},
+			})).To(Equal(map[string]string{
+				"rebellions.ai/npu.product":         "RBLN-CA25",
+				"rebellions.ai/npu.count.RBLN-CA25": "2",
+				"rebellions.ai/npu.function":        "physical",
+			}))
+		})
+
+		It("should count the virtual functions of a node without physical functions", func() {
+			Expect(nodefeature.Labels([]nodefeature.Device{
+				{Product: "RBLN-CA22"},
+				{Product: "RBLN-CA25"},
+			})).To(Equal(map[string]string{
+				"rebellions.ai/npu.product":         "mixed",
+				"rebellions.ai/npu.count.RBLN-CA22": "1",
+				"rebellions.ai/npu.count.RBLN-CA25": "1",
+				"rebellions.ai/npu.function":        "virtual",
+			}))
+		})
+
+		It("should return no labels without devices", func() {
+			Expect(nodefeature.Labels(nil)).To(BeEmpty())
+		})
+	})
+
+	Describe("UpdateLabels", func() {
+		It("should replace the product labels and keep the others", func() {
+			labels := map[string]string{
+				"rebellions.ai/npu.present":         "true",
+				"rebellions.ai/npu.product":         "RBLN-CA22",
+				"rebellions.ai/npu.count.RBLN-CA22": "4",
+			}
+			desired := map[string]string{
+				"rebellions.ai/npu.product":         "RBLN-CA25",
+				"rebellions.ai/npu.count.RBLN-CA25": "4",
+			}
+			Expect(nodefeature.UpdateLabels(labels, desired)).To(BeTrue())
+			Expect(nodefeature.UpdateLabels(labels, desired)).To(BeFalse())
+			Expect(labels).To(Equal(map[string]string{
+				"rebellions.ai/npu.present":         "true",
+				"rebellions.ai/npu.product":         "RBLN-CA25",
+				"rebellions.ai/npu.count.RBLN-CA25": "4",
+			}))
+
+			nodefeature.RemoveLabels(labels)
+			Expect(labels).To(Equal(map[string]string{"rebellions.ai/npu.present": "true"}))
+		})
+	})
+})
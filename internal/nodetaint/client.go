@@ -0,0 +1,21 @@
+package nodetaint
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+// NewClient returns a client adding Toleration to the workloads written through it if enabled.
+func NewClient(c client.Client, enabled bool) client.Client {
+	if !enabled {
+		return c
+	}
+	return k8sutil.NewPodTemplateClient(c, func(_ context.Context, template *corev1.PodTemplateSpec) error {
+		InjectPodSpec(&template.Spec)
+		return nil
+	})
+}
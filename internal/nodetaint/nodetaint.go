@@ -0,0 +1,73 @@
+// Package nodetaint dedicates the nodes with RBLN devices to NPU workloads. Nodes are tainted
+// rebellions.ai/npu=present:NoSchedule, and the operands written through Client tolerate the taint.
+package nodetaint
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+)
+
+// Taint is the taint of the nodes with RBLN devices.
+var Taint = corev1.Taint{
+	Key:    consts.RBLNNPUTaintKey,
+	Value:  consts.RBLNNPUTaintValue,
+	Effect: corev1.TaintEffectNoSchedule,
+}
+
+// OwnedAnnotationKey marks the nodes the operator tainted, so only its own taint is ever removed.
+const OwnedAnnotationKey = "rebellions.ai/npu.taint-owned"
+
+// Toleration tolerates Taint.
+var Toleration = corev1.Toleration{
+	Key:      consts.RBLNNPUTaintKey,
+	Operator: corev1.TolerationOpEqual,
+	Value:    consts.RBLNNPUTaintValue,
+	Effect:   corev1.TaintEffectNoSchedule,
+}
+
+// SetTaint adds Taint to the node and records the ownership of the taint in OwnedAnnotationKey.
+// A Taint added by someone else is left to them. It returns false if the node was already tainted.
+func SetTaint(node *corev1.Node) bool {
+	if slices.ContainsFunc(node.Spec.Taints, isTaint) {
+		return false
+	}
+	taint := Taint
+	now := metav1.Now()
+	taint.TimeAdded = &now
+	node.Spec.Taints = append(node.Spec.Taints, taint)
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[OwnedAnnotationKey] = "true"
+	return true
+}
+
+// RemoveTaint drops Taint from the node if the operator added it. Taints of the same key with
+// another value or effect are kept. It returns false if the node was not changed.
+func RemoveTaint(node *corev1.Node) bool {
+	if _, owned := node.Annotations[OwnedAnnotationKey]; !owned {
+		return false
+	}
+	delete(node.Annotations, OwnedAnnotationKey)
+	node.Spec.Taints = slices.DeleteFunc(node.Spec.Taints, isTaint)
+	return true
+}
+
+func isTaint(taint corev1.Taint) bool {
+	return taint.Key == Taint.Key && taint.Value == Taint.Value && taint.Effect == Taint.Effect
+}
+
+// InjectPodSpec adds Toleration to the pod spec unless it already tolerates Taint.
+func InjectPodSpec(spec *corev1.PodSpec) {
+	for i := range spec.Tolerations {
+		if spec.Tolerations[i].ToleratesTaint(&Taint) {
+			return
+		}
+	}
+	// copy so the tolerations shared with the component spec are never mutated
+	spec.Tolerations = append(append([]corev1.Toleration{}, spec.Tolerations...), Toleration)
+}
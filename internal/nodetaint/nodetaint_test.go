@@ -0,0 +1,138 @@
+package nodetaint_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/nodetaint"
+)
+
+func TestNodeTaint(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Node Taint Suite")
+}
+
+var _ = Describe("NodeTaint", func() {
+	Describe("SetTaint", func() {
+		It("should taint the node once and remove the taint", func() {
+			node := &corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+				{Key: "dedicated", Value: "ml", Effect: corev1.TaintEffectNoSchedule},
+			}}}
+			Expect(nodetaint.SetTaint(node)).To(BeTrue())
+			Expect(nodetaint.SetTaint(node)).To(BeFalse())
+			Expect(node.Spec.Taints).To(HaveLen(2))
+			Expect(node.Spec.Taints[1].MatchTaint(&nodetaint.Taint)).To(BeTrue())
+			Expect(node.Annotations).To(HaveKey(nodetaint.OwnedAnnotationKey))
+
+			Expect(nodetaint.RemoveTaint(node)).To(BeTrue())
+			Expect(nodetaint.RemoveTaint(node)).To(BeFalse())
+			Expect(node.Spec.Taints).To(Equal([]corev1.Taint{
+				{Key: "dedicated", Value: "ml", Effect: corev1.TaintEffectNoSchedule},
+			}))
+			Expect(node.Annotations).NotTo(HaveKey(nodetaint.OwnedAnnotationKey))
+		})
+
+		It("should keep the taints of the key it did not add", func() {
+			taints := []corev1.Taint{
+				{Key: nodetaint.Taint.Key, Value: nodetaint.Taint.Value, Effect: corev1.TaintEffectNoSchedule},
+				{Key: nodetaint.Taint.Key, Value: "reserved", Effect: corev1.TaintEffectNoExecute},
+			}
+			node := &corev1.Node{Spec: corev1.NodeSpec{Taints: append([]corev1.Taint{}, taints...)}}
+
+			// never enabled, so nothing is removed
+			Expect(nodetaint.RemoveTaint(node)).To(BeFalse())
+			// an existing taint is not claimed
+			Expect(nodetaint.SetTaint(node)).To(BeFalse())
+			Expect(node.Annotations).NotTo(HaveKey(nodetaint.OwnedAnnotationKey))
+			Expect(nodetaint.RemoveTaint(node)).To(BeFalse())
+			Expect(node.Spec.Taints).To(Equal(taints))
+		})
+
+		It("should only remove its own taint", func() {
+			node := &corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+				{Key: nodetaint.Taint.Key, Value: "reserved", Effect: corev1.TaintEffectNoExecute},
+			}}}
+			Expect(nodetaint.SetTaint(node)).To(BeTrue())
+			Expect(node.Spec.Taints).To(HaveLen(2))
+
+			Expect(nodetaint.RemoveTaint(node)).To(BeTrue())
+			Expect(node.Spec.Taints).To(Equal([]corev1.Taint{
+				{Key: nodetaint.Taint.Key, Value: "reserved", Effect: corev1.TaintEffectNoExecute},
+			}))
+		})
+	})
+
+	Describe("InjectPodSpec", func() {
+		It("should add the toleration without mutating the original tolerations", func() {
+			tolerations := make([]corev1.Toleration, 1, 2)
+			tolerations[0] = corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists}
+			spec := &corev1.PodSpec{Tolerations: tolerations}
+
+			nodetaint.InjectPodSpec(spec)
+			nodetaint.InjectPodSpec(spec)
+			Expect(spec.Tolerations).To(Equal([]corev1.Toleration{tolerations[0], nodetaint.Toleration}))
+			Expect(tolerations[:2][1]).To(Equal(corev1.Toleration{}))
+		})
+
+		It("should keep a toleration that already tolerates the taint", func() {
+			spec := &corev1.PodSpec{Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}}}
+			nodetaint.InjectPodSpec(spec)
+			Expect(spec.Tolerations).To(HaveLen(1))
+		})
+	})
+
+	Describe("Client", func() {
+		var scheme *runtime.Scheme
+
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		})
+
+		daemonSet := func() *appsv1.DaemonSet {
+			return &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "rbln-device-plugin", Namespace: "rbln-system"}}
+		}
+
+		It("should make the written workloads tolerate the taint", func() {
+			c := nodetaint.NewClient(fake.NewClientBuilder().WithScheme(scheme).Build(), true)
+			ds := daemonSet()
+			Expect(c.Create(context.Background(), ds)).To(Succeed())
+
+			created := &appsv1.DaemonSet{}
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(ds), created)).To(Succeed())
+			Expect(created.Spec.Template.Spec.Tolerations).To(ConsistOf(nodetaint.Toleration))
+
+			created.Spec.Template.Spec.Tolerations = nil
+			Expect(c.Update(context.Background(), created)).To(Succeed())
+			Expect(created.Spec.Template.Spec.Tolerations).To(ConsistOf(nodetaint.Toleration))
+		})
+
+		It("should leave the workloads unchanged when disabled", func() {
+			c := nodetaint.NewClient(fake.NewClientBuilder().WithScheme(scheme).Build(), false)
+			ds := daemonSet()
+			Expect(c.Create(context.Background(), ds)).To(Succeed())
+			Expect(ds.Spec.Template.Spec.Tolerations).To(BeEmpty())
+		})
+
+		It("should only change the pod template of Jobs on create", func() {
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "rbln-validator", Namespace: "rbln-system"}}
+			c := nodetaint.NewClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(job).Build(), true)
+
+			existing := &batchv1.Job{}
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(job), existing)).To(Succeed())
+			Expect(c.Update(context.Background(), existing)).To(Succeed())
+			Expect(existing.Spec.Template.Spec.Tolerations).To(BeEmpty())
+		})
+	})
+})
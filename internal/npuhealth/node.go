@@ -0,0 +1,120 @@
+package npuhealth
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+)
+
+const (
+	reasonInvalidReport = "InvalidHealthReport"
+	reasonStaleReport   = "HealthReportStale"
+)
+
+// Condition returns the RBLNNPUHealthy node condition describing a report.
+func Condition(report Report) corev1.NodeCondition {
+	cond := corev1.NodeCondition{
+		Type:              consts.RBLNNodeConditionTypeNPUHealthy,
+		Status:            corev1.ConditionTrue,
+		Reason:            consts.RBLNNPUHealthyReason,
+		Message:           report.Summary(),
+		LastHeartbeatTime: metav1.NewTime(report.Timestamp),
+	}
+	if !report.Healthy {
+		cond.Status = corev1.ConditionFalse
+		cond.Reason = consts.RBLNNPUUnhealthyReason
+	}
+	return cond
+}
+
+// UnknownCondition returns the RBLNNPUHealthy node condition used when the report cannot be read.
+func UnknownCondition(message string) corev1.NodeCondition {
+	return corev1.NodeCondition{
+		Type:              consts.RBLNNodeConditionTypeNPUHealthy,
+		Status:            corev1.ConditionUnknown,
+		Reason:            reasonInvalidReport,
+		Message:           message,
+		LastHeartbeatTime: metav1.Now(),
+	}
+}
+
+// StaleCondition returns the RBLNNPUHealthy node condition used when the watcher stopped
+// publishing reports.
+func StaleCondition(report Report) corev1.NodeCondition {
+	return corev1.NodeCondition{
+		Type:              consts.RBLNNodeConditionTypeNPUHealthy,
+		Status:            corev1.ConditionUnknown,
+		Reason:            reasonStaleReport,
+		Message:           fmt.Sprintf("NPU health was last reported at %s", report.Timestamp.Format(time.RFC3339)),
+		LastHeartbeatTime: metav1.NewTime(report.Timestamp),
+	}
+}
+
+// SetCondition adds or updates the RBLNNPUHealthy condition of a node.
+// The transition time only moves when the status changes. It returns false if nothing changed.
+func SetCondition(node *corev1.Node, cond corev1.NodeCondition) bool {
+	for i := range node.Status.Conditions {
+		existing := &node.Status.Conditions[i]
+		if existing.Type != cond.Type {
+			continue
+		}
+		if existing.Status == cond.Status && existing.Reason == cond.Reason && existing.Message == cond.Message {
+			return false
+		}
+		if existing.Status != cond.Status {
+			existing.LastTransitionTime = metav1.Now()
+		}
+		existing.Status = cond.Status
+		existing.Reason = cond.Reason
+		existing.Message = cond.Message
+		existing.LastHeartbeatTime = cond.LastHeartbeatTime
+		return true
+	}
+
+	cond.LastTransitionTime = metav1.Now()
+	node.Status.Conditions = append(node.Status.Conditions, cond)
+	return true
+}
+
+// RemoveCondition drops the RBLNNPUHealthy condition from a node. It returns false if it was absent.
+func RemoveCondition(node *corev1.Node) bool {
+	before := len(node.Status.Conditions)
+	node.Status.Conditions = slices.DeleteFunc(node.Status.Conditions, func(c corev1.NodeCondition) bool {
+		return c.Type == consts.RBLNNodeConditionTypeNPUHealthy
+	})
+	return len(node.Status.Conditions) != before
+}
+
+// SetTaint adds the NoSchedule taint for unhealthy NPUs. It returns false if the node is already tainted.
+func SetTaint(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == consts.RBLNNPUUnhealthyTaintKey && taint.Effect == corev1.TaintEffectNoSchedule {
+			return false
+		}
+	}
+	node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{
+		Key:       consts.RBLNNPUUnhealthyTaintKey,
+		Effect:    corev1.TaintEffectNoSchedule,
+		TimeAdded: ptrNow(),
+	})
+	return true
+}
+
+// RemoveTaint drops the taint for unhealthy NPUs. It returns false if the node was not tainted.
+func RemoveTaint(node *corev1.Node) bool {
+	before := len(node.Spec.Taints)
+	node.Spec.Taints = slices.DeleteFunc(node.Spec.Taints, func(t corev1.Taint) bool {
+		return t.Key == consts.RBLNNPUUnhealthyTaintKey
+	})
+	return len(node.Spec.Taints) != before
+}
+
+func ptrNow() *metav1.Time {
+	now := metav1.Now()
+	return &now
+}
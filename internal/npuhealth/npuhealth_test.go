@@ -0,0 +1,152 @@
+package npuhealth_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/npuhealth"
+)
+
+func TestNPUHealth(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "NPU Health Suite")
+}
+
+var _ = Describe("NPUHealth", func() {
+	Describe("ScanPCIDevices", func() {
+		var sysfs string
+
+		addDevice := func(address, vendor, driver, aerFatal string) {
+			devicePath := filepath.Join(sysfs, "devices", address)
+			Expect(os.MkdirAll(devicePath, 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(devicePath, "vendor"), []byte(vendor+"\n"), 0o644)).To(Succeed())
+			if driver != "" {
+				driverPath := filepath.Join(sysfs, "drivers", driver)
+				Expect(os.MkdirAll(driverPath, 0o755)).To(Succeed())
+				Expect(os.Symlink(driverPath, filepath.Join(devicePath, "driver"))).To(Succeed())
+			}
+			if aerFatal != "" {
+				Expect(os.WriteFile(filepath.Join(devicePath, "aer_dev_fatal"), []byte(aerFatal), 0o644)).To(Succeed())
+			}
+		}
+
+		BeforeEach(func() {
+			sysfs = GinkgoT().TempDir()
+		})
+
+		It("should list RBLN devices with their driver and fatal AER errors", func() {
+			addDevice("0000:01:00.0", "0x1eff", "rebellions", "Undefined 0\nTOTAL_ERR_FATAL 0\n")
+			addDevice("0000:02:00.0", "0x1eff", "vfio-pci", "")
+			addDevice("0000:03:00.0", "0x1eff", "rebellions", "DLP 1\nTOTAL_ERR_FATAL 2\n")
+			addDevice("0000:04:00.0", "0x10de", "nvidia", "")
+
+			devices, err := npuhealth.ScanPCIDevices(filepath.Join(sysfs, "devices"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(devices).To(ConsistOf(
+				npuhealth.PCIDevice{Address: "0000:01:00.0", Driver: "rebellions"},
+				npuhealth.PCIDevice{Address: "0000:02:00.0", Driver: "vfio-pci"},
+				npuhealth.PCIDevice{Address: "0000:03:00.0", Driver: "rebellions", FatalErrors: 2},
+			))
+		})
+	})
+
+	Describe("Evaluate", func() {
+		It("should report unbound, failing and lost devices", func() {
+			devices := []npuhealth.PCIDevice{
+				{Address: "0000:03:00.0", Driver: "rebellions", FatalErrors: 1},
+				{Address: "0000:01:00.0", Driver: "rebellions"},
+				{Address: "0000:02:00.0"},
+			}
+			previous := npuhealth.Report{ExpectedDevices: []string{"0000:01:00.0", "0000:02:00.0", "0000:03:00.0", "0000:04:00.0"}}
+
+			report := npuhealth.Evaluate(previous, devices, consts.RBLNDriverName, time.Now())
+			Expect(report.Healthy).To(BeFalse())
+			Expect(report.Devices).To(Equal(3))
+			Expect(report.UnhealthyDevices).To(HaveExactElements(
+				HaveField("Reason", npuhealth.ReasonDriverNotBound),
+				HaveField("Reason", npuhealth.ReasonPCIeFatalError),
+				And(HaveField("Card", "0000:04:00.0"), HaveField("Reason", npuhealth.ReasonDeviceLost)),
+			))
+			Expect(report.ExpectedDevices).To(Equal(previous.ExpectedDevices))
+		})
+
+		It("should only report fatal errors above the baseline", func() {
+			devices := []npuhealth.PCIDevice{{Address: "0000:01:00.0", Driver: "rebellions", FatalErrors: 2}}
+			report := npuhealth.Evaluate(npuhealth.Report{}, devices, consts.RBLNDriverName, time.Now())
+			Expect(report.Healthy).To(BeFalse())
+			Expect(report.ExpectedDevices).To(ConsistOf("0000:01:00.0"))
+
+			// the counter keeps the errors after the device was reset
+			report.AcceptFatalErrors(devices)
+			report = npuhealth.Evaluate(report, devices, consts.RBLNDriverName, time.Now())
+			Expect(report.Healthy).To(BeTrue())
+			Expect(report.FatalErrorBaseline).To(HaveKeyWithValue("0000:01:00.0", 2))
+
+			devices[0].FatalErrors = 3
+			report = npuhealth.Evaluate(report, devices, consts.RBLNDriverName, time.Now())
+			Expect(report.UnhealthyDevices).To(ConsistOf(And(
+				HaveField("Reason", npuhealth.ReasonPCIeFatalError),
+				HaveField("Message", "1 new fatal AER errors"),
+			)))
+
+			// a reboot restarts the counter
+			devices[0].FatalErrors = 0
+			report = npuhealth.Evaluate(report, devices, consts.RBLNDriverName, time.Now())
+			Expect(report.Healthy).To(BeTrue())
+			Expect(report.FatalErrorBaseline).To(BeEmpty())
+		})
+
+		It("should round-trip a healthy report", func() {
+			report := npuhealth.Evaluate(npuhealth.Report{}, []npuhealth.PCIDevice{{Address: "0000:01:00.0", Driver: "rebellions"}}, consts.RBLNDriverName, time.Now())
+			Expect(report.Healthy).To(BeTrue())
+
+			value, err := report.Encode()
+			Expect(err).NotTo(HaveOccurred())
+			decoded, err := npuhealth.Decode(value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded.Equal(report)).To(BeTrue())
+		})
+	})
+
+	Describe("Node", func() {
+		It("should set and clear the condition and taint", func() {
+			node := &corev1.Node{}
+			unhealthy := npuhealth.Report{UnhealthyDevices: []npuhealth.DeviceStatus{{Card: "0000:01:00.0", Reason: npuhealth.ReasonDeviceLost}}}
+
+			Expect(npuhealth.SetCondition(node, npuhealth.Condition(unhealthy))).To(BeTrue())
+			Expect(npuhealth.SetCondition(node, npuhealth.Condition(unhealthy))).To(BeFalse())
+			Expect(node.Status.Conditions).To(ConsistOf(And(
+				HaveField("Type", corev1.NodeConditionType(consts.RBLNNodeConditionTypeNPUHealthy)),
+				HaveField("Status", corev1.ConditionFalse),
+				HaveField("Message", "0000:01:00.0: DeviceLost"),
+			)))
+
+			Expect(npuhealth.SetTaint(node)).To(BeTrue())
+			Expect(npuhealth.SetTaint(node)).To(BeFalse())
+			Expect(node.Spec.Taints).To(ConsistOf(And(
+				HaveField("Key", consts.RBLNNPUUnhealthyTaintKey),
+				HaveField("Effect", corev1.TaintEffectNoSchedule),
+			)))
+
+			Expect(npuhealth.SetCondition(node, npuhealth.Condition(npuhealth.Report{Healthy: true, Devices: 1}))).To(BeTrue())
+			Expect(node.Status.Conditions[0].Status).To(Equal(corev1.ConditionTrue))
+			Expect(npuhealth.RemoveTaint(node)).To(BeTrue())
+			Expect(node.Spec.Taints).To(BeEmpty())
+			Expect(npuhealth.RemoveCondition(node)).To(BeTrue())
+			Expect(npuhealth.RemoveCondition(node)).To(BeFalse())
+		})
+
+		It("should report a stale report as unknown", func() {
+			cond := npuhealth.StaleCondition(npuhealth.Report{Healthy: true, Timestamp: time.Now().Add(-time.Hour)})
+			Expect(cond.Status).To(Equal(corev1.ConditionUnknown))
+			Expect(npuhealth.StaleAfter(30 * time.Second)).To(Equal(3 * npuhealth.HeartbeatInterval))
+		})
+	})
+})
@@ -0,0 +1,188 @@
+package npuhealth
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+)
+
+// Node annotations used by remediation
+const (
+	// ResetRequestAnnotationKey asks the health watcher to reset the unhealthy devices of the node.
+	// Only the operator may set it, and the watcher only resets a cordoned and drained node.
+	ResetRequestAnnotationKey = "rebellions.ai/npu.reset-request"
+	// CordonAnnotationKey marks a node cordoned by remediation, so that recovery does not
+	// uncordon a node an admin cordoned before
+	CordonAnnotationKey = "rebellions.ai/npu.remediation-cordoned"
+	// ResetCompletedAnnotationKey holds the last reset request handled by the health watcher
+	ResetCompletedAnnotationKey = "rebellions.ai/npu.reset-completed"
+	// RebootRequiredAnnotationKey marks a node that needs a reboot to recover
+	RebootRequiredAnnotationKey = "rebellions.ai/npu.reboot-required"
+)
+
+// ResetPending reports whether the node has a reset request the health watcher has not handled yet.
+func ResetPending(annotations map[string]string) bool {
+	request := annotations[ResetRequestAnnotationKey]
+	return request != "" && request != annotations[ResetCompletedAnnotationKey]
+}
+
+// RemediationEventRecovered is recorded in the history once a remediated node reports healthy again.
+const RemediationEventRecovered = "recovered"
+
+const (
+	defaultRemediationIntervalSeconds = 300
+	defaultRemediationMaxConcurrent   = 1
+	defaultRemediationHistoryLimit    = 10
+)
+
+var defaultRemediationActions = []rblnv1beta1.RemediationAction{
+	rblnv1beta1.RemediationRestartDevicePlugin,
+	rblnv1beta1.RemediationResetDevice,
+	rblnv1beta1.RemediationRestartDriver,
+	rblnv1beta1.RemediationCordon,
+}
+
+// Remediator plans the escalation of remediation actions from a RemediationSpec.
+type Remediator struct {
+	actions       []rblnv1beta1.RemediationAction
+	interval      time.Duration
+	maxConcurrent int
+	historyLimit  int
+}
+
+func NewRemediator(spec *rblnv1beta1.RemediationSpec) *Remediator {
+	r := &Remediator{
+		actions:       defaultRemediationActions,
+		interval:      defaultRemediationIntervalSeconds * time.Second,
+		maxConcurrent: defaultRemediationMaxConcurrent,
+		historyLimit:  defaultRemediationHistoryLimit,
+	}
+	if len(spec.Actions) > 0 {
+		r.actions = spec.Actions
+	}
+	if spec.IntervalSeconds > 0 {
+		r.interval = time.Duration(spec.IntervalSeconds) * time.Second
+	}
+	if spec.MaxConcurrent > 0 {
+		r.maxConcurrent = int(spec.MaxConcurrent)
+	}
+	if spec.HistoryLimit > 0 {
+		r.historyLimit = int(spec.HistoryLimit)
+	}
+	return r
+}
+
+func (r *Remediator) Interval() time.Duration {
+	return r.interval
+}
+
+// Next returns the action to run on an unhealthy node.
+// An empty action with a positive wait means the node must be checked again later;
+// an empty action without wait means every action was exhausted.
+func (r *Remediator) Next(statuses []rblnv1beta1.NodeRemediationStatus, nodeName string, now time.Time) (rblnv1beta1.RemediationAction, time.Duration) {
+	status := FindRemediation(statuses, nodeName)
+	if status != nil && status.Phase == rblnv1beta1.RemediationInProgress {
+		if status.LastActionTime != nil {
+			if wait := status.LastActionTime.Add(r.interval).Sub(now); wait > 0 {
+				return "", wait
+			}
+		}
+		if int(status.Step) >= len(r.actions) {
+			return "", 0
+		}
+		return r.actions[status.Step], 0
+	}
+	if status != nil && status.Phase == rblnv1beta1.RemediationExhausted {
+		return "", 0
+	}
+
+	// a new remediation must wait for a free slot
+	active := 0
+	for _, s := range statuses {
+		if s.NodeName != nodeName && s.Phase == rblnv1beta1.RemediationInProgress {
+			active++
+		}
+	}
+	if active >= r.maxConcurrent {
+		return "", r.interval
+	}
+	return r.actions[0], 0
+}
+
+// Record stores an action run on a node and returns the updated statuses.
+func (r *Remediator) Record(statuses []rblnv1beta1.NodeRemediationStatus, nodeName string, action rblnv1beta1.RemediationAction, message string, now time.Time) []rblnv1beta1.NodeRemediationStatus {
+	statuses, status := ensureRemediation(statuses, nodeName)
+	if status.Phase != rblnv1beta1.RemediationInProgress {
+		status.Phase = rblnv1beta1.RemediationInProgress
+		status.Step = 0
+	}
+	status.Step++
+	t := metav1.NewTime(now)
+	status.LastActionTime = &t
+	r.appendHistory(status, string(action), message, now)
+	return statuses
+}
+
+// Exhaust marks the remediation of a node as exhausted.
+func (r *Remediator) Exhaust(statuses []rblnv1beta1.NodeRemediationStatus, nodeName string, now time.Time) []rblnv1beta1.NodeRemediationStatus {
+	statuses, status := ensureRemediation(statuses, nodeName)
+	if status.Phase == rblnv1beta1.RemediationExhausted {
+		return statuses
+	}
+	status.Phase = rblnv1beta1.RemediationExhausted
+	r.appendHistory(status, string(rblnv1beta1.RemediationExhausted), "node is still unhealthy after all remediation actions", now)
+	return statuses
+}
+
+// Recover marks a remediated node healthy. It returns false if the node was not being remediated.
+func (r *Remediator) Recover(statuses []rblnv1beta1.NodeRemediationStatus, nodeName string, now time.Time) ([]rblnv1beta1.NodeRemediationStatus, bool) {
+	status := FindRemediation(statuses, nodeName)
+	if status == nil || status.Phase == rblnv1beta1.RemediationSucceeded {
+		return statuses, false
+	}
+	status.Phase = rblnv1beta1.RemediationSucceeded
+	status.Step = 0
+	r.appendHistory(status, RemediationEventRecovered, "", now)
+	return statuses, true
+}
+
+func (r *Remediator) appendHistory(status *rblnv1beta1.NodeRemediationStatus, action, message string, now time.Time) {
+	status.History = append(status.History, rblnv1beta1.RemediationEvent{
+		Action:  action,
+		Time:    metav1.NewTime(now),
+		Message: message,
+	})
+	if len(status.History) > r.historyLimit {
+		status.History = status.History[len(status.History)-r.historyLimit:]
+	}
+}
+
+// FindRemediation returns the remediation status of a node, or nil.
+func FindRemediation(statuses []rblnv1beta1.NodeRemediationStatus, nodeName string) *rblnv1beta1.NodeRemediationStatus {
+	for i := range statuses {
+		if statuses[i].NodeName == nodeName {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// RemoveRemediation drops the remediation status of a node, e.g. once the node is deleted.
+func RemoveRemediation(statuses []rblnv1beta1.NodeRemediationStatus, nodeName string) ([]rblnv1beta1.NodeRemediationStatus, bool) {
+	for i := range statuses {
+		if statuses[i].NodeName == nodeName {
+			return append(statuses[:i], statuses[i+1:]...), true
+		}
+	}
+	return statuses, false
+}
+
+func ensureRemediation(statuses []rblnv1beta1.NodeRemediationStatus, nodeName string) ([]rblnv1beta1.NodeRemediationStatus, *rblnv1beta1.NodeRemediationStatus) {
+	if status := FindRemediation(statuses, nodeName); status != nil {
+		return statuses, status
+	}
+	statuses = append(statuses, rblnv1beta1.NodeRemediationStatus{NodeName: nodeName})
+	return statuses, &statuses[len(statuses)-1]
+}
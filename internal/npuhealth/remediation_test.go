@@ -0,0 +1,120 @@
+package npuhealth_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/npuhealth"
+)
+
+var _ = Describe("Remediator", func() {
+	var (
+		remediator *npuhealth.Remediator
+		now        time.Time
+	)
+
+	BeforeEach(func() {
+		remediator = npuhealth.NewRemediator(&rblnv1beta1.RemediationSpec{
+			Enabled:         true,
+			Actions:         []rblnv1beta1.RemediationAction{rblnv1beta1.RemediationRestartDevicePlugin, rblnv1beta1.RemediationCordon},
+			IntervalSeconds: 60,
+			HistoryLimit:    3,
+		})
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	It("should escalate through the actions at the configured interval", func() {
+		var statuses []rblnv1beta1.NodeRemediationStatus
+
+		action, wait := remediator.Next(statuses, "node-a", now)
+		Expect(action).To(Equal(rblnv1beta1.RemediationRestartDevicePlugin))
+		Expect(wait).To(BeZero())
+		statuses = remediator.Record(statuses, "node-a", action, "deleted 1 pods", now)
+
+		action, wait = remediator.Next(statuses, "node-a", now.Add(30*time.Second))
+		Expect(action).To(BeEmpty())
+		Expect(wait).To(Equal(30 * time.Second))
+
+		action, _ = remediator.Next(statuses, "node-a", now.Add(time.Minute))
+		Expect(action).To(Equal(rblnv1beta1.RemediationCordon))
+		statuses = remediator.Record(statuses, "node-a", action, "", now.Add(time.Minute))
+
+		action, wait = remediator.Next(statuses, "node-a", now.Add(2*time.Minute))
+		Expect(action).To(BeEmpty())
+		Expect(wait).To(BeZero())
+		statuses = remediator.Exhaust(statuses, "node-a", now.Add(2*time.Minute))
+		Expect(statuses[0].Phase).To(Equal(rblnv1beta1.RemediationExhausted))
+		Expect(statuses[0].History).To(HaveLen(3))
+	})
+
+	It("should limit concurrent remediations", func() {
+		statuses := remediator.Record(nil, "node-a", rblnv1beta1.RemediationRestartDevicePlugin, "", now)
+
+		action, wait := remediator.Next(statuses, "node-b", now)
+		Expect(action).To(BeEmpty())
+		Expect(wait).To(Equal(time.Minute))
+
+		statuses, recovered := remediator.Recover(statuses, "node-a", now)
+		Expect(recovered).To(BeTrue())
+		action, _ = remediator.Next(statuses, "node-b", now)
+		Expect(action).To(Equal(rblnv1beta1.RemediationRestartDevicePlugin))
+	})
+
+	It("should keep a bounded history and restart after a recovery", func() {
+		statuses := remediator.Record(nil, "node-a", rblnv1beta1.RemediationRestartDevicePlugin, "", now)
+		statuses, _ = remediator.Recover(statuses, "node-a", now)
+		statuses = remediator.Record(statuses, "node-a", rblnv1beta1.RemediationRestartDevicePlugin, "", now)
+		statuses = remediator.Record(statuses, "node-a", rblnv1beta1.RemediationCordon, "", now)
+
+		status := npuhealth.FindRemediation(statuses, "node-a")
+		Expect(status.Step).To(Equal(int32(2)))
+		Expect(status.History).To(HaveLen(3))
+		Expect(status.History[0].Action).To(Equal(npuhealth.RemediationEventRecovered))
+
+		statuses, removed := npuhealth.RemoveRemediation(statuses, "node-a")
+		Expect(removed).To(BeTrue())
+		Expect(statuses).To(BeEmpty())
+	})
+
+	It("should reset failing devices and rescan for lost ones", func() {
+		sys := GinkgoT().TempDir()
+		devicePath := filepath.Join(sys, "bus/pci/devices/0000:01:00.0")
+		Expect(os.MkdirAll(devicePath, 0o755)).To(Succeed())
+
+		report := npuhealth.Report{UnhealthyDevices: []npuhealth.DeviceStatus{
+			{Card: "0000:01:00.0", Reason: npuhealth.ReasonPCIeFatalError},
+			{Card: "0000:02:00.0", Reason: npuhealth.ReasonDeviceLost},
+		}}
+		Expect(npuhealth.ResetDevices(sys, report)).To(Succeed())
+		Expect(filepath.Join(devicePath, "reset")).To(BeAnExistingFile())
+		Expect(filepath.Join(sys, "bus/pci/rescan")).To(BeAnExistingFile())
+	})
+
+	It("should reset every RBLN device and rescan", func() {
+		sys := GinkgoT().TempDir()
+		rbln := filepath.Join(sys, "bus/pci/devices/0000:01:00.0")
+		other := filepath.Join(sys, "bus/pci/devices/0000:02:00.0")
+		Expect(os.MkdirAll(rbln, 0o755)).To(Succeed())
+		Expect(os.MkdirAll(other, 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(rbln, "vendor"), []byte("0x1eff\n"), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(other, "vendor"), []byte("0x8086\n"), 0o644)).To(Succeed())
+
+		Expect(npuhealth.ResetAllDevices(sys)).To(Succeed())
+		Expect(filepath.Join(rbln, "reset")).To(BeAnExistingFile())
+		Expect(filepath.Join(other, "reset")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(sys, "bus/pci/rescan")).To(BeAnExistingFile())
+	})
+
+	It("should report a reset request until the health watcher handled it", func() {
+		Expect(npuhealth.ResetPending(nil)).To(BeFalse())
+		annotations := map[string]string{npuhealth.ResetRequestAnnotationKey: "2026-10-18T00:00:00Z"}
+		Expect(npuhealth.ResetPending(annotations)).To(BeTrue())
+		annotations[npuhealth.ResetCompletedAnnotationKey] = "2026-10-18T00:00:00Z"
+		Expect(npuhealth.ResetPending(annotations)).To(BeFalse())
+	})
+})
@@ -0,0 +1,234 @@
+// Package npuhealth defines the NPU health report published by the health watcher on each node
+// and consumed by the operator to maintain the RBLNNPUHealthy node condition.
+package npuhealth
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+)
+
+// AnnotationKey is the node annotation holding the JSON encoded Report.
+const AnnotationKey = "rebellions.ai/npu.health"
+
+// HeartbeatInterval is how often the watcher republishes an unchanged report.
+const HeartbeatInterval = time.Minute
+
+// StaleAfter returns how old a report may get before the watcher that checks every interval is
+// considered gone.
+func StaleAfter(interval time.Duration) time.Duration {
+	return 3 * max(interval, HeartbeatInterval)
+}
+
+// Reasons a device is reported unhealthy
+const (
+	ReasonDeviceLost     = "DeviceLost"
+	ReasonDriverNotBound = "DriverNotBound"
+	ReasonPCIeFatalError = "PCIeFatalError"
+	ReasonSMIFailed      = "SMIFailed"
+)
+
+// DeviceStatus describes an unhealthy NPU.
+type DeviceStatus struct {
+	// Card is the PCI address of the device
+	Card    string `json:"card"`
+	Reason  string `json:"reason"`
+	Message string `json:"message,omitempty"`
+}
+
+// Report is the health of all NPUs on a node.
+type Report struct {
+	Healthy          bool           `json:"healthy"`
+	Devices          int            `json:"devices"`
+	UnhealthyDevices []DeviceStatus `json:"unhealthyDevices,omitempty"`
+	// ExpectedDevices lists every device seen on the node, so devices lost while the watcher
+	// restarts are still reported. Remove the annotation to forget a device taken out on purpose.
+	ExpectedDevices []string `json:"expectedDevices,omitempty"`
+	// FatalErrorBaseline is the TOTAL_ERR_FATAL count of each device that is already accounted for.
+	// The counter runs since boot and survives a reset, so only errors above it make a device unhealthy.
+	FatalErrorBaseline map[string]int `json:"fatalErrorBaseline,omitempty"`
+	Timestamp          time.Time      `json:"timestamp"`
+}
+
+// Equal reports whether two reports describe the same health state, ignoring the timestamp.
+func (r Report) Equal(other Report) bool {
+	return r.Healthy == other.Healthy &&
+		r.Devices == other.Devices &&
+		slices.Equal(r.UnhealthyDevices, other.UnhealthyDevices) &&
+		slices.Equal(r.ExpectedDevices, other.ExpectedDevices) &&
+		maps.Equal(r.FatalErrorBaseline, other.FatalErrorBaseline)
+}
+
+// AcceptFatalErrors moves the fatal error baseline of the devices to their current count,
+// e.g. once they were reset.
+func (r *Report) AcceptFatalErrors(devices []PCIDevice) {
+	for _, d := range devices {
+		r.setBaseline(d.Address, d.FatalErrors)
+	}
+}
+
+func (r *Report) setBaseline(address string, count int) {
+	if count == 0 {
+		delete(r.FatalErrorBaseline, address)
+		return
+	}
+	if r.FatalErrorBaseline == nil {
+		r.FatalErrorBaseline = map[string]int{}
+	}
+	r.FatalErrorBaseline[address] = count
+}
+
+// Summary returns a short human readable description of the unhealthy devices.
+func (r Report) Summary() string {
+	if r.Healthy {
+		return fmt.Sprintf("All %d NPUs are healthy", r.Devices)
+	}
+	parts := make([]string, 0, len(r.UnhealthyDevices))
+	for _, d := range r.UnhealthyDevices {
+		part := d.Card + ": " + d.Reason
+		if d.Message != "" {
+			part += " (" + d.Message + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (r Report) Encode() (string, error) {
+	out, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("encode npu health report: %w", err)
+	}
+	return string(out), nil
+}
+
+func Decode(value string) (Report, error) {
+	var r Report
+	if err := json.Unmarshal([]byte(value), &r); err != nil {
+		return Report{}, fmt.Errorf("decode npu health report: %w", err)
+	}
+	return r, nil
+}
+
+// PCIDevice is an RBLN device found in sysfs.
+type PCIDevice struct {
+	Address     string
+	Driver      string
+	FatalErrors int
+}
+
+// ScanPCIDevices lists RBLN devices under a sysfs PCI devices directory, e.g. /sys/bus/pci/devices.
+func ScanPCIDevices(pciDevicesPath string) ([]PCIDevice, error) {
+	entries, err := os.ReadDir(pciDevicesPath)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", pciDevicesPath, err)
+	}
+
+	var devices []PCIDevice
+	for _, entry := range entries {
+		devicePath := filepath.Join(pciDevicesPath, entry.Name())
+		vendor, err := readSysfsValue(filepath.Join(devicePath, "vendor"))
+		if err != nil || strings.TrimPrefix(vendor, "0x") != consts.RBLNVendorCode {
+			continue
+		}
+
+		device := PCIDevice{Address: entry.Name()}
+		if driverPath, err := filepath.EvalSymlinks(filepath.Join(devicePath, "driver")); err == nil {
+			device.Driver = filepath.Base(driverPath)
+		}
+		device.FatalErrors = readAERFatalErrors(filepath.Join(devicePath, "aer_dev_fatal"))
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// Evaluate builds a Report from the devices found on the node.
+// previous is the last report of the node: devices it expected that are missing from the scan are
+// reported lost, and only fatal errors above its baseline make a device unhealthy.
+func Evaluate(previous Report, devices []PCIDevice, driverName string, now time.Time) Report {
+	report := Report{
+		Devices:            len(devices),
+		ExpectedDevices:    slices.Clone(previous.ExpectedDevices),
+		FatalErrorBaseline: maps.Clone(previous.FatalErrorBaseline),
+		Timestamp:          now,
+	}
+
+	found := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		found[d.Address] = true
+		if !slices.Contains(report.ExpectedDevices, d.Address) {
+			report.ExpectedDevices = append(report.ExpectedDevices, d.Address)
+		}
+		baseline := report.FatalErrorBaseline[d.Address]
+		if d.FatalErrors < baseline {
+			// the counter restarted with the node
+			baseline = d.FatalErrors
+			report.setBaseline(d.Address, baseline)
+		}
+		switch {
+		case d.Driver != driverName:
+			report.UnhealthyDevices = append(report.UnhealthyDevices, DeviceStatus{
+				Card:    d.Address,
+				Reason:  ReasonDriverNotBound,
+				Message: fmt.Sprintf("bound driver %q", d.Driver),
+			})
+		case d.FatalErrors > baseline:
+			report.UnhealthyDevices = append(report.UnhealthyDevices, DeviceStatus{
+				Card:    d.Address,
+				Reason:  ReasonPCIeFatalError,
+				Message: fmt.Sprintf("%d new fatal AER errors", d.FatalErrors-baseline),
+			})
+		}
+	}
+	for _, address := range previous.ExpectedDevices {
+		if !found[address] {
+			report.UnhealthyDevices = append(report.UnhealthyDevices, DeviceStatus{
+				Card:   address,
+				Reason: ReasonDeviceLost,
+			})
+		}
+	}
+
+	slices.Sort(report.ExpectedDevices)
+	slices.SortFunc(report.UnhealthyDevices, func(a, b DeviceStatus) int {
+		return strings.Compare(a.Card, b.Card)
+	})
+	report.Healthy = len(report.UnhealthyDevices) == 0
+	return report
+}
+
+func readSysfsValue(path string) (string, error) {
+	// #nosec G304 -- path is built from the sysfs PCI devices directory.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readAERFatalErrors returns TOTAL_ERR_FATAL from an aer_dev_fatal file, or 0 when unavailable.
+func readAERFatalErrors(path string) int {
+	content, err := readSysfsValue(path)
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "TOTAL_ERR_FATAL" {
+			total, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return 0
+			}
+			return total
+		}
+	}
+	return 0
+}
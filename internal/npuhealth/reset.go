@@ -0,0 +1,51 @@
+package npuhealth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ResetDevices resets the PCI functions of unhealthy devices through sysfs and rescans the bus
+// so that lost devices can be enumerated again. sysPath is the sysfs root, e.g. /sys.
+func ResetDevices(sysPath string, report Report) error {
+	rescan := false
+	for _, d := range report.UnhealthyDevices {
+		switch d.Reason {
+		case ReasonDeviceLost:
+			rescan = true
+		case ReasonDriverNotBound, ReasonPCIeFatalError:
+			if err := writeSysfs(filepath.Join(sysPath, "bus/pci/devices", d.Card, "reset")); err != nil {
+				return fmt.Errorf("reset %s: %w", d.Card, err)
+			}
+		}
+	}
+	if rescan {
+		if err := writeSysfs(filepath.Join(sysPath, "bus/pci/rescan")); err != nil {
+			return fmt.Errorf("rescan pci bus: %w", err)
+		}
+	}
+	return nil
+}
+
+// ResetAllDevices resets every RBLN PCI function through sysfs and rescans the bus, e.g. to
+// load a newly flashed firmware. sysPath is the sysfs root, e.g. /sys.
+func ResetAllDevices(sysPath string) error {
+	devices, err := ScanPCIDevices(filepath.Join(sysPath, "bus/pci/devices"))
+	if err != nil {
+		return err
+	}
+	for _, d := range devices {
+		if err := writeSysfs(filepath.Join(sysPath, "bus/pci/devices", d.Address, "reset")); err != nil {
+			return fmt.Errorf("reset %s: %w", d.Address, err)
+		}
+	}
+	if err := writeSysfs(filepath.Join(sysPath, "bus/pci/rescan")); err != nil {
+		return fmt.Errorf("rescan pci bus: %w", err)
+	}
+	return nil
+}
+
+func writeSysfs(path string) error {
+	return os.WriteFile(path, []byte("1"), 0o200)
+}
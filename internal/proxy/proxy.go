@@ -0,0 +1,223 @@
+// Package proxy resolves the cluster-wide HTTP(S) proxy and injects it into operand containers,
+// so drivers and validators reach package repositories and registries behind a corporate proxy.
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+const (
+	HTTPProxyEnvName  = "HTTP_PROXY"
+	HTTPSProxyEnvName = "HTTPS_PROXY"
+	NoProxyEnvName    = "NO_PROXY"
+
+	// the OpenShift cluster-wide proxy and network configuration are singletons
+	clusterProxyName   = "cluster"
+	clusterNetworkName = "cluster"
+)
+
+var (
+	proxyGVK           = schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "Proxy"}
+	networkGVK         = schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "Network"}
+	serviceCIDRListGVK = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "ServiceCIDRList"}
+
+	// in-cluster names always bypass the proxy, so operands reach services such as the API server
+	clusterNoProxy = []string{".svc", ".cluster.local"}
+)
+
+// Config is the effective proxy of the operands.
+type Config struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
+// Load returns the proxy of spec, or on OpenShift the status of the cluster Proxy object when spec
+// sets no proxy. The status is used rather than the spec, since OpenShift completes noProxy with
+// the cluster and service networks. NoProxy always includes the service networks, .svc and
+// .cluster.local. A nil Config means no proxy.
+func Load(ctx context.Context, c client.Reader, spec *rblnv1beta1.ProxySpec, openshiftVersion string) (*Config, error) {
+	config, err := load(ctx, c, spec, openshiftVersion)
+	if err != nil || config == nil {
+		return nil, err
+	}
+	serviceNetworks, err := loadServiceNetworks(ctx, c, openshiftVersion)
+	if err != nil {
+		return nil, err
+	}
+	config.NoProxy = appendNoProxy(config.NoProxy, append(serviceNetworks, clusterNoProxy...))
+	return config, nil
+}
+
+func load(ctx context.Context, c client.Reader, spec *rblnv1beta1.ProxySpec, openshiftVersion string) (*Config, error) {
+	if spec != nil && (spec.HTTPProxy != "" || spec.HTTPSProxy != "" || spec.NoProxy != "") {
+		return &Config{HTTPProxy: spec.HTTPProxy, HTTPSProxy: spec.HTTPSProxy, NoProxy: spec.NoProxy}, nil
+	}
+	if openshiftVersion == "" {
+		return nil, nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(proxyGVK)
+	if err := c.Get(ctx, types.NamespacedName{Name: clusterProxyName}, obj); err != nil {
+		if kapierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get Proxy %s: %w", clusterProxyName, err)
+	}
+	config := &Config{}
+	config.HTTPProxy, _, _ = unstructured.NestedString(obj.Object, "status", "httpProxy")
+	config.HTTPSProxy, _, _ = unstructured.NestedString(obj.Object, "status", "httpsProxy")
+	config.NoProxy, _, _ = unstructured.NestedString(obj.Object, "status", "noProxy")
+	if config.HTTPProxy == "" && config.HTTPSProxy == "" {
+		return nil, nil
+	}
+	return config, nil
+}
+
+// loadServiceNetworks returns the service networks of the cluster: the status of the Network
+// object on OpenShift, otherwise the ServiceCIDR objects, or the IPs of the kubernetes Service
+// when the cluster serves no ServiceCIDR API.
+func loadServiceNetworks(ctx context.Context, c client.Reader, openshiftVersion string) ([]string, error) {
+	if openshiftVersion != "" {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(networkGVK)
+		err := c.Get(ctx, types.NamespacedName{Name: clusterNetworkName}, obj)
+		if err == nil {
+			networks, _, _ := unstructured.NestedStringSlice(obj.Object, "status", "serviceNetwork")
+			if len(networks) > 0 {
+				return networks, nil
+			}
+		} else if !kapierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("failed to get Network %s: %w", clusterNetworkName, err)
+		}
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(serviceCIDRListGVK)
+	err := c.List(ctx, list)
+	if err != nil && !kapierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("failed to list ServiceCIDRs: %w", err)
+	}
+	var networks []string
+	if err == nil {
+		for _, item := range list.Items {
+			cidrs, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "cidrs")
+			networks = append(networks, cidrs...)
+		}
+	}
+	if len(networks) > 0 {
+		return networks, nil
+	}
+
+	svc := &corev1.Service{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "kubernetes"}, svc); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the kubernetes Service: %w", err)
+	}
+	if len(svc.Spec.ClusterIPs) > 0 {
+		return svc.Spec.ClusterIPs, nil
+	}
+	if svc.Spec.ClusterIP != "" {
+		return []string{svc.Spec.ClusterIP}, nil
+	}
+	return nil, nil
+}
+
+// appendNoProxy appends the entries missing from the comma-separated list noProxy.
+func appendNoProxy(noProxy string, entries []string) string {
+	var merged []string
+	present := map[string]bool{}
+	for _, entry := range strings.Split(noProxy, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" && !present[entry] {
+			present[entry] = true
+			merged = append(merged, entry)
+		}
+	}
+	for _, entry := range entries {
+		if !present[entry] {
+			present[entry] = true
+			merged = append(merged, entry)
+		}
+	}
+	return strings.Join(merged, ",")
+}
+
+// EnvVars returns the proxy variables in upper and lower case, as tools disagree on which they read.
+func (c *Config) EnvVars() []corev1.EnvVar {
+	if c == nil {
+		return nil
+	}
+	var envs []corev1.EnvVar
+	for _, v := range []struct{ name, value string }{
+		{HTTPProxyEnvName, c.HTTPProxy},
+		{HTTPSProxyEnvName, c.HTTPSProxy},
+		{NoProxyEnvName, c.NoProxy},
+	} {
+		if v.value == "" {
+			continue
+		}
+		envs = append(envs,
+			corev1.EnvVar{Name: v.name, Value: v.value},
+			corev1.EnvVar{Name: strings.ToLower(v.name), Value: v.value},
+		)
+	}
+	return envs
+}
+
+// NewClient returns a client injecting config into the workloads written through it. A nil
+// config injects nothing.
+func NewClient(c client.Client, config *Config) client.Client {
+	if config == nil {
+		return c
+	}
+	return k8sutil.NewPodTemplateClient(c, func(_ context.Context, template *corev1.PodTemplateSpec) error {
+		config.InjectPodSpec(&template.Spec)
+		return nil
+	})
+}
+
+// InjectPodSpec adds the proxy variables to every container of spec. Variables a container
+// already sets are kept, so a component can override or clear the proxy through its env.
+func (c *Config) InjectPodSpec(spec *corev1.PodSpec) {
+	envs := c.EnvVars()
+	if len(envs) == 0 {
+		return
+	}
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			containers[i].Env = injectEnv(containers[i].Env, envs)
+		}
+	}
+}
+
+func injectEnv(base, envs []corev1.EnvVar) []corev1.EnvVar {
+	defined := make(map[string]bool, len(base))
+	for _, env := range base {
+		defined[env.Name] = true
+	}
+	// copy so the env of the desired spec shared by the builders is never mutated
+	merged := append([]corev1.EnvVar{}, base...)
+	for _, env := range envs {
+		if !defined[env.Name] {
+			merged = append(merged, env)
+		}
+	}
+	return merged
+}
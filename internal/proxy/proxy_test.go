@@ -0,0 +1,160 @@
+package proxy_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/proxy"
+)
+
+func TestProxy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Proxy Suite")
+}
+
+var _ = Describe("Proxy", func() {
+	var clusterProxy, clusterNetwork *unstructured.Unstructured
+	var kubernetesService *corev1.Service
+
+	newClient := func() *fake.ClientBuilder {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		for _, gvk := range []schema.GroupVersionKind{
+			{Group: "config.openshift.io", Version: "v1", Kind: "Proxy"},
+			{Group: "config.openshift.io", Version: "v1", Kind: "Network"},
+			{Group: "networking.k8s.io", Version: "v1", Kind: "ServiceCIDR"},
+		} {
+			scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+		}
+		return fake.NewClientBuilder().WithScheme(scheme)
+	}
+
+	BeforeEach(func() {
+		clusterProxy = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "config.openshift.io/v1",
+			"kind":       "Proxy",
+			"metadata":   map[string]interface{}{"name": "cluster"},
+			"status": map[string]interface{}{
+				"httpProxy":  "http://proxy.corp:3128",
+				"httpsProxy": "http://proxy.corp:3128",
+				"noProxy":    ".cluster.local,.svc,10.128.0.0/14",
+			},
+		}}
+		clusterNetwork = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "config.openshift.io/v1",
+			"kind":       "Network",
+			"metadata":   map[string]interface{}{"name": "cluster"},
+			"status": map[string]interface{}{
+				"serviceNetwork": []interface{}{"172.30.0.0/16"},
+			},
+		}}
+		kubernetesService = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: metav1.NamespaceDefault},
+			Spec:       corev1.ServiceSpec{ClusterIP: "10.96.0.1", ClusterIPs: []string{"10.96.0.1"}},
+		}
+	})
+
+	Describe("Load", func() {
+		It("should prefer the cluster policy spec", func() {
+			c := newClient().WithObjects(clusterProxy, clusterNetwork).Build()
+			config, err := proxy.Load(context.Background(), c, &rblnv1beta1.ProxySpec{HTTPSProxy: "http://other:8080"}, "4.14")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(Equal(&proxy.Config{
+				HTTPSProxy: "http://other:8080",
+				NoProxy:    "172.30.0.0/16,.svc,.cluster.local",
+			}))
+		})
+
+		It("should read the status of the OpenShift cluster proxy", func() {
+			c := newClient().WithObjects(clusterProxy, clusterNetwork).Build()
+			config, err := proxy.Load(context.Background(), c, nil, "4.14")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(Equal(&proxy.Config{
+				HTTPProxy:  "http://proxy.corp:3128",
+				HTTPSProxy: "http://proxy.corp:3128",
+				NoProxy:    ".cluster.local,.svc,10.128.0.0/14,172.30.0.0/16",
+			}))
+		})
+
+		It("should add the ServiceCIDRs to the no proxy list of the spec", func() {
+			serviceCIDR := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "networking.k8s.io/v1",
+				"kind":       "ServiceCIDR",
+				"metadata":   map[string]interface{}{"name": "kubernetes"},
+				"spec":       map[string]interface{}{"cidrs": []interface{}{"10.96.0.0/12"}},
+			}}
+			c := newClient().WithObjects(serviceCIDR, kubernetesService).Build()
+			config, err := proxy.Load(context.Background(), c, &rblnv1beta1.ProxySpec{
+				HTTPProxy: "http://proxy.corp:3128",
+				NoProxy:   "registry.corp, .svc",
+			}, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.NoProxy).To(Equal("registry.corp,.svc,10.96.0.0/12,.cluster.local"))
+		})
+
+		It("should fall back to the kubernetes Service without ServiceCIDRs", func() {
+			c := newClient().WithObjects(kubernetesService).Build()
+			config, err := proxy.Load(context.Background(), c, &rblnv1beta1.ProxySpec{HTTPProxy: "http://proxy.corp:3128"}, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.NoProxy).To(Equal("10.96.0.1,.svc,.cluster.local"))
+		})
+
+		It("should ignore the cluster proxy outside OpenShift", func() {
+			c := newClient().WithObjects(clusterProxy).Build()
+			config, err := proxy.Load(context.Background(), c, &rblnv1beta1.ProxySpec{}, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(BeNil())
+		})
+
+		It("should return no proxy when the cluster proxy is unset", func() {
+			config, err := proxy.Load(context.Background(), newClient().Build(), nil, "4.14")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(BeNil())
+		})
+	})
+
+	Describe("InjectPodSpec", func() {
+		It("should add the variables to every container and keep explicit ones", func() {
+			env := []corev1.EnvVar{{Name: "NO_PROXY", Value: "*"}}
+			spec := &corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init"}},
+				Containers:     []corev1.Container{{Name: "main", Env: env}},
+			}
+			config := &proxy.Config{HTTPSProxy: "http://proxy.corp:3128", NoProxy: ".svc"}
+			config.InjectPodSpec(spec)
+
+			Expect(spec.InitContainers[0].Env).To(ConsistOf(
+				corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy.corp:3128"},
+				corev1.EnvVar{Name: "https_proxy", Value: "http://proxy.corp:3128"},
+				corev1.EnvVar{Name: "NO_PROXY", Value: ".svc"},
+				corev1.EnvVar{Name: "no_proxy", Value: ".svc"},
+			))
+			Expect(spec.Containers[0].Env).To(ConsistOf(
+				corev1.EnvVar{Name: "NO_PROXY", Value: "*"},
+				corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy.corp:3128"},
+				corev1.EnvVar{Name: "https_proxy", Value: "http://proxy.corp:3128"},
+				corev1.EnvVar{Name: "no_proxy", Value: ".svc"},
+			))
+			Expect(env).To(HaveLen(1))
+		})
+
+		It("should leave the pod untouched without a proxy", func() {
+			var config *proxy.Config
+			spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}}
+			config.InjectPodSpec(spec)
+			Expect(spec.Containers[0].Env).To(BeEmpty())
+		})
+	})
+})
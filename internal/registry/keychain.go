@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Credentials authenticate against a registry.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+func (c *Credentials) basicAuth() string {
+	return base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))
+}
+
+// Keychain maps registry hosts to credentials.
+type Keychain map[string]Credentials
+
+// Lookup returns the credentials of a registry host, or nil.
+func (k Keychain) Lookup(host string) *Credentials {
+	creds, ok := k[normalizeHost(host)]
+	if !ok {
+		return nil
+	}
+	return &creds
+}
+
+type dockerConfigEntry struct {
+	Auth     string `json:"auth"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoadKeychain reads the image pull secrets of a workload. Missing secrets are skipped,
+// as the kubelet does.
+func LoadKeychain(ctx context.Context, c client.Reader, namespace string, pullSecrets []string) (Keychain, error) {
+	keychain := Keychain{}
+	for _, name := range pullSecrets {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret); err != nil {
+			if kapierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get image pull secret %s/%s: %w", namespace, name, err)
+		}
+		if err := keychain.add(secret); err != nil {
+			return nil, fmt.Errorf("invalid image pull secret %s/%s: %w", namespace, name, err)
+		}
+	}
+	return keychain, nil
+}
+
+func (k Keychain) add(secret *corev1.Secret) error {
+	var entries map[string]dockerConfigEntry
+	if data, ok := secret.Data[corev1.DockerConfigJsonKey]; ok {
+		var config struct {
+			Auths map[string]dockerConfigEntry `json:"auths"`
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return err
+		}
+		entries = config.Auths
+	} else if data, ok := secret.Data[corev1.DockerConfigKey]; ok {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return err
+		}
+	}
+
+	for host, entry := range entries {
+		creds := Credentials{Username: entry.Username, Password: entry.Password}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return fmt.Errorf("decode auth of %s: %w", host, err)
+			}
+			creds.Username, creds.Password, _ = strings.Cut(string(decoded), ":")
+		}
+		// the first secret wins, as in the kubelet
+		if _, exists := k[normalizeHost(host)]; !exists {
+			k[normalizeHost(host)] = creds
+		}
+	}
+	return nil
+}
+
+// normalizeHost strips the scheme and path of a docker config key, e.g. https://index.docker.io/v1/.
+func normalizeHost(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case "index.docker.io", dockerHubRegistry:
+		return dockerHubHost
+	}
+	return host
+}
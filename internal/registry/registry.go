@@ -0,0 +1,298 @@
+// Package registry checks whether container images exist in their registry through the
+// Docker Registry HTTP API V2, so workloads that cannot pull their image are not created.
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	dockerHubHost     = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+
+	defaultTimeout = 10 * time.Second
+	// existing images are rarely removed, missing ones are expected to be pushed soon
+	foundTTL   = 10 * time.Minute
+	missingTTL = time.Minute
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Reference is a parsed image reference.
+type Reference struct {
+	// Host is the registry host as written in the image, docker.io when omitted
+	Host string
+	// Repository is the repository path within the registry
+	Repository string
+	// Reference is the tag or digest
+	Reference string
+}
+
+// ParseReference splits an image into registry host, repository and tag or digest.
+func ParseReference(image string) (Reference, error) {
+	name, ref := image, "latest"
+	if i := strings.Index(image, "@"); i >= 0 {
+		name, ref = image[:i], image[i+1:]
+	} else if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name, ref = image[:i], image[i+1:]
+	}
+	if name == "" || ref == "" {
+		return Reference{}, fmt.Errorf("invalid image reference %q", image)
+	}
+
+	host, repository := dockerHubHost, name
+	if i := strings.Index(name, "/"); i >= 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			host, repository = first, name[i+1:]
+		}
+	}
+	if host == dockerHubHost && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	if repository == "" {
+		return Reference{}, fmt.Errorf("invalid image reference %q", image)
+	}
+	return Reference{Host: host, Repository: repository, Reference: ref}, nil
+}
+
+// Client resolves image manifests. Results are cached briefly, since every reconcile
+// checks the images of all node pools.
+type Client struct {
+	base *http.Client
+
+	mu         sync.Mutex
+	httpClient *http.Client
+	bundle     string
+	cache      map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	exists  bool
+	expires time.Time
+}
+
+// NewClient returns a registry client. A nil httpClient uses a client with a default timeout.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Client{
+		base:       httpClient,
+		httpClient: httpClient,
+		cache:      map[string]cacheEntry{},
+	}
+}
+
+// TrustBundle makes the client trust the PEM bundle on top of the system roots, for registries
+// signed by an internal CA. An empty bundle trusts the system roots only. The transport is only
+// rebuilt when the bundle changes.
+func (c *Client) TrustBundle(bundle string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if bundle == c.bundle {
+		return nil
+	}
+	if bundle == "" {
+		c.httpClient, c.bundle = c.base, ""
+		return nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM([]byte(bundle)) {
+		return fmt.Errorf("trusted CA bundle has no PEM certificates")
+	}
+	transport, ok := c.base.Transport.(*http.Transport)
+	if !ok {
+		transport, _ = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	transport.TLSClientConfig.RootCAs = pool
+
+	httpClient := *c.base
+	httpClient.Transport = transport
+	c.httpClient, c.bundle = &httpClient, bundle
+	return nil
+}
+
+func (c *Client) client() *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.httpClient
+}
+
+// Exists reports whether the manifest of image exists. Credentials are looked up in keychain
+// by registry host. An error means the registry could not answer, not that the image is missing.
+func (c *Client) Exists(ctx context.Context, image string, keychain Keychain) (bool, error) {
+	c.mu.Lock()
+	entry, ok := c.cache[image]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.exists, nil
+	}
+
+	ref, err := ParseReference(image)
+	if err != nil {
+		return false, err
+	}
+	exists, err := c.headManifest(ctx, ref, keychain.Lookup(ref.Host))
+	if err != nil {
+		return false, err
+	}
+
+	ttl := missingTTL
+	if exists {
+		ttl = foundTTL
+	}
+	c.mu.Lock()
+	c.cache[image] = cacheEntry{exists: exists, expires: time.Now().Add(ttl)}
+	c.mu.Unlock()
+	return exists, nil
+}
+
+func (c *Client) headManifest(ctx context.Context, ref Reference, creds *Credentials) (bool, error) {
+	host := ref.Host
+	if host == dockerHubHost {
+		host = dockerHubRegistry
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, ref.Repository, ref.Reference)
+
+	resp, err := c.do(ctx, manifestURL, "")
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := c.authorize(ctx, resp.Header.Get("WWW-Authenticate"), ref, creds)
+		if err != nil {
+			return false, err
+		}
+		if resp, err = c.do(ctx, manifestURL, authorization); err != nil {
+			return false, err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status %s resolving %s/%s:%s", resp.Status, ref.Host, ref.Repository, ref.Reference)
+	}
+}
+
+func (c *Client) do(ctx context.Context, manifestURL, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// HEAD responses carry no body
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+// authorize answers the challenge of the registry with basic credentials or a bearer token.
+func (c *Client) authorize(ctx context.Context, challenge string, ref Reference, creds *Credentials) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if creds == nil {
+			return "", fmt.Errorf("registry %s requires credentials", ref.Host)
+		}
+		return "Basic " + creds.basicAuth(), nil
+	case "bearer":
+		return c.bearerToken(ctx, params, ref, creds)
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q from registry %s", challenge, ref.Host)
+	}
+}
+
+func (c *Client) bearerToken(ctx context.Context, params map[string]string, ref Reference, creds *Credentials) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid token realm %q from registry %s", params["realm"], ref.Host)
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if creds != nil {
+		req.Header.Set("Authorization", "Basic "+creds.basicAuth())
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s requesting a token for registry %s", resp.Status, ref.Host)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("decode token of registry %s: %w", ref.Host, err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return "", fmt.Errorf("registry %s returned an empty token", ref.Host)
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallenge parses a WWW-Authenticate header, e.g. Bearer realm="...",service="...".
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			params[key] = value
+		}
+	}
+	return scheme, params
+}
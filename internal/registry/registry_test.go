@@ -0,0 +1,149 @@
+package registry_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/registry"
+)
+
+func TestRegistry(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Registry Suite")
+}
+
+// fakeRegistry serves the manifests of the given repository:reference pairs behind token auth.
+type fakeRegistry struct {
+	manifests map[string]bool
+	username  string
+	password  string
+	requests  int
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests++
+	if r.URL.Path == "/token" {
+		user, pass, _ := r.BasicAuth()
+		if user != f.username || pass != f.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"token":"t-%s"}`, r.URL.Query().Get("scope"))
+		return
+	}
+
+	repository, reference, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")
+	if !ok || r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.Header.Get("Authorization") != fmt.Sprintf("Bearer t-repository:%s:pull", repository) {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="fake"`, r.Host))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if !f.manifests[repository+":"+reference] {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+var _ = Describe("Registry", func() {
+	Describe("ParseReference", func() {
+		DescribeTable("should split the image",
+			func(image string, expected registry.Reference) {
+				Expect(registry.ParseReference(image)).To(Equal(expected))
+			},
+			Entry("registry with port", "localhost:5000/rebellions/driver:3.0.0", registry.Reference{Host: "localhost:5000", Repository: "rebellions/driver", Reference: "3.0.0"}),
+			Entry("digest", "repo.rebellions.ai/rebellions/driver@sha256:abc", registry.Reference{Host: "repo.rebellions.ai", Repository: "rebellions/driver", Reference: "sha256:abc"}),
+			Entry("docker hub", "rebellions/driver", registry.Reference{Host: "docker.io", Repository: "rebellions/driver", Reference: "latest"}),
+			Entry("docker hub library", "busybox:1.36", registry.Reference{Host: "docker.io", Repository: "library/busybox", Reference: "1.36"}),
+		)
+	})
+
+	Describe("Client", func() {
+		var (
+			fakeReg  *fakeRegistry
+			server   *httptest.Server
+			host     string
+			keychain registry.Keychain
+		)
+
+		BeforeEach(func() {
+			fakeReg = &fakeRegistry{
+				manifests: map[string]bool{"rebellions/driver:3.0.0-5.15.0-ubuntu22.04": true},
+				username:  "robot",
+				password:  "secret",
+			}
+			server = httptest.NewTLSServer(fakeReg)
+			DeferCleanup(server.Close)
+			host = strings.TrimPrefix(server.URL, "https://")
+
+			auth := base64.StdEncoding.EncodeToString([]byte("robot:secret"))
+			c := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "pull-secret", Namespace: "rbln-system"},
+				Type:       corev1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					corev1.DockerConfigJsonKey: []byte(fmt.Sprintf(`{"auths":{"https://%s/v1/":{"auth":%q}}}`, host, auth)),
+				},
+			}).Build()
+			var err error
+			keychain, err = registry.LoadKeychain(context.Background(), c, "rbln-system", []string{"pull-secret", "missing"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should find an existing image with the pull secret", func() {
+			exists, err := registry.NewClient(server.Client()).Exists(context.Background(), host+"/rebellions/driver:3.0.0-5.15.0-ubuntu22.04", keychain)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+		})
+
+		It("should report a missing image", func() {
+			exists, err := registry.NewClient(server.Client()).Exists(context.Background(), host+"/rebellions/driver:3.0.0-6.8.0-ubuntu22.04", keychain)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
+		It("should fail without valid credentials", func() {
+			_, err := registry.NewClient(server.Client()).Exists(context.Background(), host+"/rebellions/driver:3.0.0-5.15.0-ubuntu22.04", nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should cache the result", func() {
+			c := registry.NewClient(server.Client())
+			image := host + "/rebellions/driver:3.0.0-5.15.0-ubuntu22.04"
+			Expect(c.Exists(context.Background(), image, keychain)).To(BeTrue())
+			requests := fakeReg.requests
+			Expect(c.Exists(context.Background(), image, keychain)).To(BeTrue())
+			Expect(fakeReg.requests).To(Equal(requests))
+		})
+
+		It("should trust the registry with the trusted CA bundle", func() {
+			c := registry.NewClient(nil)
+			image := host + "/rebellions/driver:3.0.0-5.15.0-ubuntu22.04"
+			_, err := c.Exists(context.Background(), image, keychain)
+			Expect(err).To(HaveOccurred())
+
+			bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+			Expect(c.TrustBundle(string(bundle))).To(Succeed())
+			Expect(c.Exists(context.Background(), image, keychain)).To(BeTrue())
+		})
+
+		It("should reject a bundle without certificates", func() {
+			Expect(registry.NewClient(nil).TrustBundle("not a certificate")).NotTo(Succeed())
+		})
+	})
+})
@@ -0,0 +1,313 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// objectKey identifies an object across kinds.
+type objectKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+// Recorder is a client that never writes to the cluster. Written objects are kept in memory and
+// read back over the objects of the underlying reader, so the patchers see their own writes.
+type Recorder struct {
+	reader client.Reader
+	scheme *runtime.Scheme
+	mapper meta.RESTMapper
+
+	objects map[objectKey]client.Object
+	deleted map[objectKey]bool
+	// written are the keys of the objects written by the patchers, as opposed to seeded inputs
+	written map[objectKey]bool
+}
+
+// NewRecorder returns a recorder reading through reader. A nil reader starts from an empty cluster.
+func NewRecorder(reader client.Reader, scheme *runtime.Scheme, mapper meta.RESTMapper) *Recorder {
+	return &Recorder{
+		reader:  reader,
+		scheme:  scheme,
+		mapper:  mapper,
+		objects: map[objectKey]client.Object{},
+		deleted: map[objectKey]bool{},
+		written: map[objectKey]bool{},
+	}
+}
+
+// Seed adds input objects, e.g. nodes or custom resources read from files, without rendering them.
+func (r *Recorder) Seed(objs ...client.Object) error {
+	for _, obj := range objs {
+		key, err := r.keyOf(obj)
+		if err != nil {
+			return err
+		}
+		r.objects[key] = obj.DeepCopyObject().(client.Object)
+		delete(r.deleted, key)
+	}
+	return nil
+}
+
+// Objects returns the objects written by the patchers, in apply order.
+func (r *Recorder) Objects() []client.Object {
+	keys := make([]objectKey, 0, len(r.written))
+	for key := range r.written {
+		if _, ok := r.objects[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if ka, kb := kindRank(a.gvk.Kind), kindRank(b.gvk.Kind); ka != kb {
+			return ka < kb
+		}
+		if a.gvk.Kind != b.gvk.Kind {
+			return a.gvk.Kind < b.gvk.Kind
+		}
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		return a.name < b.name
+	})
+
+	objs := make([]client.Object, 0, len(keys))
+	for _, key := range keys {
+		obj := r.objects[key].DeepCopyObject().(client.Object)
+		obj.GetObjectKind().SetGroupVersionKind(key.gvk)
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+func (r *Recorder) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	gvk, err := apiutil.GVKForObject(obj, r.scheme)
+	if err != nil {
+		return err
+	}
+	k := objectKey{gvk: gvk, namespace: key.Namespace, name: key.Name}
+	if stored, ok := r.objects[k]; ok {
+		return r.convert(stored, obj, gvk)
+	}
+	if r.deleted[k] || r.reader == nil {
+		return kapierrors.NewNotFound(r.resourceOf(gvk), key.Name)
+	}
+	return r.reader.Get(ctx, key, obj, opts...)
+}
+
+func (r *Recorder) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	gvk, err := apiutil.GVKForObject(list, r.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+
+	var items []runtime.Object
+	if r.reader != nil {
+		if err := r.reader.List(ctx, list, opts...); err != nil {
+			return err
+		}
+		if items, err = meta.ExtractList(list); err != nil {
+			return err
+		}
+	}
+
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	// objects held in memory replace their counterparts of the reader
+	merged := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			return fmt.Errorf("unexpected list item %T", item)
+		}
+		k := objectKey{gvk: gvk, namespace: obj.GetNamespace(), name: obj.GetName()}
+		if _, ok := r.objects[k]; ok || r.deleted[k] {
+			continue
+		}
+		merged = append(merged, item)
+	}
+
+	keys := make([]objectKey, 0)
+	for k, stored := range r.objects {
+		if k.gvk != gvk || !matches(stored, listOpts) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].namespace != keys[j].namespace {
+			return keys[i].namespace < keys[j].namespace
+		}
+		return keys[i].name < keys[j].name
+	})
+	for _, k := range keys {
+		item, err := r.newItem(list, gvk)
+		if err != nil {
+			return err
+		}
+		if err := r.convert(r.objects[k], item, gvk); err != nil {
+			return err
+		}
+		merged = append(merged, item)
+	}
+	return meta.SetList(list, merged)
+}
+
+func (r *Recorder) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	return r.write(obj)
+}
+
+func (r *Recorder) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	return r.write(obj)
+}
+
+// Patch records obj as a whole: the patchers patch the complete desired object.
+func (r *Recorder) Patch(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	return r.write(obj)
+}
+
+func (r *Recorder) Delete(ctx context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	key, err := r.keyOf(obj)
+	if err != nil {
+		return err
+	}
+	if _, ok := r.objects[key]; !ok {
+		// surface NotFound like the API server for objects that never existed
+		existing := obj.DeepCopyObject().(client.Object)
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+			return err
+		}
+	}
+	delete(r.objects, key)
+	delete(r.written, key)
+	r.deleted[key] = true
+	return nil
+}
+
+func (r *Recorder) DeleteAllOf(_ context.Context, _ client.Object, _ ...client.DeleteAllOfOption) error {
+	return nil
+}
+
+func (r *Recorder) Status() client.SubResourceWriter {
+	return discardWriter{}
+}
+
+func (r *Recorder) SubResource(_ string) client.SubResourceClient {
+	return discardWriter{}
+}
+
+func (r *Recorder) Scheme() *runtime.Scheme {
+	return r.scheme
+}
+
+func (r *Recorder) RESTMapper() meta.RESTMapper {
+	return r.mapper
+}
+
+func (r *Recorder) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	return apiutil.GVKForObject(obj, r.scheme)
+}
+
+func (r *Recorder) IsObjectNamespaced(obj runtime.Object) (bool, error) {
+	return apiutil.IsObjectNamespaced(obj, r.scheme, r.mapper)
+}
+
+func (r *Recorder) write(obj client.Object) error {
+	key, err := r.keyOf(obj)
+	if err != nil {
+		return err
+	}
+	r.objects[key] = obj.DeepCopyObject().(client.Object)
+	r.written[key] = true
+	delete(r.deleted, key)
+	return nil
+}
+
+func (r *Recorder) keyOf(obj client.Object) (objectKey, error) {
+	gvk, err := apiutil.GVKForObject(obj, r.scheme)
+	if err != nil {
+		return objectKey{}, err
+	}
+	return objectKey{gvk: gvk, namespace: obj.GetNamespace(), name: obj.GetName()}, nil
+}
+
+func (r *Recorder) resourceOf(gvk schema.GroupVersionKind) schema.GroupResource {
+	if mapping, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+		return mapping.Resource.GroupResource()
+	}
+	return schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(gvk.Kind) + "s"}
+}
+
+// newItem returns an empty item of list.
+func (r *Recorder) newItem(list client.ObjectList, gvk schema.GroupVersionKind) (runtime.Object, error) {
+	if _, ok := list.(*unstructured.UnstructuredList); ok {
+		item := &unstructured.Unstructured{}
+		item.SetGroupVersionKind(gvk)
+		return item, nil
+	}
+	return r.scheme.New(gvk)
+}
+
+// convert copies src into dst, converting between typed and unstructured objects.
+func (r *Recorder) convert(src, dst runtime.Object, gvk schema.GroupVersionKind) error {
+	if reflect.TypeOf(src) == reflect.TypeOf(dst) {
+		reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(src.DeepCopyObject()).Elem())
+		return nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(src)
+	if err != nil {
+		return err
+	}
+	if u, ok := dst.(*unstructured.Unstructured); ok {
+		u.SetUnstructuredContent(content)
+		u.SetGroupVersionKind(gvk)
+		return nil
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(content, dst)
+}
+
+func matches(obj client.Object, opts *client.ListOptions) bool {
+	if opts.Namespace != "" && obj.GetNamespace() != opts.Namespace {
+		return false
+	}
+	if opts.LabelSelector != nil && !opts.LabelSelector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	return true
+}
+
+// discardWriter drops status and subresource writes.
+type discardWriter struct{}
+
+func (discardWriter) Get(_ context.Context, _, _ client.Object, _ ...client.SubResourceGetOption) error {
+	return nil
+}
+
+func (discardWriter) Create(_ context.Context, _, _ client.Object, _ ...client.SubResourceCreateOption) error {
+	return nil
+}
+
+func (discardWriter) Update(_ context.Context, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+	return nil
+}
+
+func (discardWriter) Patch(_ context.Context, _ client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	return nil
+}
+
+var _ client.Client = &Recorder{}
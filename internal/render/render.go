@@ -0,0 +1,289 @@
+// Package render produces the manifests the operator would apply for a RBLNClusterPolicy, its
+// RBLNDrivers and RBLNFirmwares, without writing to the cluster. The scopes and patchers of the controllers run
+// unchanged against a Recorder, so the output is exactly what reconciling would write.
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/scope"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/validator"
+)
+
+// Options describes the cluster the manifests are rendered for.
+type Options struct {
+	OpenshiftVersion string
+	// ContainerRuntime is the runtime of nodes whose container runtime cannot be detected
+	ContainerRuntime string
+}
+
+// kindOrder is the order manifests are written in, so they can be applied as is.
+var kindOrder = []string{
+	"Namespace",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"ValidatingAdmissionPolicy",
+	"ValidatingAdmissionPolicyBinding",
+	"Role",
+	"RoleBinding",
+	"SecurityContextConstraints",
+	"RuntimeClass",
+	"Service",
+	"NetworkPolicy",
+	"Certificate",
+	"DaemonSet",
+	"Deployment",
+	"Job",
+	"ServiceMonitor",
+	"PrometheusRule",
+	"GrafanaDashboard",
+}
+
+func kindRank(kind string) int {
+	for i, k := range kindOrder {
+		if k == kind {
+			return i
+		}
+	}
+	return len(kindOrder)
+}
+
+// clusterScopedKinds are the cluster-scoped kinds the patchers read or write.
+var clusterScopedKinds = map[string]bool{
+	"Namespace":                        true,
+	"Node":                             true,
+	"ClusterRole":                      true,
+	"ClusterRoleBinding":               true,
+	"RuntimeClass":                     true,
+	"SecurityContextConstraints":       true,
+	"ValidatingAdmissionPolicy":        true,
+	"ValidatingAdmissionPolicyBinding": true,
+	"RBLNClusterPolicy":                true,
+	"RBLNDriver":                       true,
+	"RBLNFirmware":                     true,
+}
+
+// NewRESTMapper returns a mapper of the kinds of scheme and of apiVersions, given as
+// group/version/kind like the --api-versions of helm template. Optional components such as
+// ServiceMonitors are only rendered when their kind is listed.
+func NewRESTMapper(scheme *runtime.Scheme, apiVersions []string) (meta.RESTMapper, error) {
+	mapper := meta.NewDefaultRESTMapper(scheme.PrioritizedVersionsAllGroups())
+	add := func(gvk schema.GroupVersionKind) {
+		restScope := meta.RESTScopeNamespace
+		if clusterScopedKinds[gvk.Kind] {
+			restScope = meta.RESTScopeRoot
+		}
+		mapper.Add(gvk, restScope)
+	}
+	for gvk := range scheme.AllKnownTypes() {
+		add(gvk)
+	}
+	for _, apiVersion := range apiVersions {
+		i := strings.LastIndex(apiVersion, "/")
+		if i <= 0 || i == len(apiVersion)-1 {
+			return nil, fmt.Errorf("invalid API version %q: expected group/version/kind", apiVersion)
+		}
+		gv, err := schema.ParseGroupVersion(apiVersion[:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid API version %q: %w", apiVersion, err)
+		}
+		add(gv.WithKind(apiVersion[i+1:]))
+	}
+	return mapper, nil
+}
+
+// Render reconciles the RBLNClusterPolicy, the RBLNDrivers and the RBLNFirmwares visible to r and
+// returns the objects the patchers wrote. Nodes are labelled like the controller does, but not
+// rendered. Firmware flash Jobs depend on the reported inventory and are not rendered either.
+func Render(ctx context.Context, r *Recorder, log logr.Logger, opts Options) ([]client.Object, error) {
+	policies := &rblnv1beta1.RBLNClusterPolicyList{}
+	if err := r.List(ctx, policies); err != nil {
+		return nil, fmt.Errorf("failed to list RBLNClusterPolicy: %w", err)
+	}
+	switch len(policies.Items) {
+	case 0:
+		return nil, errors.New("no RBLNClusterPolicy to render")
+	case 1:
+	default:
+		return nil, fmt.Errorf("found %d RBLNClusterPolicy objects; expected a single one", len(policies.Items))
+	}
+	clusterPolicy := &policies.Items[0]
+
+	cpScope, err := scope.NewRBLNClusterPolicyScope(ctx, r, log, r.Scheme(), clusterPolicy, opts.OpenshiftVersion, opts.ContainerRuntime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize RBLNClusterPolicy scope: %w", err)
+	}
+	nfdInstalled, rblnNodes, err := cpScope.LabelRblnNodes()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !nfdInstalled:
+		log.Info("WARNING: no node has NodeFeatureDiscovery labels. Skip RBLNClusterPolicy components")
+	case rblnNodes == 0:
+		log.Info("WARNING: no Rebellions NPU node. Skip RBLNClusterPolicy components")
+	default:
+		if err := cpScope.PatchComponents(ctx); err != nil {
+			return nil, fmt.Errorf("failed to render RBLNClusterPolicy components: %w", err)
+		}
+	}
+
+	drivers := &rebellionsaiv1alpha1.RBLNDriverList{}
+	if err := r.List(ctx, drivers); err != nil {
+		return nil, fmt.Errorf("failed to list RBLNDriver: %w", err)
+	}
+	sort.Slice(drivers.Items, func(i, j int) bool { return drivers.Items[i].Name < drivers.Items[j].Name })
+	selectors := validator.NewNodeSelectorValidator(r)
+	for i := range drivers.Items {
+		driver := &drivers.Items[i]
+		if err := selectors.Validate(ctx, driver); err != nil {
+			log.Info("WARNING: nodeSelector validation failed; skip RBLNDriver", "name", driver.Name, "error", err.Error())
+			continue
+		}
+		driverScope, err := scope.NewRBLNDriverScope(ctx, r, log, r.Scheme(), driver, clusterPolicy, opts.OpenshiftVersion, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize RBLNDriver scope %s: %w", driver.Name, err)
+		}
+		if err := driverScope.PatchComponents(ctx); err != nil {
+			return nil, fmt.Errorf("failed to render RBLNDriver %s: %w", driver.Name, err)
+		}
+	}
+
+	firmwares := &rebellionsaiv1alpha1.RBLNFirmwareList{}
+	if err := r.List(ctx, firmwares); err != nil {
+		return nil, fmt.Errorf("failed to list RBLNFirmware: %w", err)
+	}
+	sort.Slice(firmwares.Items, func(i, j int) bool { return firmwares.Items[i].Name < firmwares.Items[j].Name })
+	for i := range firmwares.Items {
+		firmware := &firmwares.Items[i]
+		firmwareScope, err := scope.NewRBLNFirmwareScope(ctx, r, log, r.Scheme(), firmware, clusterPolicy, opts.OpenshiftVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize RBLNFirmware scope %s: %w", firmware.Name, err)
+		}
+		if err := firmwareScope.PatchComponents(ctx); err != nil {
+			return nil, fmt.Errorf("failed to render RBLNFirmware %s: %w", firmware.Name, err)
+		}
+	}
+
+	var objs []client.Object
+	for _, obj := range r.Objects() {
+		if _, ok := obj.(*corev1.Node); ok {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// Decode reads the objects of a multi-document YAML or JSON stream. Lists, e.g. the output of
+// kubectl get -o yaml, are flattened. Kinds known to scheme are returned typed.
+func Decode(in io.Reader, scheme *runtime.Scheme) ([]client.Object, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(in, 4096)
+	var objs []client.Object
+	for {
+		u := &unstructured.Unstructured{}
+		if err := decoder.Decode(&u.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, err
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+
+		items := []*unstructured.Unstructured{u}
+		if u.IsList() {
+			list, err := u.ToList()
+			if err != nil {
+				return nil, err
+			}
+			items = items[:0]
+			for i := range list.Items {
+				items = append(items, &list.Items[i])
+			}
+		}
+		for _, item := range items {
+			obj, err := toTyped(item, scheme)
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, obj)
+		}
+	}
+}
+
+func toTyped(u *unstructured.Unstructured, scheme *runtime.Scheme) (client.Object, error) {
+	gvk := u.GroupVersionKind()
+	if gvk.Kind == "" {
+		return nil, fmt.Errorf("object %q has no kind", u.GetName())
+	}
+	if !scheme.Recognizes(gvk) {
+		return u, nil
+	}
+	typed, err := scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, typed); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %q: %w", gvk.Kind, u.GetName(), err)
+	}
+	obj, ok := typed.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an object", gvk.Kind)
+	}
+	return obj, nil
+}
+
+// Encode writes objs as a multi-document YAML stream. Fields set by the API server are dropped,
+// so the output only changes with the inputs.
+func Encode(out io.Writer, objs []client.Object) error {
+	for _, obj := range objs {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		u := &unstructured.Unstructured{Object: content}
+		u.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+		for _, field := range [][]string{
+			{"metadata", "creationTimestamp"},
+			{"metadata", "resourceVersion"},
+			{"metadata", "uid"},
+			{"metadata", "generation"},
+			{"metadata", "managedFields"},
+			{"spec", "template", "metadata", "creationTimestamp"},
+			{"status"},
+		} {
+			unstructured.RemoveNestedField(u.Object, field...)
+		}
+
+		data, err := yaml.Marshal(u.Object)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(out, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package render_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/render"
+)
+
+func TestRender(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Render Suite")
+}
+
+const inputs = `
+apiVersion: rebellions.ai/v1beta1
+kind: RBLNClusterPolicy
+metadata:
+  name: rbln-cluster-policy
+spec:
+  namespace: rbln-system
+  name: rbln
+  workloadType: container
+  devicePlugin:
+    enabled: true
+    registry: docker.io
+    image: rebellions/k8s-device-plugin
+    version: latest
+    hostBinPath: /run/rbln/driver/usr/bin
+    resourceList:
+      - resourceName: ATOM
+        resourcePrefix: rebellions.ai
+        productCardNames: [RBLN-CA22]
+  validator:
+    managementState: Removed
+---
+apiVersion: rebellions.ai/v1alpha1
+kind: RBLNDriver
+metadata:
+  name: rbln-driver
+spec:
+  registry: repo.rebellions.ai
+  image: rebellions/rbln-driver
+  version: "3.0.0"
+  manager:
+    registry: docker.io
+    image: rebellions/rbln-k8s-driver-manager
+    version: latest
+---
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: Node
+    metadata:
+      name: node-1
+      labels:
+        feature.node.kubernetes.io/pci-1eff.present: "true"
+        feature.node.kubernetes.io/system-os_release.ID: ubuntu
+        feature.node.kubernetes.io/system-os_release.VERSION_ID: "22.04"
+        feature.node.kubernetes.io/kernel-version.full: 5.15.0-100-generic
+  - apiVersion: v1
+    kind: Node
+    metadata:
+      name: node-2
+      labels:
+        feature.node.kubernetes.io/pci-1eff.present: "true"
+        feature.node.kubernetes.io/system-os_release.ID: ubuntu
+        feature.node.kubernetes.io/system-os_release.VERSION_ID: "22.04"
+        feature.node.kubernetes.io/kernel-version.full: 6.8.0-40-generic
+`
+
+var _ = Describe("Render", func() {
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(rebellionsaiv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	newRecorder := func(reader client.Reader) *render.Recorder {
+		mapper, err := render.NewRESTMapper(scheme, nil)
+		Expect(err).NotTo(HaveOccurred())
+		return render.NewRecorder(reader, scheme, mapper)
+	}
+
+	It("should render the components and a driver DaemonSet per node pool", func() {
+		objs, err := render.Decode(strings.NewReader(inputs), scheme)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(4))
+
+		r := newRecorder(nil)
+		Expect(r.Seed(objs...)).To(Succeed())
+		rendered, err := render.Render(context.Background(), r, logr.Discard(), render.Options{ContainerRuntime: "containerd"})
+		Expect(err).NotTo(HaveOccurred())
+
+		var daemonSets []string
+		kinds := map[string]bool{}
+		for _, obj := range rendered {
+			kinds[obj.GetObjectKind().GroupVersionKind().Kind] = true
+			if ds, ok := obj.(*appsv1.DaemonSet); ok {
+				daemonSets = append(daemonSets, ds.Name)
+			}
+		}
+		Expect(kinds).To(HaveKey("ServiceAccount"))
+		Expect(kinds).NotTo(HaveKey("Node"))
+		Expect(daemonSets).To(ContainElement("rbln-device-plugin"))
+		Expect(daemonSets).NotTo(ContainElement(ContainSubstring("validator")))
+		driverPools := 0
+		for _, name := range daemonSets {
+			if strings.HasPrefix(name, "rbln-driver") {
+				driverPools++
+			}
+		}
+		Expect(driverPools).To(Equal(2))
+
+		var out bytes.Buffer
+		Expect(render.Encode(&out, rendered)).To(Succeed())
+		Expect(out.String()).To(HavePrefix("---\napiVersion: v1\nkind: ServiceAccount\n"))
+		Expect(out.String()).NotTo(ContainSubstring("creationTimestamp"))
+		Expect(out.String()).NotTo(ContainSubstring("status:"))
+	})
+
+	It("should render the firmware inventory", func() {
+		objs, err := render.Decode(strings.NewReader(inputs+`
+---
+apiVersion: rebellions.ai/v1alpha1
+kind: RBLNFirmware
+metadata:
+  name: rbln-firmware
+spec:
+  cards:
+  - product: RBLN-CA22
+    version: "1.2.0"
+  registry: repo.rebellions.ai
+  image: rebellions/rbln-firmware
+  version: "1.2.0"
+`), scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		r := newRecorder(nil)
+		Expect(r.Seed(objs...)).To(Succeed())
+		rendered, err := render.Render(context.Background(), r, logr.Discard(), render.Options{ContainerRuntime: "containerd"})
+		Expect(err).NotTo(HaveOccurred())
+
+		names := map[string][]string{}
+		for _, obj := range rendered {
+			kind := obj.GetObjectKind().GroupVersionKind().Kind
+			names[kind] = append(names[kind], obj.GetName())
+		}
+		Expect(names["DaemonSet"]).To(ContainElement(ContainSubstring("firmware")))
+		Expect(names["ClusterRole"]).To(ContainElement(ContainSubstring("firmware")))
+		Expect(names["ValidatingAdmissionPolicy"]).To(ContainElement(ContainSubstring("firmware")))
+		Expect(names["ValidatingAdmissionPolicyBinding"]).To(ContainElement(ContainSubstring("firmware")))
+	})
+
+	It("should fail without a RBLNClusterPolicy", func() {
+		_, err := render.Render(context.Background(), newRecorder(nil), logr.Discard(), render.Options{})
+		Expect(err).To(MatchError(ContainSubstring("no RBLNClusterPolicy")))
+	})
+
+	Describe("Recorder", func() {
+		var (
+			reader client.Client
+			r      *render.Recorder
+		)
+
+		BeforeEach(func() {
+			reader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "ns", Labels: map[string]string{"app": "x"}}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "ns", Labels: map[string]string{"app": "x"}}},
+			).Build()
+			r = newRecorder(reader)
+		})
+
+		It("should keep writes in memory and read them back over the cluster", func() {
+			written := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "ns", Labels: map[string]string{"app": "x"}},
+				Data:       map[string]string{"k": "v"},
+			}
+			Expect(r.Update(context.Background(), written)).To(Succeed())
+			Expect(r.Delete(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "ns"}})).To(Succeed())
+
+			list := &corev1.ConfigMapList{}
+			Expect(r.List(context.Background(), list, client.MatchingLabels{"app": "x"})).To(Succeed())
+			Expect(list.Items).To(HaveLen(1))
+			Expect(list.Items[0].Data).To(HaveKeyWithValue("k", "v"))
+
+			live := &corev1.ConfigMap{}
+			Expect(reader.Get(context.Background(), types.NamespacedName{Name: "live", Namespace: "ns"}, live)).To(Succeed())
+			Expect(live.Data).To(BeEmpty())
+			Expect(reader.Get(context.Background(), types.NamespacedName{Name: "stale", Namespace: "ns"}, &corev1.ConfigMap{})).To(Succeed())
+
+			Expect(r.Objects()).To(HaveLen(1))
+		})
+
+		It("should not render seeded inputs", func() {
+			Expect(r.Seed(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})).To(Succeed())
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: "node-1"}, &corev1.Node{})).To(Succeed())
+			Expect(r.Objects()).To(BeEmpty())
+		})
+	})
+})
@@ -13,7 +13,12 @@ import (
 
 	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
 	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/imagemirror"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/nodefeature"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/nodetaint"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/proxy"
 	"github.com/rebellions-sw/rbln-npu-operator/internal/scope/patch"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/trustedca"
 )
 
 // RBLNClusterPolicyScope is a scope for reconciling a RBLNClusterPolicy resource.
@@ -26,6 +31,13 @@ type RBLNClusterPolicyScope struct {
 	singleton *rblnv1beta1.RBLNClusterPolicy
 	namespace string
 
+	openshiftVersion string
+
+	// containerRuntime is the cluster-wide runtime used for nodes whose runtime cannot be detected
+	containerRuntime string
+	// images rewrites the images of the components to their mirrors and records them
+	images *imagemirror.Client
+
 	patcher []patch.Patcher
 }
 
@@ -36,6 +48,9 @@ func NewRBLNClusterPolicyScope(ctx context.Context, client client.Client, log lo
 		log:       log,
 		scheme:    scheme,
 		singleton: clusterPolicy,
+
+		openshiftVersion: openshiftVersion,
+		containerRuntime: containerRuntime,
 	}
 
 	if s.singleton.Spec.Namespace != "" {
@@ -50,6 +65,27 @@ func NewRBLNClusterPolicyScope(ctx context.Context, client client.Client, log lo
 		return nil, err
 	}
 
+	rewriter, err := imagemirror.Load(ctx, client, clusterPolicy.Spec.ImageMirrors, openshiftVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image mirrors: %w", err)
+	}
+	s.images = imagemirror.NewClient(client, rewriter)
+	proxyConfig, err := proxy.Load(ctx, client, clusterPolicy.Spec.Proxy, openshiftVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load proxy: %w", err)
+	}
+	// components are written through the image mirror client, mount the trusted CA bundle,
+	// get the proxy and tolerate the NPU node taint
+	var trustedCAName string
+	var mergeImage trustedca.MergeImage
+	if clusterPolicy.Spec.TrustedCA != nil {
+		trustedCAName = trustedca.ConfigMapName(clusterPolicy.Spec.BaseName)
+		if mergeImage, err = trustedca.ValidatorMergeImage(&clusterPolicy.Spec.Validator); err != nil {
+			return nil, err
+		}
+	}
+	client = nodetaint.NewClient(proxy.NewClient(trustedca.NewClient(s.images, s.namespace, trustedCAName, mergeImage), proxyConfig), clusterPolicy.Spec.IsNodeTaintEnabled())
+
 	vmp, err := patch.NewVFIOManagerPatcher(client, log, s.namespace, &clusterPolicy.Spec, scheme, openshiftVersion)
 	if err != nil {
 		return s, err
@@ -86,7 +122,7 @@ func NewRBLNClusterPolicyScope(ctx context.Context, client client.Client, log lo
 	}
 	s.patcher = append(s.patcher, sdp)
 
-	ctp, err := patch.NewContainerToolkitPatcher(client, log, s.namespace, &clusterPolicy.Spec, scheme, openshiftVersion, containerRuntime)
+	ctp, err := patch.NewContainerToolkitPatcher(client, log, s.namespace, &clusterPolicy.Spec, scheme, openshiftVersion)
 	if err != nil {
 		return s, err
 	}
@@ -103,27 +139,56 @@ func NewRBLNClusterPolicyScope(ctx context.Context, client client.Client, log lo
 
 // PatchComponents patches all components managed by the scope
 func (s *RBLNClusterPolicyScope) PatchComponents(ctx context.Context) error {
+	if s.singleton.Spec.Paused {
+		s.log.Info("RBLNClusterPolicy is paused. Skip patching components")
+		return nil
+	}
+
+	// the bundle is materialized before the components mounting it
+	trustedCAName := trustedca.ConfigMapName(s.singleton.Spec.BaseName)
+	if err := trustedca.Sync(ctx, s.client, s.singleton, s.scheme, s.singleton.Spec.TrustedCA, s.namespace, trustedCAName, s.openshiftVersion); err != nil {
+		return err
+	}
+
 	for _, p := range s.patcher {
-		if p.IsEnabled() {
-			if err := p.Patch(ctx, s.singleton); err != nil {
-				return fmt.Errorf("failed to patch component: %v", err)
-			}
-		} else {
+		switch p.ManagementState() {
+		case rblnv1beta1.ManagementStateRemoved:
 			if err := p.CleanUp(ctx, s.singleton); err != nil {
 				return fmt.Errorf("failed to clean up component: %v", err)
 			}
+		case rblnv1beta1.ManagementStateUnmanaged:
+			s.log.Info("Component is unmanaged. Leave its resources untouched", "component", p.ComponentName())
+		default:
+			if err := p.Patch(ctx, s.singleton); err != nil {
+				return fmt.Errorf("failed to patch component: %v", err)
+			}
 		}
 	}
 	return nil
 }
 
+// Images returns the effective images of the components patched by the scope.
+func (s *RBLNClusterPolicyScope) Images() []rblnv1beta1.ContainerImage {
+	recorded := s.images.Images()
+	images := make([]rblnv1beta1.ContainerImage, 0, len(recorded))
+	for _, image := range recorded {
+		images = append(images, rblnv1beta1.ContainerImage{
+			Workload:  image.Workload,
+			Container: image.Container,
+			Image:     image.Image,
+		})
+	}
+	return images
+}
+
 func (s *RBLNClusterPolicyScope) AssembleComponentConditions(ctx context.Context) []rblnv1beta1.RBLNComponentStatus {
 	componentsStatus := make([]rblnv1beta1.RBLNComponentStatus, 0, len(s.patcher))
 	for _, p := range s.patcher {
-		if p.IsEnabled() {
+		if state := p.ManagementState(); state != rblnv1beta1.ManagementStateRemoved {
 			componentStatus := rblnv1beta1.RBLNComponentStatus{
-				Name:      p.ComponentName(),
-				Namespace: p.ComponentNamespace(),
+				Name:            p.ComponentName(),
+				Namespace:       p.ComponentNamespace(),
+				ManagementState: state,
 			}
 			conditions, err := p.ConditionReport(ctx, s.singleton)
 			componentStatus.Condition = conditions
@@ -147,10 +212,22 @@ func (s *RBLNClusterPolicyScope) LabelRblnNodes() (bool, int, error) {
 		return false, 0, fmt.Errorf("failed to list nodes: %s", err.Error())
 	}
 
+	// the product labels are informational, so failing to read the devices does not block labelling
+	devices, _, err := nodefeature.Devices(ctx, s.client)
+	if err != nil {
+		s.log.Error(err, "WARNING: failed to read the RBLN devices of the nodes; skip product labels")
+	}
+
+	// a paused policy still counts the nodes but leaves their labels and taints untouched
+	paused := s.singleton.Spec.Paused
+	if paused {
+		s.log.Info("RBLNClusterPolicy is paused. Skip labeling and tainting nodes")
+	}
+
 	nfdInstalled := false
 	rblnNodeCnt := 0
-	updateLabels := false
 	for _, node := range nodeList.Items {
+		updateLabels := false
 		labels := node.GetLabels()
 		if !nfdInstalled && hasNFDLabels(labels) {
 			nfdInstalled = true
@@ -165,6 +242,8 @@ func (s *RBLNClusterPolicyScope) LabelRblnNodes() (bool, int, error) {
 			s.log.Info("Rebellions device removed. Disable RBLN Present Label", "Node", node.Name)
 			labels[consts.RBLNPresentLabelKey] = "false"
 			removeAllRBLNComponentLabels(labels)
+			nodefeature.RemoveLabels(labels)
+			delete(labels, consts.RBLNContainerRuntimeLabelKey)
 			node.SetLabels(labels)
 			updateLabels = true
 		}
@@ -178,9 +257,28 @@ func (s *RBLNClusterPolicyScope) LabelRblnNodes() (bool, int, error) {
 				node.SetLabels(labels)
 				updateLabels = true
 			}
+			if updateContainerRuntimeLabel(labels, node, s.containerRuntime) {
+				node.SetLabels(labels)
+				updateLabels = true
+			}
+			// label the products only once node-feature-discovery published the devices of the node
+			if nodeDevices, ok := devices[node.Name]; ok && nodefeature.UpdateLabels(labels, nodefeature.Labels(nodeDevices)) {
+				node.SetLabels(labels)
+				updateLabels = true
+			}
 			rblnNodeCnt++
 		}
-		if updateLabels {
+		// dedicate the node to NPU workloads while it has RBLN devices
+		tainted := hasRBLNPresentLabel(labels) && s.singleton.Spec.IsNodeTaintEnabled()
+		updateTaints := nodetaint.RemoveTaint
+		if tainted {
+			updateTaints = nodetaint.SetTaint
+		}
+		if updateTaints(&node) {
+			s.log.Info("Update NPU node taint", "Node", node.Name, "tainted", tainted)
+			updateLabels = true
+		}
+		if updateLabels && !paused {
 			if err := s.client.Update(ctx, &node); err != nil {
 				return nfdInstalled, 0, fmt.Errorf("failed to label node %s, err: %s", node.Name, err.Error())
 			}
@@ -0,0 +1,78 @@
+package scope
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+)
+
+// instanceTypeLabelKey is set to k3s or rke2 by the embedded cloud provider of those distributions.
+const instanceTypeLabelKey = "node.kubernetes.io/instance-type"
+
+// detectContainerRuntime returns the container runtime label value of a node.
+// fallback is used when the runtime version reported by the kubelet is not recognized.
+func detectContainerRuntime(node corev1.Node, fallback string) string {
+	runtimeVer := node.Status.NodeInfo.ContainerRuntimeVersion
+	switch {
+	case strings.HasPrefix(runtimeVer, "docker"):
+		return consts.Docker
+	case strings.HasPrefix(runtimeVer, "cri-o"):
+		return consts.CRIO
+	case strings.HasPrefix(runtimeVer, "containerd"):
+		runtime := detectKubernetesDistribution(node)
+		if containerdMajorVersion(runtimeVer) >= 2 {
+			runtime += consts.ContainerdV2Suffix
+		}
+		return runtime
+	default:
+		return fallback
+	}
+}
+
+// detectKubernetesDistribution returns the containerd flavor shipped by the node's distribution.
+func detectKubernetesDistribution(node corev1.Node) string {
+	switch node.GetLabels()[instanceTypeLabelKey] {
+	case consts.K3s:
+		return consts.K3s
+	case consts.RKE2:
+		return consts.RKE2
+	}
+
+	kubeletVer := node.Status.NodeInfo.KubeletVersion
+	switch {
+	case strings.Contains(kubeletVer, "+"+consts.RKE2):
+		return consts.RKE2
+	case strings.Contains(kubeletVer, "+"+consts.K3s):
+		return consts.K3s
+	default:
+		return consts.Containerd
+	}
+}
+
+// containerdMajorVersion parses versions such as containerd://1.7.22 or containerd://2.0.0-k3s1.
+func containerdMajorVersion(runtimeVer string) int {
+	_, version, found := strings.Cut(runtimeVer, "://")
+	if !found {
+		return 0
+	}
+	version = strings.TrimPrefix(version, "v")
+	major, _, _ := strings.Cut(version, ".")
+	v, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// updateContainerRuntimeLabel sets the detected container runtime label and reports whether it changed.
+func updateContainerRuntimeLabel(labels map[string]string, node corev1.Node, fallback string) bool {
+	runtime := detectContainerRuntime(node, fallback)
+	if labels[consts.RBLNContainerRuntimeLabelKey] == runtime {
+		return false
+	}
+	labels[consts.RBLNContainerRuntimeLabelKey] = runtime
+	return true
+}
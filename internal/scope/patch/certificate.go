@@ -0,0 +1,128 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+const (
+	tlsCertKey = "tls.crt"
+	tlsKeyKey  = "tls.key"
+	tlsCAKey   = "ca.crt"
+)
+
+var certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// certificateRequest describes a cert-manager Certificate managed on behalf of a component.
+type certificateRequest struct {
+	name       string
+	namespace  string
+	secretName string
+	commonName string
+	dnsNames   []string
+	usages     []string
+}
+
+// tlsSecretName returns the Secret holding the certificate described by spec, or fallback when
+// the Secret is generated (by cert-manager) and no explicit name was given.
+func tlsSecretName(spec *rblnv1beta1.TLSCertificateSpec, fallback string) string {
+	if spec == nil {
+		return ""
+	}
+	if spec.SecretName != "" {
+		return spec.SecretName
+	}
+	if spec.CertManager != nil {
+		return fallback
+	}
+	return ""
+}
+
+// reconcileCertificate creates or updates a cert-manager Certificate for the given issuer.
+func reconcileCertificate(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner metav1.Object, issuer *rblnv1beta1.CertManagerIssuerSpec, req certificateRequest) (controllerutil.OperationResult, error) {
+	available, err := isKindAvailable(c, certificateGVK)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	if !available {
+		return controllerutil.OperationResultNone, fmt.Errorf("cert-manager is requested for %s/%s but the Certificate CRD is not installed", req.namespace, req.name)
+	}
+
+	issuerKind := issuer.IssuerKind
+	if issuerKind == "" {
+		issuerKind = "Issuer"
+	}
+	issuerGroup := issuer.IssuerGroup
+	if issuerGroup == "" {
+		issuerGroup = certificateGVK.Group
+	}
+
+	dnsNames := make([]interface{}, 0, len(req.dnsNames))
+	for _, dnsName := range req.dnsNames {
+		dnsNames = append(dnsNames, dnsName)
+	}
+	usages := make([]interface{}, 0, len(req.usages))
+	for _, usage := range req.usages {
+		usages = append(usages, usage)
+	}
+	spec := map[string]interface{}{
+		"secretName": req.secretName,
+		"dnsNames":   dnsNames,
+		"usages":     usages,
+		"issuerRef": map[string]interface{}{
+			"name":  issuer.IssuerName,
+			"kind":  issuerKind,
+			"group": issuerGroup,
+		},
+	}
+	if req.commonName != "" {
+		spec["commonName"] = req.commonName
+	}
+	if issuer.Duration != "" {
+		spec["duration"] = issuer.Duration
+	}
+
+	builder := k8sutil.NewUnstructuredBuilder(certificateGVK, req.name, req.namespace)
+	cert := builder.Build()
+	return controllerutil.CreateOrPatch(ctx, c, cert, func() error {
+		cert = builder.
+			WithSpec(spec).
+			WithOwner(owner, scheme).
+			Build()
+		return nil
+	})
+}
+
+// deleteIfKindAvailable deletes the named object of an optional kind, ignoring it when the CRD is not installed.
+func deleteIfKindAvailable(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, name, namespace string) error {
+	available, err := isKindAvailable(c, gvk)
+	if err != nil || !available {
+		return err
+	}
+
+	// look the object up first so that reconciles with the feature disabled do not issue deletes
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, obj); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := c.Delete(ctx, obj); err != nil && !kapierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -8,6 +8,15 @@ const (
 	DaemonSetPodsNotReady = "DaemonSetAllPodsNotReady"
 	DaemonSetAllPodsReady = "DaemonSetAllPodsReady"
 
+	NodePoolsResolved    = "NodePoolsResolved"
+	ImageDigestMissing   = "ImageDigestMissing"
+	AllNodePoolsResolved = "AllNodePoolsResolved"
+
+	PrecompiledImageMissing = "PrecompiledImageMissing"
+	PrecompiledImagesFound  = "PrecompiledImagesFound"
+
+	DriverToolkitImageMissing = "DriverToolkitImageMissing"
+
 	hostUsrBinVolumeName = "host-usr-bin"
 	hostUsrBinPath       = "/usr/bin"
 
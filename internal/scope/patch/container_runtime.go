@@ -0,0 +1,218 @@
+package patch
+
+import (
+	"context"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+)
+
+const (
+	containerToolkitDeployLabelKey = "rebellions.ai/npu.deploy.container-toolkit"
+
+	containerRuntimeSockVolumeName   = "runtime-sock"
+	containerRuntimeConfigVolumeName = "runtime-config"
+	containerdSockPath               = "/run/containerd/containerd.sock"
+	k3sContainerdSockPath            = "/run/k3s/containerd/containerd.sock"
+	dockerSockPath                   = "/var/run/docker.sock"
+	crioSockPath                     = "/var/run/crio/crio.sock"
+)
+
+// Environment variables of rbln-ctk-daemon, which reads every setting from an RBLN_CTK_DAEMON_
+// prefixed variable. The driver validation publishes the driver settings the same way.
+const (
+	// containerToolkitRuntimeEnv is the runtime to configure: containerd, docker or crio
+	containerToolkitRuntimeEnv = "RBLN_CTK_DAEMON_RUNTIME"
+	// containerToolkitSocketEnv is the socket the runtime is restarted through
+	containerToolkitSocketEnv = "RBLN_CTK_DAEMON_SOCKET"
+	// containerToolkitConfigEnv is the runtime config file the RBLN runtime is added to.
+	// k3s and RKE2 render containerd's config from a .tmpl file, so the template is edited there.
+	containerToolkitConfigEnv = "RBLN_CTK_DAEMON_CONFIG"
+	// containerToolkitRuntimeHandlerEnv names the runtime handler the RBLN runtime is registered as
+	containerToolkitRuntimeHandlerEnv = "RBLN_CTK_DAEMON_RUNTIME_HANDLER"
+	// containerToolkitSetAsDefaultEnv is false when workloads select the handler through a RuntimeClass
+	containerToolkitSetAsDefaultEnv = "RBLN_CTK_DAEMON_SET_AS_DEFAULT"
+)
+
+// containerRuntimeProfile describes how the container toolkit reaches and configures a runtime.
+// containerd 2 only differs where the distribution keeps a separate template for its config
+// schema; the schema version itself is read from the config by rbln-ctk-daemon.
+type containerRuntimeProfile struct {
+	runtime    string
+	socketPath string
+	configPath string
+}
+
+var containerRuntimeProfiles = map[string]containerRuntimeProfile{
+	consts.Containerd: {
+		runtime:    consts.Containerd,
+		socketPath: containerdSockPath,
+		configPath: "/etc/containerd/config.toml",
+	},
+	consts.Containerd + consts.ContainerdV2Suffix: {
+		runtime:    consts.Containerd,
+		socketPath: containerdSockPath,
+		configPath: "/etc/containerd/config.toml",
+	},
+	consts.K3s: {
+		runtime:    consts.Containerd,
+		socketPath: k3sContainerdSockPath,
+		configPath: "/var/lib/rancher/k3s/agent/etc/containerd/config.toml.tmpl",
+	},
+	consts.K3s + consts.ContainerdV2Suffix: {
+		runtime:    consts.Containerd,
+		socketPath: k3sContainerdSockPath,
+		configPath: "/var/lib/rancher/k3s/agent/etc/containerd/config-v3.toml.tmpl",
+	},
+	consts.RKE2: {
+		runtime:    consts.Containerd,
+		socketPath: k3sContainerdSockPath,
+		configPath: "/var/lib/rancher/rke2/agent/etc/containerd/config.toml.tmpl",
+	},
+	consts.RKE2 + consts.ContainerdV2Suffix: {
+		runtime:    consts.Containerd,
+		socketPath: k3sContainerdSockPath,
+		configPath: "/var/lib/rancher/rke2/agent/etc/containerd/config-v3.toml.tmpl",
+	},
+	consts.Docker: {
+		runtime:    consts.Docker,
+		socketPath: dockerSockPath,
+		configPath: "/etc/docker/daemon.json",
+	},
+	consts.CRIO: {
+		runtime:    consts.CRIO,
+		socketPath: crioSockPath,
+		configPath: "/etc/crio/crio.conf",
+	},
+}
+
+// runtimePool is a group of container toolkit nodes sharing the same container runtime.
+type runtimePool struct {
+	name         string
+	profile      containerRuntimeProfile
+	nodeSelector map[string]string
+}
+
+// getRuntimePools partitions container toolkit nodes per detected container runtime.
+func getRuntimePools(ctx context.Context, k8sClient client.Client, spec *rblnv1beta1.RBLNContainerToolkitSpec) ([]runtimePool, error) {
+	logger := log.FromContext(ctx)
+
+	nodeList := &corev1.NodeList{}
+	if err := k8sClient.List(ctx, nodeList, client.MatchingLabels{containerToolkitDeployLabelKey: "true"}); err != nil {
+		logger.Error(err, "failed to list nodes")
+		return nil, err
+	}
+
+	poolMap := make(map[string]runtimePool)
+	for _, node := range nodeList.Items {
+		detected, ok := node.GetLabels()[consts.RBLNContainerRuntimeLabelKey]
+		if !ok {
+			logger.Info("WARNING: Could not find container runtime label for node", "Node", node.Name, "Label", consts.RBLNContainerRuntimeLabelKey)
+			continue
+		}
+		if _, exists := poolMap[detected]; exists {
+			continue
+		}
+		profile, ok := resolveRuntimeProfile(detected, spec)
+		if !ok {
+			logger.Info("WARNING: Unsupported container runtime for node", "Node", node.Name, "Runtime", detected)
+			continue
+		}
+		logger.Info("Detected new container runtime pool", "Runtime", detected)
+		poolMap[detected] = runtimePool{
+			name:    detected,
+			profile: profile,
+			nodeSelector: map[string]string{
+				containerToolkitDeployLabelKey:      "true",
+				consts.RBLNContainerRuntimeLabelKey: detected,
+			},
+		}
+	}
+
+	pools := make([]runtimePool, 0, len(poolMap))
+	for _, name := range slices.Sorted(maps.Keys(poolMap)) {
+		pools = append(pools, poolMap[name])
+	}
+	return pools, nil
+}
+
+// resolveRuntimeProfile returns the runtime profile of a detected runtime after applying the spec overrides.
+func resolveRuntimeProfile(detected string, spec *rblnv1beta1.RBLNContainerToolkitSpec) (containerRuntimeProfile, bool) {
+	key := detected
+	if spec.Runtime != "" {
+		key = spec.Runtime
+		if strings.HasSuffix(detected, consts.ContainerdV2Suffix) {
+			key += consts.ContainerdV2Suffix
+		}
+	}
+
+	profile, ok := containerRuntimeProfiles[key]
+	if !ok {
+		// docker and cri-o do not have a containerd 2.x variant
+		profile, ok = containerRuntimeProfiles[strings.TrimSuffix(key, consts.ContainerdV2Suffix)]
+		if !ok {
+			return containerRuntimeProfile{}, false
+		}
+	}
+
+	if spec.SocketPath != "" {
+		profile.socketPath = spec.SocketPath
+	}
+	if spec.ConfigPath != "" {
+		profile.configPath = spec.ConfigPath
+	}
+	return profile, true
+}
+
+// env returns the environment variables configuring rbln-ctk-daemon for the runtime.
+func (p containerRuntimeProfile) env() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: containerToolkitRuntimeEnv, Value: p.runtime},
+		{Name: containerToolkitSocketEnv, Value: p.socketPath},
+		{Name: containerToolkitConfigEnv, Value: p.configPath},
+	}
+}
+
+// volumes returns the host runtime socket and config directory, mounted at their host paths.
+func (p containerRuntimeProfile) volumes() ([]corev1.Volume, []corev1.VolumeMount) {
+	configDir := filepath.Dir(p.configPath)
+	volumes := []corev1.Volume{
+		{
+			Name: containerRuntimeSockVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: p.socketPath,
+					Type: ptr(corev1.HostPathSocket),
+				},
+			},
+		},
+		{
+			Name: containerRuntimeConfigVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: configDir,
+					Type: ptr(corev1.HostPathDirectoryOrCreate),
+				},
+			},
+		},
+	}
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      containerRuntimeSockVolumeName,
+			MountPath: p.socketPath,
+		},
+		{
+			Name:      containerRuntimeConfigVolumeName,
+			MountPath: configDir,
+		},
+	}
+	return volumes, mounts
+}
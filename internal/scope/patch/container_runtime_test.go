@@ -0,0 +1,90 @@
+package patch
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+)
+
+var _ = Describe("ContainerRuntime", func() {
+	Describe("resolveRuntimeProfile", func() {
+		It("should use the k3s socket and config-v3 template for k3s with containerd 2", func() {
+			profile, ok := resolveRuntimeProfile("k3s-v2", &rblnv1beta1.RBLNContainerToolkitSpec{})
+			Expect(ok).To(BeTrue())
+			Expect(profile.runtime).To(Equal(consts.Containerd))
+			Expect(profile.socketPath).To(Equal("/run/k3s/containerd/containerd.sock"))
+			Expect(profile.configPath).To(HaveSuffix("config-v3.toml.tmpl"))
+			Expect(profile.env()).To(Equal([]corev1.EnvVar{
+				{Name: "RBLN_CTK_DAEMON_RUNTIME", Value: "containerd"},
+				{Name: "RBLN_CTK_DAEMON_SOCKET", Value: "/run/k3s/containerd/containerd.sock"},
+				{Name: "RBLN_CTK_DAEMON_CONFIG", Value: "/var/lib/rancher/k3s/agent/etc/containerd/config-v3.toml.tmpl"},
+			}))
+		})
+
+		It("should keep the detected containerd major version when the runtime is overridden", func() {
+			profile, ok := resolveRuntimeProfile("containerd-v2", &rblnv1beta1.RBLNContainerToolkitSpec{Runtime: consts.RKE2})
+			Expect(ok).To(BeTrue())
+			Expect(profile.configPath).To(Equal("/var/lib/rancher/rke2/agent/etc/containerd/config-v3.toml.tmpl"))
+
+			profile, ok = resolveRuntimeProfile("containerd-v2", &rblnv1beta1.RBLNContainerToolkitSpec{Runtime: consts.CRIO})
+			Expect(ok).To(BeTrue())
+			Expect(profile.runtime).To(Equal(consts.CRIO))
+			Expect(profile.configPath).To(Equal("/etc/crio/crio.conf"))
+		})
+
+		It("should apply socket and config path overrides", func() {
+			profile, ok := resolveRuntimeProfile(consts.Containerd, &rblnv1beta1.RBLNContainerToolkitSpec{
+				SocketPath: "/var/run/custom/containerd.sock",
+				ConfigPath: "/opt/containerd/config.toml",
+			})
+			Expect(ok).To(BeTrue())
+			volumes, mounts := profile.volumes()
+			Expect(volumes).To(ContainElement(HaveField("VolumeSource.HostPath.Path", "/var/run/custom/containerd.sock")))
+			Expect(mounts).To(ContainElement(HaveField("MountPath", "/opt/containerd")))
+		})
+
+		It("should reject unknown runtimes", func() {
+			_, ok := resolveRuntimeProfile("podman", &rblnv1beta1.RBLNContainerToolkitSpec{})
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("getRuntimePools", func() {
+		It("should create one pool per detected runtime", func() {
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+			node := func(name, runtime string) *corev1.Node {
+				labels := map[string]string{containerToolkitDeployLabelKey: "true"}
+				if runtime != "" {
+					labels[consts.RBLNContainerRuntimeLabelKey] = runtime
+				}
+				return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+			}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(
+					node("node-a", "k3s"),
+					node("node-b", "containerd-v2"),
+					node("node-c", "k3s"),
+					node("node-d", ""),
+				).
+				Build()
+
+			pools, err := getRuntimePools(context.Background(), c, &rblnv1beta1.RBLNContainerToolkitSpec{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pools).To(HaveLen(2))
+			Expect(pools[0].name).To(Equal("containerd-v2"))
+			Expect(pools[1].name).To(Equal("k3s"))
+			Expect(pools[1].nodeSelector).To(HaveKeyWithValue(consts.RBLNContainerRuntimeLabelKey, "k3s"))
+		})
+	})
+})
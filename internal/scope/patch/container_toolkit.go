@@ -13,7 +13,6 @@ import (
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -30,12 +29,7 @@ const (
 	containerToolkitHostBinVolumeName = "host-bin"
 	containerToolkitHostBinPath       = "/usr/local/bin"
 	containerToolkitHostBinMountPath  = "/host/usr/local/bin"
-	containerdSockVolumeName          = "containerd-sock"
-	containerdSockPath                = "/run/containerd/containerd.sock"
-	dockerSockVolumeName              = "docker-sock"
-	dockerSockPath                    = "/var/run/docker.sock"
-	crioSockVolumeName                = "crio-sock"
-	crioSockPath                      = "/var/run/crio/crio.sock"
+	containerToolkitPoolLabelKey      = "nodepool"
 	containerToolkitEntrypointKey     = "entrypoint.sh"
 	containerToolkitEntrypointPath    = "/bin/entrypoint.sh"
 )
@@ -49,19 +43,22 @@ type containerToolkitPatcher struct {
 	name             string
 	namespace        string
 	openshiftVersion string
-	containerRuntime string
+	// nodeTaint makes the pods of the RuntimeClass tolerate the NPU node taint
+	nodeTaint          bool
+	deviceListStrategy deviceListStrategy
 }
 
-func NewContainerToolkitPatcher(client client.Client, log logr.Logger, namespace string, cpSpec *rblnv1beta1.RBLNClusterPolicySpec, scheme *runtime.Scheme, openshiftVersion string, containerRuntime string) (Patcher, error) {
+func NewContainerToolkitPatcher(client client.Client, log logr.Logger, namespace string, cpSpec *rblnv1beta1.RBLNClusterPolicySpec, scheme *runtime.Scheme, openshiftVersion string) (Patcher, error) {
 	patcher := &containerToolkitPatcher{
 		client: client,
 		log:    log,
 		scheme: scheme,
 
-		name:             cpSpec.BaseName + "-" + consts.RBLNContainerToolkitName,
-		namespace:        namespace,
-		openshiftVersion: openshiftVersion,
-		containerRuntime: containerRuntime,
+		name:               cpSpec.BaseName + "-" + consts.RBLNContainerToolkitName,
+		namespace:          namespace,
+		openshiftVersion:   openshiftVersion,
+		nodeTaint:          cpSpec.IsNodeTaintEnabled(),
+		deviceListStrategy: newDeviceListStrategy(cpSpec),
 	}
 
 	synced := syncSpec(cpSpec, cpSpec.ContainerToolkit)
@@ -76,6 +73,10 @@ func (h *containerToolkitPatcher) IsEnabled() bool {
 	return h.desiredSpec.IsEnabled()
 }
 
+func (h *containerToolkitPatcher) ManagementState() rblnv1beta1.ManagementState {
+	return componentManagementState(h.desiredSpec)
+}
+
 func (h *containerToolkitPatcher) Patch(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	if !h.IsEnabled() {
 		return nil
@@ -93,22 +94,35 @@ func (h *containerToolkitPatcher) Patch(ctx context.Context, owner *rblnv1beta1.
 	if err := h.handleConfigMap(ctx, owner); err != nil {
 		return err
 	}
-	if err := h.handleDaemonSet(ctx, owner); err != nil {
+	if err := h.handleRuntimeClass(ctx, owner); err != nil {
 		return err
 	}
 
-	return nil
+	pools, err := getRuntimePools(ctx, h.client, h.desiredSpec)
+	if err != nil {
+		return err
+	}
+	if len(pools) == 0 {
+		h.log.Info("WARNING: no nodes with a detected container runtime for container toolkit; skipping daemonset reconcile")
+	}
+	poolDaemonSets := make(map[string]bool, len(pools))
+	for _, pool := range pools {
+		if err := h.handleDaemonSet(ctx, owner, pool); err != nil {
+			return err
+		}
+		poolDaemonSets[h.daemonSetName(pool)] = true
+	}
+
+	return h.deleteStaleDaemonSets(ctx, poolDaemonSets)
 }
 
 func (h *containerToolkitPatcher) CleanUp(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	h.log.Info("WARNING: Container Toolkit is disabled. Remove all Container Toolkit resources")
 
-	if err := h.client.Delete(ctx, &appsv1.DaemonSet{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      h.name,
-			Namespace: h.namespace,
-		},
-	}); err != nil && !kapierrors.IsNotFound(err) {
+	if err := h.deleteStaleDaemonSets(ctx, nil); err != nil {
+		return err
+	}
+	if err := h.deleteRuntimeClasses(ctx, ""); err != nil {
 		return err
 	}
 
@@ -149,39 +163,44 @@ func (h *containerToolkitPatcher) CleanUp(ctx context.Context, owner *rblnv1beta
 }
 
 func (h *containerToolkitPatcher) ConditionReport(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) ([]metav1.Condition, error) {
-	var ds appsv1.DaemonSet
-	if err := h.client.Get(ctx, types.NamespacedName{Name: h.name, Namespace: h.namespace}, &ds); err != nil {
+	dsList := &appsv1.DaemonSetList{}
+	if err := h.client.List(ctx, dsList, client.InNamespace(h.namespace), client.MatchingLabels{"app": h.name}); err != nil {
 		return []metav1.Condition{{
 			Type:               DaemonSetReady,
 			Status:             metav1.ConditionFalse,
 			Reason:             DaemonSetNotFound,
-			Message:            fmt.Sprintf("DaemonSet %s/%s could not be found: %v", h.namespace, h.name, err),
+			Message:            fmt.Sprintf("DaemonSet list could not be retrieved: %v", err),
+			LastTransitionTime: metav1.Now(),
+		}}, nil
+	}
+	if len(dsList.Items) == 0 {
+		return []metav1.Condition{{
+			Type:               DaemonSetReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             DaemonSetNotFound,
+			Message:            fmt.Sprintf("DaemonSet for %s/%s could not be found", h.namespace, h.name),
 			LastTransitionTime: metav1.Now(),
 		}}, nil
 	}
 
-	ready := ds.Status.DesiredNumberScheduled > 0 &&
-		ds.Status.NumberReady == ds.Status.DesiredNumberScheduled &&
-		ds.Status.NumberUnavailable == 0
-
-	observedGen := ds.GetGeneration()
-
-	if !ready {
+	notReady := make([]string, 0)
+	for _, ds := range dsList.Items {
+		ready := ds.Status.DesiredNumberScheduled > 0 &&
+			ds.Status.NumberReady == ds.Status.DesiredNumberScheduled &&
+			ds.Status.NumberUnavailable == 0
+		if ready {
+			continue
+		}
+		notReady = append(notReady, fmt.Sprintf("%s/%s (%d of %d pods are Ready)", ds.Namespace, ds.Name, ds.Status.NumberReady, ds.Status.DesiredNumberScheduled))
+	}
+	if len(notReady) > 0 {
 		return []metav1.Condition{
 			{
-				Type:   DaemonSetReady,
-				Status: metav1.ConditionFalse,
-				Reason: DaemonSetPodsNotReady,
-				Message: fmt.Sprintf(
-					"DaemonSet %s/%s is progressing: %d of %d pods are Ready (%d unavailable)",
-					h.namespace,
-					h.name,
-					ds.Status.NumberReady,
-					ds.Status.DesiredNumberScheduled,
-					ds.Status.NumberUnavailable,
-				),
+				Type:               DaemonSetReady,
+				Status:             metav1.ConditionFalse,
+				Reason:             DaemonSetPodsNotReady,
+				Message:            fmt.Sprintf("DaemonSets not ready: %s", strings.Join(notReady, ", ")),
 				LastTransitionTime: metav1.Now(),
-				ObservedGeneration: observedGen,
 			},
 		}, nil
 	}
@@ -191,9 +210,8 @@ func (h *containerToolkitPatcher) ConditionReport(ctx context.Context, owner *rb
 			Type:               DaemonSetReady,
 			Status:             metav1.ConditionTrue,
 			Reason:             DaemonSetAllPodsReady,
-			Message:            fmt.Sprintf("All pods in DaemonSet %s/%s are running", h.namespace, h.name),
+			Message:            fmt.Sprintf("All pods in DaemonSets for %s/%s are running", h.namespace, h.name),
 			LastTransitionTime: metav1.Now(),
-			ObservedGeneration: observedGen,
 		},
 	}, nil
 }
@@ -210,6 +228,29 @@ func (h *containerToolkitPatcher) entrypointConfigMapName() string {
 	return h.name + "-entrypoint"
 }
 
+func (h *containerToolkitPatcher) daemonSetName(pool runtimePool) string {
+	return h.name + "-" + pool.name
+}
+
+// deleteStaleDaemonSets removes container toolkit DaemonSets not listed in keep,
+// e.g. pools whose runtime is no longer present in the cluster.
+func (h *containerToolkitPatcher) deleteStaleDaemonSets(ctx context.Context, keep map[string]bool) error {
+	dsList := &appsv1.DaemonSetList{}
+	if err := h.client.List(ctx, dsList, client.InNamespace(h.namespace), client.MatchingLabels{"app": h.name}); err != nil {
+		return err
+	}
+	for _, ds := range dsList.Items {
+		if keep[ds.Name] {
+			continue
+		}
+		h.log.Info("Removing Container Toolkit DaemonSet", "namespace", ds.Namespace, "name", ds.Name)
+		if err := h.client.Delete(ctx, &ds); err != nil && !kapierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 func (h *containerToolkitPatcher) handleServiceAccount(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	builder := k8sutil.NewServiceAccountBuilder(h.name, h.namespace)
 	sa := builder.Build()
@@ -310,6 +351,7 @@ func (h *containerToolkitPatcher) handleConfigMap(ctx context.Context, owner *rb
 		"command -v rbln-ctk-daemon >/dev/null 2>&1 && rbln-ctk-daemon --version || true",
 		"echo \"driver-ready contents:\"",
 		"cat " + validationsMountPath + "/driver-ready || true",
+		"echo \"device list strategy: ${" + deviceListStrategyEnvName + ":-default}\"",
 		"",
 		"exec rbln-ctk-daemon \"$@\"",
 	}, "\n") + "\n"
@@ -331,8 +373,8 @@ func (h *containerToolkitPatcher) handleConfigMap(ctx context.Context, owner *rb
 	return nil
 }
 
-func (h *containerToolkitPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
-	builder := k8sutil.NewDaemonSetBuilder(h.name, h.namespace)
+func (h *containerToolkitPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy, pool runtimePool) error {
+	builder := k8sutil.NewDaemonSetBuilder(h.daemonSetName(pool), h.namespace)
 	ds := builder.Build()
 
 	validatorSpec := owner.Spec.Validator
@@ -510,56 +552,12 @@ func (h *containerToolkitPatcher) handleDaemonSet(ctx context.Context, owner *rb
 		},
 	}
 
-	switch h.containerRuntime {
-	case consts.Containerd:
-		runtimeEnv = append(runtimeEnv, corev1.EnvVar{Name: "RBLN_CTK_DAEMON_RUNTIME", Value: consts.Containerd})
-		runtimeEnv = append(runtimeEnv, corev1.EnvVar{Name: "RBLN_CTK_DAEMON_SOCKET", Value: containerdSockPath})
-		toolkitVolumeMounts = append(toolkitVolumeMounts, corev1.VolumeMount{
-			Name:      containerdSockVolumeName,
-			MountPath: containerdSockPath,
-		})
-		toolkitVolumes = append(toolkitVolumes, corev1.Volume{
-			Name: containerdSockVolumeName,
-			VolumeSource: corev1.VolumeSource{
-				HostPath: &corev1.HostPathVolumeSource{
-					Path: containerdSockPath,
-					Type: ptr(corev1.HostPathSocket),
-				},
-			},
-		})
-	case consts.Docker:
-		runtimeEnv = append(runtimeEnv, corev1.EnvVar{Name: "RBLN_CTK_DAEMON_RUNTIME", Value: consts.Docker})
-		runtimeEnv = append(runtimeEnv, corev1.EnvVar{Name: "RBLN_CTK_DAEMON_SOCKET", Value: dockerSockPath})
-		toolkitVolumeMounts = append(toolkitVolumeMounts, corev1.VolumeMount{
-			Name:      dockerSockVolumeName,
-			MountPath: dockerSockPath,
-		})
-		toolkitVolumes = append(toolkitVolumes, corev1.Volume{
-			Name: dockerSockVolumeName,
-			VolumeSource: corev1.VolumeSource{
-				HostPath: &corev1.HostPathVolumeSource{
-					Path: dockerSockPath,
-					Type: ptr(corev1.HostPathSocket),
-				},
-			},
-		})
-	case consts.CRIO:
-		runtimeEnv = append(runtimeEnv, corev1.EnvVar{Name: "RBLN_CTK_DAEMON_RUNTIME", Value: consts.CRIO})
-		runtimeEnv = append(runtimeEnv, corev1.EnvVar{Name: "RBLN_CTK_DAEMON_SOCKET", Value: crioSockPath})
-		toolkitVolumeMounts = append(toolkitVolumeMounts, corev1.VolumeMount{
-			Name:      crioSockVolumeName,
-			MountPath: crioSockPath,
-		})
-		toolkitVolumes = append(toolkitVolumes, corev1.Volume{
-			Name: crioSockVolumeName,
-			VolumeSource: corev1.VolumeSource{
-				HostPath: &corev1.HostPathVolumeSource{
-					Path: crioSockPath,
-					Type: ptr(corev1.HostPathSocket),
-				},
-			},
-		})
-	}
+	runtimeEnv = append(runtimeEnv, pool.profile.env()...)
+	runtimeEnv = append(runtimeEnv, h.runtimeHandlerEnv()...)
+	runtimeEnv = append(runtimeEnv, h.deviceListStrategy.env(deviceListStrategyEnvName)...)
+	runtimeVolumes, runtimeVolumeMounts := pool.profile.volumes()
+	toolkitVolumes = append(toolkitVolumes, runtimeVolumes...)
+	toolkitVolumeMounts = append(toolkitVolumeMounts, runtimeVolumeMounts...)
 
 	toolkitContainer := k8sutil.NewContainerBuilder().
 		WithName(h.name).
@@ -577,34 +575,38 @@ func (h *containerToolkitPatcher) handleDaemonSet(ctx context.Context, owner *rb
 		toolkitContainer.Args = slices.Clone(toolkitSpec.Args)
 	}
 
+	podSpec := k8sutil.NewPodSpecBuilder().
+		WithServiceAccountName(h.name).
+		WithNodeSelector(pool.nodeSelector).
+		WithAffinity(h.desiredSpec.Affinity).
+		WithTolerations(h.desiredSpec.Tolerations).
+		WithImagePullSecrets(h.desiredSpec.ImagePullSecrets).
+		WithPriorityClassName(h.desiredSpec.PriorityClassName).
+		WithHostPID(true).
+		WithVolumes(toolkitVolumes).
+		WithInitContainers([]*corev1.Container{driverInit}).
+		WithContainers([]*corev1.Container{toolkitContainer}).
+		Build()
+
 	dsRes, err := controllerutil.CreateOrPatch(ctx, h.client, ds, func() error {
 		ds = builder.
-			WithLabelSelectors(map[string]string{"app": h.name}).
+			WithLabelSelectors(map[string]string{
+				"app":                        h.name,
+				containerToolkitPoolLabelKey: pool.name,
+			}).
 			WithLabels(h.desiredSpec.Labels).
 			WithAnnotations(h.desiredSpec.Annotations).
-			WithPodSpec(k8sutil.NewPodSpecBuilder().
-				WithServiceAccountName(h.name).
-				WithNodeSelector(map[string]string{"rebellions.ai/npu.deploy.container-toolkit": "true"}).
-				WithAffinity(h.desiredSpec.Affinity).
-				WithTolerations(h.desiredSpec.Tolerations).
-				WithImagePullSecrets(h.desiredSpec.ImagePullSecrets).
-				WithPriorityClassName(h.desiredSpec.PriorityClassName).
-				WithHostPID(true).
-				WithVolumes(toolkitVolumes).
-				WithInitContainers([]*corev1.Container{driverInit}).
-				WithContainers([]*corev1.Container{toolkitContainer}).
-				Build(),
-			).
+			WithPodSpec(podSpec).
 			WithOwner(owner, h.scheme).
 			Build()
 		return nil
 	})
 	if err != nil {
-		h.log.Error(err, "Failed to reconcile Container Toolkit DaemonSet")
+		h.log.Error(err, "Failed to reconcile Container Toolkit DaemonSet", "runtime", pool.name)
 		return err
 	}
 
-	h.log.Info("Reconciled Container Toolkit DaemonSet", "namespace", ds.Namespace, "name", ds.Name, "result", dsRes)
+	h.log.Info("Reconciled Container Toolkit DaemonSet", "namespace", ds.Namespace, "name", ds.Name, "runtime", pool.name, "result", dsRes)
 	return nil
 }
 
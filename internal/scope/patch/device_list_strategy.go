@@ -0,0 +1,50 @@
+package patch
+
+import (
+	"slices"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+)
+
+// deviceListStrategyEnvName is read by the toolkit validation, which skips the CDI spec check
+// when the device plugins do not return CDI annotations. The container toolkit receives it too.
+const deviceListStrategyEnvName = "DEVICE_LIST_STRATEGY"
+
+// deviceListStrategy is the cluster-wide device injection mode. The device plugins implement it
+// with their --use-cdi flag, the validator and the container toolkit with deviceListStrategyEnvName.
+// An empty strategy keeps each component's default.
+type deviceListStrategy string
+
+func newDeviceListStrategy(cpSpec *rblnv1beta1.RBLNClusterPolicySpec) deviceListStrategy {
+	return deviceListStrategy(cpSpec.DeviceListStrategy)
+}
+
+func (s deviceListStrategy) isSet() bool {
+	return s != ""
+}
+
+func (s deviceListStrategy) usesCDI() bool {
+	return s == rblnv1beta1.DeviceListStrategyCDIAnnotations
+}
+
+// useCDIArg returns the sriovdp flag enabling CDI annotations in allocate responses.
+func (s deviceListStrategy) useCDIArg() string {
+	return "--use-cdi=" + strconv.FormatBool(s.usesCDI())
+}
+
+// pluginArgs returns the device plugin arguments, which replace the image's default arguments
+// and therefore restate them before the strategy flag.
+func (s deviceListStrategy) pluginArgs() []string {
+	return append(slices.Clone(devicePluginDefaultArgs), s.useCDIArg())
+}
+
+// env returns the strategy as an environment variable, or nil when unset.
+func (s deviceListStrategy) env(name string) []corev1.EnvVar {
+	if !s.isSet() {
+		return nil
+	}
+	return []corev1.EnvVar{{Name: name, Value: string(s)}}
+}
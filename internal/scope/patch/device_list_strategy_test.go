@@ -0,0 +1,30 @@
+package patch
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+)
+
+var _ = Describe("DeviceListStrategy", func() {
+	It("should keep component defaults when unset", func() {
+		strategy := newDeviceListStrategy(&rblnv1beta1.RBLNClusterPolicySpec{})
+		Expect(strategy.isSet()).To(BeFalse())
+		Expect(strategy.useCDIArg()).To(Equal("--use-cdi=false"))
+		Expect(strategy.pluginArgs()).To(Equal([]string{"-v=10", "--logtostderr", "--use-cdi=false"}))
+		Expect(strategy.env(deviceListStrategyEnvName)).To(BeEmpty())
+	})
+
+	DescribeTable("should enable CDI only for CDI strategies",
+		func(value string, cdi bool) {
+			strategy := newDeviceListStrategy(&rblnv1beta1.RBLNClusterPolicySpec{DeviceListStrategy: value})
+			Expect(strategy.usesCDI()).To(Equal(cdi))
+			Expect(strategy.pluginArgs()).To(HaveExactElements("-v=10", "--logtostderr", HavePrefix("--use-cdi=")))
+			Expect(strategy.env(deviceListStrategyEnvName)).To(ConsistOf(corev1.EnvVar{Name: deviceListStrategyEnvName, Value: value}))
+		},
+		Entry("cdi-annotations", rblnv1beta1.DeviceListStrategyCDIAnnotations, true),
+		Entry("envvar", rblnv1beta1.DeviceListStrategyEnvVar, false),
+	)
+})
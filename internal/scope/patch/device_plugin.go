@@ -28,15 +28,19 @@ const (
 	hostDriverUsrBinMountPath = "/host/driver/usr/bin"
 )
 
+// devicePluginDefaultArgs are the arguments the device plugin images run sriovdp with by default
+var devicePluginDefaultArgs = []string{"-v=10", "--logtostderr"}
+
 type devicePluginPatcher struct {
 	client client.Client
 	log    logr.Logger
 	scheme *runtime.Scheme
 
-	desiredSpec      *rblnv1beta1.RBLNDevicePluginSpec
-	name             string
-	namespace        string
-	openshiftVersion string
+	desiredSpec        *rblnv1beta1.RBLNDevicePluginSpec
+	name               string
+	namespace          string
+	openshiftVersion   string
+	deviceListStrategy deviceListStrategy
 }
 
 func NewDevicePluginPatcher(client client.Client, log logr.Logger, namespace string, cpSpec *rblnv1beta1.RBLNClusterPolicySpec, scheme *runtime.Scheme, openshiftVersion string) (Patcher, error) {
@@ -45,9 +49,10 @@ func NewDevicePluginPatcher(client client.Client, log logr.Logger, namespace str
 		log:    log,
 		scheme: scheme,
 
-		name:             cpSpec.BaseName + "-" + consts.RBLNDevicePluginName,
-		namespace:        namespace,
-		openshiftVersion: openshiftVersion,
+		name:               cpSpec.BaseName + "-" + consts.RBLNDevicePluginName,
+		namespace:          namespace,
+		openshiftVersion:   openshiftVersion,
+		deviceListStrategy: newDeviceListStrategy(cpSpec),
 	}
 
 	synced := syncSpec(cpSpec, cpSpec.DevicePlugin)
@@ -63,6 +68,10 @@ func (h *devicePluginPatcher) IsEnabled() bool {
 	return h.desiredSpec.IsEnabled()
 }
 
+func (h *devicePluginPatcher) ManagementState() rblnv1beta1.ManagementState {
+	return componentManagementState(h.desiredSpec)
+}
+
 func (h *devicePluginPatcher) Patch(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	if !h.desiredSpec.IsEnabled() {
 		return nil
@@ -353,6 +362,12 @@ func (h *devicePluginPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1
 	if validatorSpec.ImagePullPolicy == "" {
 		initContainer.ImagePullPolicy = corev1.PullIfNotPresent
 	}
+
+	// keep the image's default arguments unless a strategy is set
+	var pluginArgs []string
+	if h.deviceListStrategy.isSet() {
+		pluginArgs = h.deviceListStrategy.pluginArgs()
+	}
 	dsRes, err := controllerutil.CreateOrPatch(ctx, h.client, ds, func() error {
 		ds = builder.
 			WithLabelSelectors(map[string]string{"app": h.name}).
@@ -465,6 +480,7 @@ func (h *devicePluginPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1
 					k8sutil.NewContainerBuilder().
 						WithName(h.name).
 						WithImage(ComposeImageReference(h.desiredSpec.Registry, h.desiredSpec.Image), h.desiredSpec.Version, h.desiredSpec.ImagePullPolicy).
+						WithArgs(pluginArgs).
 						WithResources(h.desiredSpec.Resources, "250m", "40Mi").
 						WithVolumeMounts([]corev1.VolumeMount{
 							{
@@ -3,6 +3,7 @@ package patch
 import (
 	"testing"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -71,4 +72,21 @@ var _ = Describe("DevicePluginPatcher", func() {
 			})
 		})
 	})
+
+	Describe("ManagementState", func() {
+		DescribeTable("should follow the component spec",
+			func(enabled bool, state, expected rblnv1beta1.ManagementState) {
+				cpSpec := &rblnv1beta1.RBLNClusterPolicySpec{
+					DevicePlugin: rblnv1beta1.RBLNDevicePluginSpec{Enabled: enabled, ManagementState: state},
+				}
+				p, err := NewDevicePluginPatcher(nil, logr.Discard(), "rbln-system", cpSpec, nil, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p.ManagementState()).To(Equal(expected))
+			},
+			Entry("managed by default", true, rblnv1beta1.ManagementState(""), rblnv1beta1.ManagementStateManaged),
+			Entry("unmanaged", true, rblnv1beta1.ManagementStateUnmanaged, rblnv1beta1.ManagementStateUnmanaged),
+			Entry("removed", true, rblnv1beta1.ManagementStateRemoved, rblnv1beta1.ManagementStateRemoved),
+			Entry("removed when disabled", false, rblnv1beta1.ManagementStateUnmanaged, rblnv1beta1.ManagementStateRemoved),
+		)
+	})
 })
@@ -3,6 +3,7 @@ package patch
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sort"
 	"strings"
 
@@ -13,6 +14,7 @@ import (
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -44,6 +46,21 @@ const (
 	hostRootPath                              = "/"
 	hostDevVolumeName                         = "host-dev"
 	hostDevPath                               = "/dev"
+	moduleSigningVolumeName                   = "module-signing-key"
+	moduleSigningMountPath                    = "/opt/rebellions/module-signing"
+	moduleSigningKeyEnvName                   = "RBLN_MODULE_SIGNING_KEY"
+	moduleSigningCertEnvName                  = "RBLN_MODULE_SIGNING_CERT"
+	defaultModuleSigningPrivateKey            = "signing_key.priv"
+	defaultModuleSigningCertificate           = "signing_key.x509"
+	driverBuildEnvName                        = "RBLN_DRIVER_BUILD"
+	driverBuildSource                         = "source"
+	kernelVersionEnvName                      = "KERNEL_VERSION"
+	moduleCacheDirEnvName                     = "RBLN_MODULE_CACHE_DIR"
+	moduleCacheVolumeName                     = "module-cache"
+	moduleCacheMountPath                      = "/var/cache/rbln/modules"
+	moduleCacheHostPath                       = "/var/lib/rbln/modules"
+	hostKernelModulesVolumeName               = "host-lib-modules"
+	hostKernelSourcesVolumeName               = "host-usr-src"
 )
 
 type mountPathToVolumeSource map[string]corev1.VolumeSource
@@ -108,10 +125,28 @@ type driverManagerPatcher struct {
 	instanceName     string
 	namespace        string
 	openshiftVersion string
+	resolver         ImageResolver
+
+	// unresolvedPools maps the node pools skipped by Patch to the reason
+	unresolvedPools map[string]unresolvedPool
+	// missingImages maps the node pools whose driver image is not in the registry to the image
+	missingImages map[string]string
+}
+
+type unresolvedPool struct {
+	reason  string
+	message string
+}
+
+// ImageResolver reports whether an image exists in its registry. An error means the
+// registry could not be asked.
+type ImageResolver interface {
+	Exists(ctx context.Context, image string, pullSecrets []string) (bool, error)
 }
 
 type DriverPatcher interface {
 	IsEnabled() bool
+	ManagementState() rebellionsaiv1alpha1.ManagementState
 	Patch(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNDriver) error
 	CleanUp(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNDriver) error
 	ConditionReport(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNDriver) ([]metav1.Condition, error)
@@ -119,7 +154,9 @@ type DriverPatcher interface {
 	ComponentNamespace() string
 }
 
-func NewDriverManagerPatcher(client client.Client, log logr.Logger, namespace string, driver *rebellionsaiv1alpha1.RBLNDriver, scheme *runtime.Scheme, openshiftVersion string) (DriverPatcher, error) {
+// NewDriverManagerPatcher returns the driver manager patcher. When resolver is set, node pools
+// whose precompiled driver image does not exist are skipped instead of left in ImagePullBackOff.
+func NewDriverManagerPatcher(client client.Client, log logr.Logger, namespace string, driver *rebellionsaiv1alpha1.RBLNDriver, scheme *runtime.Scheme, openshiftVersion string, resolver ImageResolver) (DriverPatcher, error) {
 	if driver == nil {
 		return nil, fmt.Errorf("driver is nil")
 	}
@@ -132,6 +169,7 @@ func NewDriverManagerPatcher(client client.Client, log logr.Logger, namespace st
 		instanceName:     driver.Name,
 		namespace:        namespace,
 		openshiftVersion: openshiftVersion,
+		resolver:         resolver,
 	}, nil
 }
 
@@ -139,6 +177,13 @@ func (h *driverManagerPatcher) IsEnabled() bool {
 	return h.desiredSpec != nil
 }
 
+func (h *driverManagerPatcher) ManagementState() rebellionsaiv1alpha1.ManagementState {
+	if h.desiredSpec == nil {
+		return rebellionsaiv1alpha1.ManagementStateRemoved
+	}
+	return h.desiredSpec.GetManagementState()
+}
+
 func (h *driverManagerPatcher) Patch(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNDriver) error {
 	if !h.IsEnabled() {
 		return nil
@@ -164,8 +209,17 @@ func (h *driverManagerPatcher) Patch(ctx context.Context, owner *rebellionsaiv1a
 	if err := h.handleConfigMap(ctx); err != nil {
 		return err
 	}
+	if err := h.checkSigningKeySecret(ctx); err != nil {
+		return err
+	}
+
+	digests, err := h.poolDigests(ctx)
+	if err != nil {
+		return err
+	}
 
-	nodePools, err := getNodePools(ctx, h.client, h.desiredSpec.NodeSelector)
+	useDriverToolkit := h.useDriverToolkit()
+	nodePools, err := getNodePools(ctx, h.client, h.desiredSpec.NodeSelector, useDriverToolkit)
 	if err != nil {
 		return err
 	}
@@ -173,15 +227,93 @@ func (h *driverManagerPatcher) Patch(ctx context.Context, owner *rebellionsaiv1a
 		h.log.Info("WARNING: no nodes matching the given selector for driver manager; skipping daemonset reconcile", "instance", h.instanceName)
 		return nil
 	}
+	var dtkImages map[string]string
+	if useDriverToolkit {
+		if dtkImages, err = driverToolkitImages(ctx, h.client); err != nil {
+			return err
+		}
+		prestaged, err := h.prestagedRHCOSPools(ctx, nodePools, dtkImages)
+		if err != nil {
+			return err
+		}
+		nodePools = append(nodePools, prestaged...)
+	}
+
+	h.unresolvedPools = map[string]unresolvedPool{}
+	h.missingImages = map[string]string{}
+	deployed := map[string]bool{}
 	for _, nodePool := range nodePools {
-		if err := h.handleDaemonSet(ctx, owner, nodePool); err != nil {
+		if nodePool.rhcosVersion != "" && dtkImages[nodePool.rhcosVersion] == "" {
+			h.log.Info("WARNING: skipping node pool without a Driver Toolkit image", "nodePool", nodePool.name)
+			h.unresolvedPools[nodePool.name] = unresolvedPool{
+				reason:  DriverToolkitImageMissing,
+				message: fmt.Sprintf("no Driver Toolkit image for RHCOS %s in ImageStream %s/%s", nodePool.rhcosVersion, driverToolkitNamespace, driverToolkitImageStream),
+			}
+			continue
+		}
+		// a pool without a usable image must not block the other pools
+		build, err := h.driverBuild(nodePool, digests, dtkImages)
+		if err != nil && digests == nil {
+			return err
+		}
+		if err != nil {
+			h.log.Info("WARNING: skipping node pool without a driver image", "nodePool", nodePool.name, "reason", err.Error())
+			h.unresolvedPools[nodePool.name] = unresolvedPool{reason: ImageDigestMissing, message: err.Error()}
+			continue
+		}
+		if !h.driverImageExists(ctx, nodePool, build.image) {
+			h.missingImages[nodePool.name] = build.image
+			fallback, ok := h.sourceBuildFallback(ctx, nodePool, build, digests)
+			if !ok {
+				h.unresolvedPools[nodePool.name] = unresolvedPool{
+					reason:  PrecompiledImageMissing,
+					message: fmt.Sprintf("image %s not found", build.image),
+				}
+				continue
+			}
+			build = fallback
+		}
+		if err := h.handleDaemonSet(ctx, owner, nodePool, build); err != nil {
 			return err
 		}
+		deployed[nodePool.name] = true
 	}
 
+	return h.deleteStaleDaemonSets(ctx, deployed)
+}
+
+// deleteStaleDaemonSets deletes the DaemonSets of pools without nodes, e.g. of a kernel no node
+// runs anymore. DaemonSets of skipped pools are kept, since their pods may still serve nodes.
+func (h *driverManagerPatcher) deleteStaleDaemonSets(ctx context.Context, deployed map[string]bool) error {
+	dsList := &appsv1.DaemonSetList{}
+	if err := h.client.List(ctx, dsList, client.InNamespace(h.namespace), client.MatchingLabels(map[string]string{
+		driverManagerAppLabelKey:      h.name,
+		driverManagerInstanceLabelKey: h.instanceName,
+	})); err != nil {
+		return err
+	}
+	for i := range dsList.Items {
+		ds := &dsList.Items[i]
+		pool := ds.Labels[driverManagerNodePoolLabelKey]
+		if deployed[pool] {
+			continue
+		}
+		if _, skipped := h.unresolvedPools[pool]; skipped {
+			continue
+		}
+		h.log.Info("Deleting Driver Manager DaemonSet of a stale node pool", "namespace", ds.Namespace, "name", ds.Name, "nodePool", pool)
+		if err := h.client.Delete(ctx, ds); err != nil && !kapierrors.IsNotFound(err) {
+			return err
+		}
+	}
 	return nil
 }
 
+// useDriverToolkit reports whether RHCOS pools build the module with the OpenShift Driver Toolkit.
+func (h *driverManagerPatcher) useDriverToolkit() bool {
+	return h.openshiftVersion != "" && h.desiredSpec.IsDriverToolkitEnabled()
+}
+
 func (h *driverManagerPatcher) CleanUp(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNDriver) error {
 	h.log.Info("WARNING: Driver Manager is disabled. Remove all Driver Manager resources")
 	dsList := &appsv1.DaemonSetList{}
@@ -256,7 +388,77 @@ func (h *driverManagerPatcher) CleanUp(ctx context.Context, owner *rebellionsaiv
 	return nil
 }
 
-func (h *driverManagerPatcher) ConditionReport(ctx context.Context, _ *rebellionsaiv1alpha1.RBLNDriver) ([]metav1.Condition, error) {
+func (h *driverManagerPatcher) ConditionReport(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNDriver) ([]metav1.Condition, error) {
+	conds, err := h.daemonSetConditionReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// node pools are resolved by Patch, which is skipped for unmanaged drivers
+	if h.unresolvedPools == nil {
+		return conds, nil
+	}
+	if len(h.unresolvedPools) > 0 {
+		pools := make([]string, 0, len(h.unresolvedPools))
+		reasons := map[string]bool{}
+		for pool, unresolved := range h.unresolvedPools {
+			pools = append(pools, fmt.Sprintf("%s: %s", pool, unresolved.message))
+			reasons[unresolved.reason] = true
+		}
+		// report the reason that needs the user's attention first
+		reason := PrecompiledImageMissing
+		for _, r := range []string{ImageDigestMissing, DriverToolkitImageMissing} {
+			if reasons[r] {
+				reason = r
+				break
+			}
+		}
+		sort.Strings(pools)
+		conds = append(conds, metav1.Condition{
+			Type:               NodePoolsResolved,
+			Status:             metav1.ConditionFalse,
+			Reason:             reason,
+			Message:            fmt.Sprintf("Node pools skipped: %s", strings.Join(pools, "; ")),
+			LastTransitionTime: metav1.Now(),
+		})
+	} else if h.desiredSpec.Digests != nil || h.resolver != nil {
+		conds = append(conds, metav1.Condition{
+			Type:               NodePoolsResolved,
+			Status:             metav1.ConditionTrue,
+			Reason:             AllNodePoolsResolved,
+			Message:            "Driver images of all node pools are resolved",
+			LastTransitionTime: metav1.Now(),
+		})
+	}
+
+	if h.resolver == nil {
+		return conds, nil
+	}
+	if len(h.missingImages) > 0 {
+		pools := make([]string, 0, len(h.missingImages))
+		for pool, image := range h.missingImages {
+			pools = append(pools, fmt.Sprintf("%s (%s)", pool, image))
+		}
+		sort.Strings(pools)
+		conds = append(conds, metav1.Condition{
+			Type:               PrecompiledImageMissing,
+			Status:             metav1.ConditionTrue,
+			Reason:             PrecompiledImageMissing,
+			Message:            fmt.Sprintf("No precompiled driver image for node pools: %s", strings.Join(pools, ", ")),
+			LastTransitionTime: metav1.Now(),
+		})
+	} else {
+		conds = append(conds, metav1.Condition{
+			Type:               PrecompiledImageMissing,
+			Status:             metav1.ConditionFalse,
+			Reason:             PrecompiledImagesFound,
+			Message:            "Precompiled driver images exist for all node pools",
+			LastTransitionTime: metav1.Now(),
+		})
+	}
+	return conds, nil
+}
+
+func (h *driverManagerPatcher) daemonSetConditionReport(ctx context.Context) ([]metav1.Condition, error) {
 	dsList := &appsv1.DaemonSetList{}
 	if err := h.client.List(ctx, dsList, client.InNamespace(h.namespace), client.MatchingLabels(map[string]string{
 		driverManagerAppLabelKey:      h.name,
@@ -494,7 +696,7 @@ func (h *driverManagerPatcher) handleClusterRoleBinding(ctx context.Context) err
 	return nil
 }
 
-func (h *driverManagerPatcher) handleDaemonSet(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNDriver, pool nodePool) error {
+func (h *driverManagerPatcher) handleDaemonSet(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNDriver, pool nodePool, build driverBuild) error {
 	dsName := fmt.Sprintf("%s-%s", h.instanceName, pool.name)
 	builder := k8sutil.NewDaemonSetBuilder(dsName, h.namespace)
 	ds := builder.Build()
@@ -622,6 +824,91 @@ func (h *driverManagerPatcher) handleDaemonSet(ctx context.Context, owner *rebel
 			})
 		}
 	}
+	if secureBoot := h.desiredSpec.SecureBoot; secureBoot != nil {
+		privateKey, certificate := moduleSigningKeys(secureBoot)
+		// copy, the env is shared with the RBLNDriver spec
+		driverContainer.Env = append(append([]corev1.EnvVar{}, driverContainer.Env...),
+			corev1.EnvVar{Name: moduleSigningKeyEnvName, Value: moduleSigningMountPath + "/" + privateKey},
+			corev1.EnvVar{Name: moduleSigningCertEnvName, Value: moduleSigningMountPath + "/" + certificate},
+		)
+		additionalVolumeMounts = append(additionalVolumeMounts, corev1.VolumeMount{
+			Name:      moduleSigningVolumeName,
+			MountPath: moduleSigningMountPath,
+			ReadOnly:  true,
+		})
+		additionalVolumes = append(additionalVolumes, corev1.Volume{
+			Name: moduleSigningVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secureBoot.SigningKeySecret,
+					// only the key pair, other entries of the Secret stay out of the driver container
+					Items: []corev1.KeyToPath{
+						{Key: privateKey, Path: privateKey},
+						{Key: certificate, Path: certificate},
+					},
+					DefaultMode: ptr(int32(0o400)),
+				},
+			},
+		})
+	}
+	if build.source {
+		buildEnvs := []corev1.EnvVar{
+			{Name: driverBuildEnvName, Value: driverBuildSource},
+			{Name: moduleCacheDirEnvName, Value: moduleCacheMountPath},
+		}
+		// pools pre-staged for a cluster update have no node, hence no kernel, yet
+		if pool.kernel != "" {
+			buildEnvs = append(buildEnvs, corev1.EnvVar{Name: kernelVersionEnvName, Value: pool.kernel})
+		}
+		// copy, the env is shared with the RBLNDriver spec
+		driverContainer.Env = append(append([]corev1.EnvVar{}, driverContainer.Env...), buildEnvs...)
+		additionalVolumeMounts = append(additionalVolumeMounts,
+			corev1.VolumeMount{Name: moduleCacheVolumeName, MountPath: moduleCacheMountPath},
+		)
+		if build.toolkitImage != "" {
+			// RHCOS ships no kernel headers; the Driver Toolkit init container provides them
+			additionalVolumeMounts = append(additionalVolumeMounts,
+				corev1.VolumeMount{Name: driverToolkitKernelVolumeName, MountPath: "/host/lib/modules", SubPath: "lib/modules", ReadOnly: true},
+				corev1.VolumeMount{Name: driverToolkitKernelVolumeName, MountPath: "/host/usr/src", SubPath: "usr/src", ReadOnly: true},
+			)
+			additionalVolumes = append(additionalVolumes, corev1.Volume{
+				Name:         driverToolkitKernelVolumeName,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			})
+		} else {
+			additionalVolumeMounts = append(additionalVolumeMounts,
+				corev1.VolumeMount{Name: hostKernelModulesVolumeName, MountPath: "/host/lib/modules", ReadOnly: true},
+				corev1.VolumeMount{Name: hostKernelSourcesVolumeName, MountPath: "/host/usr/src", ReadOnly: true},
+			)
+			additionalVolumes = append(additionalVolumes,
+				corev1.Volume{
+					Name: hostKernelModulesVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{Path: "/lib/modules", Type: ptr(corev1.HostPathDirectory)},
+					},
+				},
+				corev1.Volume{
+					Name: hostKernelSourcesVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{Path: "/usr/src", Type: ptr(corev1.HostPathDirectoryOrCreate)},
+					},
+				},
+			)
+		}
+		additionalVolumes = append(additionalVolumes,
+			corev1.Volume{
+				Name: moduleCacheVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{
+						// keyed by kernel or RHCOS version and by driver, so a rebooted node never loads a module
+						// built for another kernel and a driver update never loads the module of the previous driver
+						Path: moduleCacheHostPath + "/" + pool.cacheKey() + "/" + build.cacheVersion(h.desiredSpec.Version),
+						Type: ptr(corev1.HostPathDirectoryOrCreate),
+					},
+				},
+			},
+		)
+	}
 	if len(additionalVolumeMounts) > 0 {
 		driverContainer.VolumeMounts = append(driverContainer.VolumeMounts, additionalVolumeMounts...)
 	}
@@ -635,13 +922,8 @@ func (h *driverManagerPatcher) handleDaemonSet(ctx context.Context, owner *rebel
 		PeriodSeconds:    driverManagerStartupProbePeriodSeconds,
 		FailureThreshold: driverManagerStartupProbeFailureThreshold,
 	}
-	driverSpec := *h.desiredSpec
-	driverImagePath, err := driverSpec.GetPrecompiledImagePath(pool.getOS(), pool.kernel)
-	if err != nil {
-		return err
-	}
-	driverContainer.Image = driverImagePath
-	driverTag := fmt.Sprintf("%s-%s-%s", driverSpec.Version, pool.kernel, pool.getOS())
+	driverContainer.Image = build.image
+	driverTag := fmt.Sprintf("%s-%s-%s", h.desiredSpec.Version, pool.kernel, pool.getOS())
 	driverPullPolicy := h.desiredSpec.ImagePullPolicy
 	if driverPullPolicy == "" {
 		driverPullPolicy = corev1.PullIfNotPresent
@@ -718,6 +1000,9 @@ func (h *driverManagerPatcher) handleDaemonSet(ctx context.Context, owner *rebel
 	if len(additionalVolumes) > 0 {
 		podSpec.Volumes = append(podSpec.Volumes, additionalVolumes...)
 	}
+	if build.toolkitImage != "" {
+		podSpec.InitContainers = append(podSpec.InitContainers, *newDriverToolkitContainer(build.toolkitImage))
+	}
 
 	dsRes, err := controllerutil.CreateOrPatch(ctx, h.client, ds, func() error {
 		ds = builder.
@@ -739,6 +1024,134 @@ func (h *driverManagerPatcher) handleDaemonSet(ctx context.Context, owner *rebel
 	return nil
 }
 
+// poolDigests returns the image digest of each node pool, or nil when digests are not pinned.
+func (h *driverManagerPatcher) poolDigests(ctx context.Context) (map[string]string, error) {
+	spec := h.desiredSpec.Digests
+	if spec == nil {
+		return nil, nil
+	}
+	digests := map[string]string{}
+	if spec.ConfigMap != "" {
+		cm := &corev1.ConfigMap{}
+		if err := h.client.Get(ctx, types.NamespacedName{Name: spec.ConfigMap, Namespace: h.namespace}, cm); err != nil {
+			return nil, fmt.Errorf("failed to get driver image digest configmap %s/%s: %w", h.namespace, spec.ConfigMap, err)
+		}
+		maps.Copy(digests, cm.Data)
+	}
+	maps.Copy(digests, spec.Pools)
+	return digests, nil
+}
+
+// driverBuild is the driver image of a node pool and whether it builds the module from source,
+// optionally with a Driver Toolkit image.
+type driverBuild struct {
+	image        string
+	source       bool
+	toolkitImage string
+}
+
+// cacheVersion identifies the driver a module is built from: the image digest when the image is
+// pinned, otherwise the driver version.
+func (b driverBuild) cacheVersion(version string) string {
+	if _, digest, ok := strings.Cut(b.image, "@"); ok {
+		return strings.ReplaceAll(digest, ":", "-")
+	}
+	return version
+}
+
+// driverBuild returns the driver image of a node pool, pinned by digest when digests are configured.
+func (h *driverManagerPatcher) driverBuild(pool nodePool, digests, dtkImages map[string]string) (driverBuild, error) {
+	build := driverBuild{source: !h.desiredSpec.IsPrecompiledEnabled()}
+	if build.source && !h.desiredSpec.IsSourceBuildSupported() {
+		return build, fmt.Errorf("precompiled: false requires sourceBuildContract")
+	}
+	if pool.rhcosVersion != "" {
+		build.source = true
+		build.toolkitImage = dtkImages[pool.rhcosVersion]
+	}
+	var err error
+	switch {
+	case h.desiredSpec.Digests != nil:
+		digest, ok := digests[pool.name]
+		if !ok {
+			return build, fmt.Errorf("no image digest for node pool %s", pool.name)
+		}
+		build.image, err = h.desiredSpec.GetPrecompiledImageDigestPath(digest)
+	case build.source:
+		build.image, err = h.desiredSpec.GetSourceImagePath(pool.getOS())
+	default:
+		build.image, err = h.desiredSpec.GetPrecompiledImagePath(pool.getOS(), pool.kernel)
+	}
+	return build, err
+}
+
+// sourceBuildFallback returns the source build of a pool whose precompiled image is missing.
+// Pools pinned by digest never fall back, as the source image is not pinned, and neither do
+// images without a source build contract.
+func (h *driverManagerPatcher) sourceBuildFallback(ctx context.Context, pool nodePool, build driverBuild, digests map[string]string) (driverBuild, bool) {
+	if build.source || digests != nil || !h.desiredSpec.IsSourceBuildSupported() {
+		return build, false
+	}
+	image, err := h.desiredSpec.GetSourceImagePath(pool.getOS())
+	if err != nil || !h.driverImageExists(ctx, pool, image) {
+		return build, false
+	}
+	h.log.Info("Building the driver from source for node pool without a precompiled image", "nodePool", pool.name, "image", image)
+	return driverBuild{image: image, source: true}, true
+}
+
+// driverImageExists checks the driver image of a node pool against the registry. The pool is
+// deployed when the registry cannot be asked, since the kubelet may still pull the image.
+func (h *driverManagerPatcher) driverImageExists(ctx context.Context, pool nodePool, image string) bool {
+	if h.resolver == nil {
+		return true
+	}
+	exists, err := h.resolver.Exists(ctx, image, h.desiredSpec.ImagePullSecrets)
+	if err != nil {
+		h.log.Info("WARNING: could not resolve the driver image; deploying the node pool anyway", "nodePool", pool.name, "image", image, "error", err.Error())
+		return true
+	}
+	if !exists {
+		h.log.Info("WARNING: driver image of node pool not found", "nodePool", pool.name, "image", image)
+	}
+	return exists
+}
+
+// checkSigningKeySecret fails early when the module signing key is missing, since the driver
+// pods would otherwise be stuck in ContainerCreating, and when the driver image is not declared
+// to sign the module with it.
+func (h *driverManagerPatcher) checkSigningKeySecret(ctx context.Context) error {
+	secureBoot := h.desiredSpec.SecureBoot
+	if secureBoot == nil {
+		return nil
+	}
+	if secureBoot.ComponentContract == "" {
+		return fmt.Errorf("module signing requires secureBoot.componentContract")
+	}
+	secret := &corev1.Secret{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: secureBoot.SigningKeySecret, Namespace: h.namespace}, secret); err != nil {
+		return fmt.Errorf("failed to get module signing key secret %s/%s: %w", h.namespace, secureBoot.SigningKeySecret, err)
+	}
+	privateKey, certificate := moduleSigningKeys(secureBoot)
+	for _, key := range []string{privateKey, certificate} {
+		if len(secret.Data[key]) == 0 {
+			return fmt.Errorf("module signing key secret %s/%s does not contain %s", h.namespace, secureBoot.SigningKeySecret, key)
+		}
+	}
+	return nil
+}
+
+func moduleSigningKeys(secureBoot *rebellionsaiv1alpha1.DriverSecureBootSpec) (string, string) {
+	privateKey, certificate := secureBoot.PrivateKey, secureBoot.Certificate
+	if privateKey == "" {
+		privateKey = defaultModuleSigningPrivateKey
+	}
+	if certificate == "" {
+		certificate = defaultModuleSigningCertificate
+	}
+	return privateKey, certificate
+}
+
 func (h *driverManagerPatcher) hasOtherDriverInstances(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNDriver) (bool, error) {
 	driverList := &rebellionsaiv1alpha1.RBLNDriverList{}
 	if err := h.client.List(ctx, driverList); err != nil {
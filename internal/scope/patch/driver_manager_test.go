@@ -0,0 +1,477 @@
+package patch
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/proxy"
+)
+
+var _ = Describe("DriverManagerPatcher", func() {
+	var (
+		owner  *rebellionsaiv1alpha1.RBLNDriver
+		node   *corev1.Node
+		scheme *runtime.Scheme
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(rebellionsaiv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		owner = &rebellionsaiv1alpha1.RBLNDriver{
+			Spec: rebellionsaiv1alpha1.RBLNDriverSpec{
+				Registry: "repo.rebellions.ai",
+				Image:    "rebellions/rbln-driver",
+				Version:  "3.0.0",
+				Env:      []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+			},
+		}
+		owner.SetName("rbln-driver")
+		owner.SetUID(types.UID("uid"))
+
+		node = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-1",
+				Labels: map[string]string{
+					driverManagerDeployLabelKey: "true",
+					nfdOSReleaseIDLabelKey:      "ubuntu",
+					nfdOSVersionIDLabelKey:      "22.04",
+					nfdKernelLabelKey:           "5.15.0-100-generic",
+				},
+			},
+		}
+	})
+
+	driverContainer := func(c client.Client) *corev1.Container {
+		list := &appsv1.DaemonSetList{}
+		Expect(c.List(context.Background(), list)).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+		podSpec := &list.Items[0].Spec.Template.Spec
+		for i := range podSpec.Containers {
+			if podSpec.Containers[i].Name == driverManagerContainer {
+				return &podSpec.Containers[i]
+			}
+		}
+		Fail("driver container not found")
+		return nil
+	}
+
+	Describe("Proxy", func() {
+		It("should inject the proxy without overriding the driver env", func() {
+			owner.Spec.Env = append(owner.Spec.Env, corev1.EnvVar{Name: "NO_PROXY", Value: "*"})
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+			proxyConfig := &proxy.Config{HTTPProxy: "http://proxy.corp:3128", NoProxy: ".svc"}
+			patcher, err := NewDriverManagerPatcher(proxy.NewClient(c, proxyConfig), logr.Discard(), "rbln-system", owner, scheme, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+			Expect(driverContainer(c).Env).To(ContainElements(
+				corev1.EnvVar{Name: "FOO", Value: "bar"},
+				corev1.EnvVar{Name: "NO_PROXY", Value: "*"},
+				corev1.EnvVar{Name: "HTTP_PROXY", Value: "http://proxy.corp:3128"},
+				corev1.EnvVar{Name: "http_proxy", Value: "http://proxy.corp:3128"},
+			))
+			Expect(driverContainer(c).Env).NotTo(ContainElement(corev1.EnvVar{Name: "NO_PROXY", Value: ".svc"}))
+			Expect(owner.Spec.Env).To(HaveLen(2))
+		})
+	})
+
+	Describe("SecureBoot", func() {
+		It("should mount the signing key into the driver container only", func() {
+			owner.Spec.SecureBoot = &rebellionsaiv1alpha1.DriverSecureBootSpec{
+				ComponentContract: rebellionsaiv1alpha1.DriverModuleSigningContractV1,
+				SigningKeySecret:  "module-signing",
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "module-signing", Namespace: "rbln-system"},
+				Data: map[string][]byte{
+					defaultModuleSigningPrivateKey:  []byte("key"),
+					defaultModuleSigningCertificate: []byte("cert"),
+					"unrelated":                     []byte("token"),
+				},
+			}).Build()
+			patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+			container := driverContainer(c)
+			Expect(container.VolumeMounts).To(ContainElement(And(
+				HaveField("Name", moduleSigningVolumeName),
+				HaveField("ReadOnly", true),
+			)))
+			Expect(container.Env).To(ContainElements(
+				corev1.EnvVar{Name: moduleSigningKeyEnvName, Value: moduleSigningMountPath + "/signing_key.priv"},
+				corev1.EnvVar{Name: moduleSigningCertEnvName, Value: moduleSigningMountPath + "/signing_key.x509"},
+			))
+			Expect(owner.Spec.Env).To(HaveLen(1))
+
+			list := &appsv1.DaemonSetList{}
+			Expect(c.List(context.Background(), list)).To(Succeed())
+			Expect(list.Items[0].Spec.Template.Spec.Volumes).To(ContainElement(And(
+				HaveField("Name", moduleSigningVolumeName),
+				HaveField("Secret.Items", ConsistOf(
+					corev1.KeyToPath{Key: defaultModuleSigningPrivateKey, Path: defaultModuleSigningPrivateKey},
+					corev1.KeyToPath{Key: defaultModuleSigningCertificate, Path: defaultModuleSigningCertificate},
+				)),
+			)))
+			for _, init := range list.Items[0].Spec.Template.Spec.InitContainers {
+				Expect(init.VolumeMounts).NotTo(ContainElement(HaveField("Name", moduleSigningVolumeName)))
+			}
+		})
+
+		It("should fail when the signing key secret is incomplete", func() {
+			owner.Spec.SecureBoot = &rebellionsaiv1alpha1.DriverSecureBootSpec{
+				ComponentContract: rebellionsaiv1alpha1.DriverModuleSigningContractV1,
+				SigningKeySecret:  "module-signing",
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "module-signing", Namespace: "rbln-system"},
+				Data:       map[string][]byte{defaultModuleSigningPrivateKey: []byte("key")},
+			}).Build()
+			patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(MatchError(ContainSubstring(defaultModuleSigningCertificate)))
+		})
+
+		It("should fail without a module signing contract", func() {
+			owner.Spec.SecureBoot = &rebellionsaiv1alpha1.DriverSecureBootSpec{SigningKeySecret: "module-signing"}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+			patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(MatchError(ContainSubstring("componentContract")))
+		})
+
+		It("should not mount a signing key by default", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+			patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+			Expect(driverContainer(c).VolumeMounts).NotTo(ContainElement(HaveField("Name", moduleSigningVolumeName)))
+		})
+	})
+
+	Describe("Digests", func() {
+		const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+		var otherNode *corev1.Node
+
+		BeforeEach(func() {
+			otherNode = node.DeepCopy()
+			otherNode.Name = "node-2"
+			otherNode.Labels[nfdKernelLabelKey] = "6.8.0-40-generic"
+		})
+
+		It("should pin the pool image by digest and skip pools without one", func() {
+			owner.Spec.Digests = &rebellionsaiv1alpha1.DriverDigestsSpec{ConfigMap: "driver-digests"}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, otherNode, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "driver-digests", Namespace: "rbln-system"},
+				Data:       map[string]string{"ubuntu22.04-5.15.0-100-generic": digest},
+			}).Build()
+			patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+			Expect(driverContainer(c).Image).To(Equal("repo.rebellions.ai/rebellions/rbln-driver@" + digest))
+			conds, err := patcher.ConditionReport(context.Background(), owner)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conds).To(ContainElement(And(
+				HaveField("Type", NodePoolsResolved),
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", ImageDigestMissing),
+				HaveField("Message", ContainSubstring("ubuntu22.04-6.8.0-40-generic")),
+			)))
+		})
+
+		It("should prefer the digests of the spec over the ConfigMap", func() {
+			owner.Spec.Digests = &rebellionsaiv1alpha1.DriverDigestsSpec{
+				Pools:     map[string]string{"ubuntu22.04-5.15.0-100-generic": digest},
+				ConfigMap: "driver-digests",
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "driver-digests", Namespace: "rbln-system"},
+				Data:       map[string]string{"ubuntu22.04-5.15.0-100-generic": "sha256:invalid"},
+			}).Build()
+			patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+			Expect(driverContainer(c).Image).To(HaveSuffix("@" + digest))
+			conds, err := patcher.ConditionReport(context.Background(), owner)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conds).To(ContainElement(And(
+				HaveField("Type", NodePoolsResolved),
+				HaveField("Status", metav1.ConditionTrue),
+			)))
+		})
+	})
+
+	Describe("ImagePreflight", func() {
+		It("should skip node pools without a precompiled driver image", func() {
+			otherNode := node.DeepCopy()
+			otherNode.Name = "node-2"
+			otherNode.Labels[nfdKernelLabelKey] = "6.8.0-40-generic"
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, otherNode).Build()
+			resolver := stubImageResolver{"repo.rebellions.ai/rebellions/rbln-driver:3.0.0-5.15.0-100-generic-ubuntu22.04": true}
+			patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "", resolver)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+			list := &appsv1.DaemonSetList{}
+			Expect(c.List(context.Background(), list)).To(Succeed())
+			Expect(list.Items).To(ConsistOf(HaveField("Name", "rbln-driver-ubuntu22.04-5.15.0-100-generic")))
+			conds, err := patcher.ConditionReport(context.Background(), owner)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conds).To(ContainElements(
+				And(
+					HaveField("Type", PrecompiledImageMissing),
+					HaveField("Status", metav1.ConditionTrue),
+					HaveField("Message", ContainSubstring("ubuntu22.04-6.8.0-40-generic")),
+				),
+				And(
+					HaveField("Type", NodePoolsResolved),
+					HaveField("Status", metav1.ConditionFalse),
+					HaveField("Reason", PrecompiledImageMissing),
+				),
+			))
+		})
+
+		It("should fall back to the source build when the source image exists", func() {
+			owner.Spec.SourceBuildContract = rebellionsaiv1alpha1.DriverSourceBuildContractV1
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+			resolver := stubImageResolver{"repo.rebellions.ai/rebellions/rbln-driver:3.0.0-ubuntu22.04": true}
+			patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "", resolver)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+			container := driverContainer(c)
+			Expect(container.Image).To(Equal("repo.rebellions.ai/rebellions/rbln-driver:3.0.0-ubuntu22.04"))
+			Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: driverBuildEnvName, Value: driverBuildSource}))
+			conds, err := patcher.ConditionReport(context.Background(), owner)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conds).To(ContainElements(
+				And(HaveField("Type", PrecompiledImageMissing), HaveField("Status", metav1.ConditionTrue)),
+				And(HaveField("Type", NodePoolsResolved), HaveField("Status", metav1.ConditionTrue)),
+			))
+		})
+	})
+
+	Describe("Source build", func() {
+		It("should not fall back to the source build without a source build contract", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+			resolver := stubImageResolver{"repo.rebellions.ai/rebellions/rbln-driver:3.0.0-ubuntu22.04": true}
+			patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "", resolver)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+			list := &appsv1.DaemonSetList{}
+			Expect(c.List(context.Background(), list)).To(Succeed())
+			Expect(list.Items).To(BeEmpty())
+		})
+
+		It("should refuse precompiled: false without a source build contract", func() {
+			owner.Spec.Precompiled = ptr(false)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+			patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(MatchError(ContainSubstring("sourceBuildContract")))
+		})
+
+		It("should build the module against the node kernel and cache it per kernel", func() {
+			owner.Spec.Precompiled = ptr(false)
+			owner.Spec.SourceBuildContract = rebellionsaiv1alpha1.DriverSourceBuildContractV1
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+			patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+			container := driverContainer(c)
+			Expect(container.Image).To(Equal("repo.rebellions.ai/rebellions/rbln-driver:3.0.0-ubuntu22.04"))
+			Expect(container.Env).To(ContainElements(
+				corev1.EnvVar{Name: driverBuildEnvName, Value: driverBuildSource},
+				corev1.EnvVar{Name: kernelVersionEnvName, Value: "5.15.0-100-generic"},
+			))
+			Expect(owner.Spec.Env).To(HaveLen(1))
+
+			list := &appsv1.DaemonSetList{}
+			Expect(c.List(context.Background(), list)).To(Succeed())
+			Expect(list.Items[0].Spec.Template.Spec.Volumes).To(ContainElement(And(
+				HaveField("Name", moduleCacheVolumeName),
+				HaveField("VolumeSource.HostPath.Path", moduleCacheHostPath+"/5.15.0-100-generic/3.0.0"),
+			)))
+		})
+	})
+})
+
+var _ = Describe("DriverToolkit", func() {
+	const (
+		currentRHCOS   = "414.92.202310170514-0"
+		nextRHCOS      = "415.92.202402201450-0"
+		olderNextRHCOS = "415.92.202401120930-0"
+		staleRHCOS     = "413.92.202305231734-0"
+	)
+
+	var (
+		owner  *rebellionsaiv1alpha1.RBLNDriver
+		scheme *runtime.Scheme
+		objs   []client.Object
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(rebellionsaiv1alpha1.AddToScheme(scheme)).To(Succeed())
+		for _, gvk := range []schema.GroupVersionKind{imageStreamGVK, clusterVersionGVK} {
+			scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+		}
+
+		owner = &rebellionsaiv1alpha1.RBLNDriver{
+			Spec: rebellionsaiv1alpha1.RBLNDriverSpec{
+				Registry:            "repo.rebellions.ai",
+				Image:               "rebellions/rbln-driver",
+				Version:             "3.0.0",
+				SourceBuildContract: rebellionsaiv1alpha1.DriverSourceBuildContractV1,
+			},
+		}
+		owner.SetName("rbln-driver")
+		owner.SetUID(types.UID("uid"))
+
+		imageStream := &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": driverToolkitImageStream, "namespace": driverToolkitNamespace},
+			"spec": map[string]interface{}{"tags": []interface{}{
+				map[string]interface{}{"name": "latest", "from": map[string]interface{}{"kind": "DockerImage", "name": "quay.io/dtk@sha256:next"}},
+				map[string]interface{}{"name": currentRHCOS, "from": map[string]interface{}{"kind": "DockerImage", "name": "quay.io/dtk@sha256:current"}},
+				map[string]interface{}{"name": nextRHCOS, "from": map[string]interface{}{"kind": "DockerImage", "name": "quay.io/dtk@sha256:next"}},
+				// kept from earlier releases
+				map[string]interface{}{"name": staleRHCOS, "from": map[string]interface{}{"kind": "DockerImage", "name": "quay.io/dtk@sha256:stale"}},
+				map[string]interface{}{"name": olderNextRHCOS, "from": map[string]interface{}{"kind": "DockerImage", "name": "quay.io/dtk@sha256:older"}},
+			}},
+		}}
+		imageStream.SetGroupVersionKind(imageStreamGVK)
+
+		objs = []client.Object{
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name: "node-1",
+				Labels: map[string]string{
+					driverManagerDeployLabelKey: "true",
+					nfdOSReleaseIDLabelKey:      "rhcos",
+					nfdOSVersionIDLabelKey:      "4.14",
+					nfdKernelLabelKey:           "5.14.0-284.36.1.el9_2.x86_64",
+					nfdOSTreeVersionLabelKey:    currentRHCOS,
+				},
+			}},
+			imageStream,
+			// left over from the pool of the kernel before the switch to the Driver Toolkit
+			&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+				Name:      "rbln-driver-rhcos4.14-5.14.0-284.36.1.el9.2",
+				Namespace: "rbln-system",
+				Labels: map[string]string{
+					driverManagerAppLabelKey:      driverManagerName,
+					driverManagerInstanceLabelKey: "rbln-driver",
+					driverManagerNodePoolLabelKey: "rhcos4.14-5.14.0-284.36.1.el9.2",
+				},
+			}},
+		}
+	})
+
+	clusterVersion := func(desired, state string) *unstructured.Unstructured {
+		cv := &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": clusterVersionName},
+			"status": map[string]interface{}{
+				"desired": map[string]interface{}{"version": desired},
+				"history": []interface{}{map[string]interface{}{"version": desired, "state": state}},
+			},
+		}}
+		cv.SetGroupVersionKind(clusterVersionGVK)
+		return cv
+	}
+
+	daemonSets := func(c client.Client) map[string]appsv1.DaemonSet {
+		list := &appsv1.DaemonSetList{}
+		Expect(c.List(context.Background(), list)).To(Succeed())
+		byName := map[string]appsv1.DaemonSet{}
+		for _, ds := range list.Items {
+			byName[ds.Name] = ds
+		}
+		return byName
+	}
+
+	It("should build the module with the Driver Toolkit of the node RHCOS version", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, clusterVersion("4.14.1", "Completed"))...).Build()
+		patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "4.14", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+		dss := daemonSets(c)
+		Expect(dss).To(HaveLen(1))
+		ds, ok := dss["rbln-driver-rhcos4.14-"+currentRHCOS]
+		Expect(ok).To(BeTrue())
+		podSpec := ds.Spec.Template.Spec
+		Expect(podSpec.NodeSelector).To(HaveKeyWithValue(nfdOSTreeVersionLabelKey, currentRHCOS))
+		Expect(podSpec.InitContainers).To(ContainElement(
+			And(HaveField("Name", driverToolkitContainer), HaveField("Image", "quay.io/dtk@sha256:current")),
+		))
+		Expect(podSpec.Containers).To(ContainElement(And(
+			HaveField("Name", driverManagerContainer),
+			HaveField("Image", "repo.rebellions.ai/rebellions/rbln-driver:3.0.0-rhcos4.14"),
+			HaveField("Env", ContainElement(corev1.EnvVar{Name: driverBuildEnvName, Value: driverBuildSource})),
+			HaveField("VolumeMounts", ContainElements(
+				And(HaveField("Name", driverToolkitKernelVolumeName), HaveField("MountPath", "/host/usr/src")),
+				And(HaveField("Name", driverToolkitKernelVolumeName), HaveField("MountPath", "/host/lib/modules")),
+			)),
+		)))
+		Expect(podSpec.Volumes).NotTo(ContainElement(HaveField("Name", hostKernelSourcesVolumeName)))
+	})
+
+	It("should pre-stage the RHCOS version of a pending cluster update", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, clusterVersion("4.15.3", "Partial"))...).Build()
+		patcher, err := NewDriverManagerPatcher(c, logr.Discard(), "rbln-system", owner, scheme, "4.14", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+		dss := daemonSets(c)
+		Expect(dss).To(HaveLen(2))
+		Expect(dss).To(HaveKey("rbln-driver-rhcos4.14-" + currentRHCOS))
+		next, ok := dss["rbln-driver-rhcos4.15-"+nextRHCOS]
+		Expect(ok).To(BeTrue())
+		Expect(next.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue(nfdOSTreeVersionLabelKey, nextRHCOS))
+		Expect(next.Spec.Template.Spec.Containers).To(ContainElement(And(
+			HaveField("Name", driverManagerContainer),
+			HaveField("Image", "repo.rebellions.ai/rebellions/rbln-driver:3.0.0-rhcos4.15"),
+		)))
+	})
+})
+
+type stubImageResolver map[string]bool
+
+func (r stubImageResolver) Exists(_ context.Context, image string, _ []string) (bool, error) {
+	return r[image], nil
+}
@@ -0,0 +1,171 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+const (
+	// the release payload publishes the Driver Toolkit image of each RHCOS version in this ImageStream
+	driverToolkitImageStream = "driver-toolkit"
+	driverToolkitNamespace   = "openshift"
+	driverToolkitContainer   = "rbln-driver-toolkit-ctr"
+	// the kernel headers and modules of the Driver Toolkit are copied to this volume, which the
+	// driver container mounts in place of the host directories of the source build
+	driverToolkitKernelVolumeName = "driver-toolkit-kernel"
+	driverToolkitKernelMountPath  = "/mnt/driver-toolkit-kernel"
+	clusterVersionName            = "version"
+)
+
+var (
+	imageStreamGVK    = schema.GroupVersionKind{Group: "image.openshift.io", Version: "v1", Kind: "ImageStream"}
+	clusterVersionGVK = schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "ClusterVersion"}
+
+	// rhcosVersionRegex matches RHCOS versions such as 414.92.202310170514-0
+	rhcosVersionRegex = regexp.MustCompile(`^(\d)(\d+)\.\d+\.\d+-\d+$`)
+)
+
+// driverToolkitImages returns the Driver Toolkit image of each RHCOS version of the release.
+func driverToolkitImages(ctx context.Context, c client.Reader) (map[string]string, error) {
+	is := &unstructured.Unstructured{}
+	is.SetGroupVersionKind(imageStreamGVK)
+	if err := c.Get(ctx, types.NamespacedName{Name: driverToolkitImageStream, Namespace: driverToolkitNamespace}, is); err != nil {
+		if kapierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to get ImageStream %s/%s: %w", driverToolkitNamespace, driverToolkitImageStream, err)
+	}
+
+	tags, _, err := unstructured.NestedSlice(is.Object, "spec", "tags")
+	if err != nil {
+		return nil, fmt.Errorf("invalid ImageStream %s/%s: %w", driverToolkitNamespace, driverToolkitImageStream, err)
+	}
+	images := map[string]string{}
+	for _, tag := range tags {
+		tagMap, ok := tag.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(tagMap, "name")
+		kind, _, _ := unstructured.NestedString(tagMap, "from", "kind")
+		image, _, _ := unstructured.NestedString(tagMap, "from", "name")
+		// skip latest and other aliases, only RHCOS versions select nodes
+		if !rhcosVersionRegex.MatchString(name) || kind != "DockerImage" || image == "" {
+			continue
+		}
+		images[name] = image
+	}
+	return images, nil
+}
+
+// pendingClusterVersion returns the version of the release the ClusterVersion is updating to,
+// or "" when no update is in progress.
+func pendingClusterVersion(ctx context.Context, c client.Reader) (string, error) {
+	cv := &unstructured.Unstructured{}
+	cv.SetGroupVersionKind(clusterVersionGVK)
+	if err := c.Get(ctx, types.NamespacedName{Name: clusterVersionName}, cv); err != nil {
+		if kapierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get ClusterVersion: %w", err)
+	}
+	desired, _, _ := unstructured.NestedString(cv.Object, "status", "desired", "version")
+	if desired == "" {
+		return "", nil
+	}
+	history, _, _ := unstructured.NestedSlice(cv.Object, "status", "history")
+	if len(history) == 0 {
+		return desired, nil
+	}
+	// the most recent entry comes first
+	latest, ok := history[0].(map[string]interface{})
+	if !ok {
+		return desired, nil
+	}
+	version, _, _ := unstructured.NestedString(latest, "version")
+	state, _, _ := unstructured.NestedString(latest, "state")
+	if version == desired && state == "Completed" {
+		return "", nil
+	}
+	return desired, nil
+}
+
+// rhcosOSVersion returns the OS version of an RHCOS version, e.g. 4.14 for 414.92.202310170514-0.
+func rhcosOSVersion(rhcosVersion string) (string, bool) {
+	match := rhcosVersionRegex.FindStringSubmatch(rhcosVersion)
+	if match == nil {
+		return "", false
+	}
+	return match[1] + "." + match[2], true
+}
+
+// prestagedRHCOSPools returns the pool of the RHCOS version of a pending cluster update if no node
+// runs it yet, so its driver is scheduled as soon as nodes reboot into it. The ImageStream keeps
+// the tags of earlier releases, so only the newest tag of the OS version of the update is used.
+func (h *driverManagerPatcher) prestagedRHCOSPools(ctx context.Context, pools []nodePool, dtkImages map[string]string) ([]nodePool, error) {
+	desired, err := pendingClusterVersion(ctx, h.client)
+	if err != nil || desired == "" {
+		return nil, err
+	}
+	osVersion := clusterOSVersion(desired)
+
+	var rhcosVersion string
+	for tag := range dtkImages {
+		// RHCOS versions of an OS version only differ by their build timestamp
+		if tagOSVersion, ok := rhcosOSVersion(tag); ok && tagOSVersion == osVersion && tag > rhcosVersion {
+			rhcosVersion = tag
+		}
+	}
+	if rhcosVersion == "" {
+		return nil, nil
+	}
+	for _, pool := range pools {
+		if pool.rhcosVersion == rhcosVersion {
+			return nil, nil
+		}
+	}
+	pool := newRHCOSNodePool(buildNodeSelector(h.desiredSpec.NodeSelector), osVersion, rhcosVersion, "")
+	h.log.Info("Pre-staging driver for the RHCOS version of the pending cluster update", "nodePool", pool.name, "clusterVersion", desired)
+	return []nodePool{pool}, nil
+}
+
+// clusterOSVersion returns the OS version of a cluster version, e.g. 4.15 for 4.15.3.
+func clusterOSVersion(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// newDriverToolkitContainer copies the kernel headers and modules of the Driver Toolkit image of
+// the pool's RHCOS version, which ships the kernel-devel and kernel-core packages of the release.
+// The driver container then runs its source build against them, as on nodes with kernel headers.
+func newDriverToolkitContainer(image string) *corev1.Container {
+	container := k8sutil.NewContainerBuilder().
+		WithName(driverToolkitContainer).
+		WithCommands([]string{"sh", "-c"}).
+		WithArgs([]string{fmt.Sprintf(
+			"mkdir -p %[1]s/usr/src %[1]s/lib/modules && cp -a /usr/src/kernels %[1]s/usr/src/ && cp -a /lib/modules/. %[1]s/lib/modules/",
+			driverToolkitKernelMountPath,
+		)}).
+		WithVolumeMounts([]corev1.VolumeMount{
+			{Name: driverToolkitKernelVolumeName, MountPath: driverToolkitKernelMountPath},
+		}).
+		Build()
+	container.Image = image
+	container.ImagePullPolicy = corev1.PullIfNotPresent
+	return container
+}
@@ -0,0 +1,410 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/firmware"
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+const (
+	// FirmwareInventoryName names the inventory DaemonSets and their service account.
+	FirmwareInventoryName = "rbln-firmware-inventory"
+	// FirmwareFlashName names the service account of the flash jobs, which get no API token.
+	FirmwareFlashName = "rbln-firmware-flash"
+
+	firmwareInventoryInstanceLabelKey = "rebellions.ai/firmware-instance"
+	firmwareHostSysVolumeName         = "host-sys"
+	firmwareHostSysPath               = "/sys"
+	defaultFirmwareInventoryInterval  = 300
+)
+
+type FirmwarePatcher interface {
+	IsEnabled() bool
+	Patch(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNFirmware) error
+	CleanUp(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNFirmware) error
+	ConditionReport(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNFirmware) ([]metav1.Condition, error)
+	ComponentName() string
+	ComponentNamespace() string
+}
+
+// firmwareInventoryPatcher deploys the validator's firmware inventory on the nodes selected by an RBLNFirmware.
+type firmwareInventoryPatcher struct {
+	client client.Client
+	log    logr.Logger
+	scheme *runtime.Scheme
+
+	desiredSpec      *rebellionsaiv1alpha1.RBLNFirmwareSpec
+	validatorSpec    *rblnv1beta1.ValidatorSpec
+	name             string
+	instanceName     string
+	nodeSelector     map[string]string
+	namespace        string
+	openshiftVersion string
+}
+
+func NewFirmwareInventoryPatcher(client client.Client, log logr.Logger, namespace string, fw *rebellionsaiv1alpha1.RBLNFirmware, cpSpec *rblnv1beta1.RBLNClusterPolicySpec, scheme *runtime.Scheme, openshiftVersion string) (FirmwarePatcher, error) {
+	if fw == nil {
+		return nil, fmt.Errorf("firmware is nil")
+	}
+	if cpSpec == nil {
+		return nil, fmt.Errorf("cluster policy is nil")
+	}
+	return &firmwareInventoryPatcher{
+		client:           client,
+		log:              log,
+		scheme:           scheme,
+		desiredSpec:      &fw.Spec,
+		validatorSpec:    &cpSpec.Validator,
+		name:             FirmwareInventoryName,
+		instanceName:     fw.Name,
+		nodeSelector:     fw.GetNodeSelector(),
+		namespace:        namespace,
+		openshiftVersion: openshiftVersion,
+	}, nil
+}
+
+func (h *firmwareInventoryPatcher) IsEnabled() bool {
+	return h.desiredSpec != nil
+}
+
+func (h *firmwareInventoryPatcher) Patch(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNFirmware) error {
+	if !h.IsEnabled() {
+		return nil
+	}
+
+	if err := h.handleServiceAccount(ctx, h.name, nil); err != nil {
+		return err
+	}
+	if err := h.handleServiceAccount(ctx, FirmwareFlashName, ptr(false)); err != nil {
+		return err
+	}
+	if h.openshiftVersion != "" {
+		if err := h.handleRole(ctx); err != nil {
+			return err
+		}
+		if err := h.handleRoleBinding(ctx); err != nil {
+			return err
+		}
+	}
+	if err := h.handleClusterRole(ctx); err != nil {
+		return err
+	}
+	if err := h.handleClusterRoleBinding(ctx); err != nil {
+		return err
+	}
+	if err := h.handleAdmissionPolicy(ctx); err != nil {
+		return err
+	}
+	return h.handleDaemonSet(ctx, owner)
+}
+
+func (h *firmwareInventoryPatcher) CleanUp(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNFirmware) error {
+	h.log.Info("WARNING: Firmware inventory is disabled. Remove all Firmware inventory resources")
+	if err := h.client.Delete(ctx, &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      h.daemonSetName(),
+			Namespace: h.namespace,
+		},
+	}); err != nil && !kapierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (h *firmwareInventoryPatcher) ConditionReport(ctx context.Context, _ *rebellionsaiv1alpha1.RBLNFirmware) ([]metav1.Condition, error) {
+	var ds appsv1.DaemonSet
+	if err := h.client.Get(ctx, types.NamespacedName{Name: h.daemonSetName(), Namespace: h.namespace}, &ds); err != nil {
+		return []metav1.Condition{{
+			Type:               DaemonSetReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             DaemonSetNotFound,
+			Message:            fmt.Sprintf("DaemonSet %s/%s could not be found: %v", h.namespace, h.daemonSetName(), err),
+			LastTransitionTime: metav1.Now(),
+		}}, nil
+	}
+
+	ready := ds.Status.DesiredNumberScheduled > 0 &&
+		ds.Status.NumberReady == ds.Status.DesiredNumberScheduled &&
+		ds.Status.NumberUnavailable == 0
+	if !ready {
+		return []metav1.Condition{{
+			Type:   DaemonSetReady,
+			Status: metav1.ConditionFalse,
+			Reason: DaemonSetPodsNotReady,
+			Message: fmt.Sprintf(
+				"DaemonSet %s/%s is progressing: %d of %d pods are Ready (%d unavailable)",
+				h.namespace,
+				ds.Name,
+				ds.Status.NumberReady,
+				ds.Status.DesiredNumberScheduled,
+				ds.Status.NumberUnavailable,
+			),
+			LastTransitionTime: metav1.Now(),
+			ObservedGeneration: ds.GetGeneration(),
+		}}, nil
+	}
+
+	return []metav1.Condition{{
+		Type:               DaemonSetReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             DaemonSetAllPodsReady,
+		Message:            fmt.Sprintf("All pods in DaemonSet %s/%s are running", h.namespace, ds.Name),
+		LastTransitionTime: metav1.Now(),
+		ObservedGeneration: ds.GetGeneration(),
+	}}, nil
+}
+
+func (h *firmwareInventoryPatcher) ComponentName() string {
+	return h.instanceName
+}
+
+func (h *firmwareInventoryPatcher) ComponentNamespace() string {
+	return h.namespace
+}
+
+func (h *firmwareInventoryPatcher) daemonSetName() string {
+	return fmt.Sprintf("%s-%s", h.name, h.instanceName)
+}
+
+func (h *firmwareInventoryPatcher) handleServiceAccount(ctx context.Context, name string, automountToken *bool) error {
+	builder := k8sutil.NewServiceAccountBuilder(name, h.namespace)
+	sa := builder.Build()
+
+	saRes, err := controllerutil.CreateOrPatch(ctx, h.client, sa, func() error {
+		sa = builder.Build()
+		sa.AutomountServiceAccountToken = automountToken
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile Firmware inventory ServiceAccount")
+		return err
+	}
+	h.log.Info("Reconciled Firmware inventory ServiceAccount", "namespace", sa.Namespace, "name", sa.Name, "result", saRes)
+	return nil
+}
+
+func (h *firmwareInventoryPatcher) handleRole(ctx context.Context) error {
+	builder := k8sutil.NewRoleBuilder(h.name, h.namespace)
+	role := builder.Build()
+
+	roleRes, err := controllerutil.CreateOrPatch(ctx, h.client, role, func() error {
+		role = builder.
+			WithRules(rbacv1.PolicyRule{
+				APIGroups:     []string{"security.openshift.io"},
+				Resources:     []string{"securitycontextconstraints"},
+				ResourceNames: []string{"privileged"},
+				Verbs:         []string{"use"},
+			}).
+			Build()
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile Firmware inventory Role")
+		return err
+	}
+	h.log.Info("Reconciled Firmware inventory Role", "namespace", role.Namespace, "name", role.Name, "result", roleRes)
+	return nil
+}
+
+func (h *firmwareInventoryPatcher) handleRoleBinding(ctx context.Context) error {
+	builder := k8sutil.NewRoleBindingBuilder(h.name, h.namespace)
+	binding := builder.Build()
+
+	bindingRes, err := controllerutil.CreateOrPatch(ctx, h.client, binding, func() error {
+		binding = builder.
+			WithRoleRef(rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "Role",
+				Name:     h.name,
+			}).
+			WithSubjects(
+				rbacv1.Subject{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      h.name,
+					Namespace: h.namespace,
+				},
+				rbacv1.Subject{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      FirmwareFlashName,
+					Namespace: h.namespace,
+				},
+			).
+			Build()
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile Firmware inventory RoleBinding")
+		return err
+	}
+	h.log.Info("Reconciled Firmware inventory RoleBinding", "namespace", binding.Namespace, "name", binding.Name, "result", bindingRes)
+	return nil
+}
+
+func (h *firmwareInventoryPatcher) handleClusterRole(ctx context.Context) error {
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: h.name,
+		},
+	}
+
+	roleRes, err := controllerutil.CreateOrPatch(ctx, h.client, role, func() error {
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"nodes"},
+				Verbs:     []string{"get", "patch"},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile Firmware inventory ClusterRole")
+		return err
+	}
+	h.log.Info("Reconciled Firmware inventory ClusterRole", "name", role.Name, "result", roleRes)
+	return nil
+}
+
+func (h *firmwareInventoryPatcher) handleClusterRoleBinding(ctx context.Context) error {
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: h.name,
+		},
+	}
+
+	bindingRes, err := controllerutil.CreateOrPatch(ctx, h.client, binding, func() error {
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     h.name,
+		}
+		binding.Subjects = []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      h.name,
+				Namespace: h.namespace,
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile Firmware inventory ClusterRoleBinding")
+		return err
+	}
+	h.log.Info("Reconciled Firmware inventory ClusterRoleBinding", "name", binding.Name, "result", bindingRes)
+	return nil
+}
+
+// handleAdmissionPolicy confines the node patches of the inventory to the inventory annotation
+// of the node its pod runs on, since RBAC cannot scope them to a node.
+func (h *firmwareInventoryPatcher) handleAdmissionPolicy(ctx context.Context) error {
+	available, err := isKindAvailable(h.client, validatingAdmissionPolicyGVK)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return fmt.Errorf("the firmware inventory requires the %s API (Kubernetes 1.30+)", validatingAdmissionPolicyGVK.GroupVersion())
+	}
+	spec := buildNodeAnnotationPolicySpec(serviceAccountUsername(h.namespace, h.name), "firmware inventory", []string{firmware.AnnotationKey})
+	return applyAdmissionPolicy(ctx, h.client, h.log, h.name, spec)
+}
+
+func (h *firmwareInventoryPatcher) handleDaemonSet(ctx context.Context, owner *rebellionsaiv1alpha1.RBLNFirmware) error {
+	name := h.daemonSetName()
+	builder := k8sutil.NewDaemonSetBuilder(name, h.namespace)
+	ds := builder.Build()
+
+	imagePullPolicy := h.validatorSpec.ImagePullPolicy
+	if imagePullPolicy == "" {
+		imagePullPolicy = corev1.PullIfNotPresent
+	}
+	interval := h.desiredSpec.InventoryIntervalSeconds
+	if interval <= 0 {
+		interval = defaultFirmwareInventoryInterval
+	}
+
+	container := k8sutil.NewContainerBuilder().
+		WithName(h.name).
+		WithImage(ComposeImageReference(h.validatorSpec.Registry, h.validatorSpec.Image), h.validatorSpec.Version, imagePullPolicy).
+		WithCommands([]string{validatorDefaultCommand}).
+		WithArgs([]string{"firmware", fmt.Sprintf("--sleep-interval-seconds=%d", interval)}).
+		WithEnvs([]corev1.EnvVar{
+			{
+				Name: "NODE_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "spec.nodeName",
+					},
+				},
+			},
+		}).
+		WithSecurityContext(&corev1.SecurityContext{
+			Privileged: ptr(true),
+			RunAsUser:  ptr(int64(0)),
+		}).
+		WithVolumeMounts([]corev1.VolumeMount{
+			{
+				Name:      firmwareHostSysVolumeName,
+				MountPath: "/host-sys",
+				ReadOnly:  true,
+			},
+		}).
+		Build()
+
+	labels := map[string]string{
+		"app":                             name,
+		firmwareInventoryInstanceLabelKey: h.instanceName,
+	}
+	dsRes, err := controllerutil.CreateOrPatch(ctx, h.client, ds, func() error {
+		ds = builder.
+			WithLabelSelectors(labels).
+			WithPodSpec(k8sutil.NewPodSpecBuilder().
+				WithServiceAccountName(h.name).
+				WithNodeSelector(h.nodeSelector).
+				WithTolerations([]corev1.Toleration{
+					{
+						Operator: corev1.TolerationOpExists,
+						Effect:   corev1.TaintEffectNoSchedule,
+					},
+				}).
+				WithImagePullSecrets(h.validatorSpec.ImagePullSecrets).
+				WithVolumes([]corev1.Volume{
+					{
+						Name: firmwareHostSysVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							HostPath: &corev1.HostPathVolumeSource{
+								Path: firmwareHostSysPath,
+								Type: ptr(corev1.HostPathDirectory),
+							},
+						},
+					},
+				}).
+				WithContainers([]*corev1.Container{container}).
+				Build(),
+			).
+			WithOwner(owner, h.scheme).
+			Build()
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile Firmware inventory DaemonSet")
+		return err
+	}
+	h.log.Info("Reconciled Firmware inventory DaemonSet", "namespace", ds.Namespace, "name", ds.Name, "result", dsRes)
+	return nil
+}
@@ -0,0 +1,142 @@
+package patch
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+)
+
+var _ = Describe("FirmwareInventoryPatcher", func() {
+	var (
+		cpSpec *rblnv1beta1.RBLNClusterPolicySpec
+		owner  *rebellionsaiv1alpha1.RBLNFirmware
+		scheme *runtime.Scheme
+	)
+
+	newClient := func() client.Client {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(validatingAdmissionPolicyGVK, meta.RESTScopeRoot)
+		mapper.Add(validatingAdmissionPolicyBindingGVK, meta.RESTScopeRoot)
+		return fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).Build()
+	}
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(rebellionsaiv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		cpSpec = &rblnv1beta1.RBLNClusterPolicySpec{
+			Validator: rblnv1beta1.ValidatorSpec{
+				Registry: "repo.rebellions.ai",
+				Image:    "rebellions/rbln-validator",
+				Version:  "1.0.0",
+			},
+		}
+		owner = &rebellionsaiv1alpha1.RBLNFirmware{
+			Spec: rebellionsaiv1alpha1.RBLNFirmwareSpec{
+				Cards:                    []rebellionsaiv1alpha1.FirmwareCardSpec{{Product: "RBLN-CA22", Version: "1.2.0"}},
+				InventoryIntervalSeconds: 120,
+			},
+		}
+		owner.SetName("fleet")
+		owner.SetUID(types.UID("uid"))
+	})
+
+	It("should run the validator firmware inventory on the selected nodes", func() {
+		c := newClient()
+		patcher, err := NewFirmwareInventoryPatcher(c, logr.Discard(), "rbln-system", owner, cpSpec, scheme, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+		ds := &appsv1.DaemonSet{}
+		Expect(c.Get(context.Background(), client.ObjectKey{Name: "rbln-firmware-inventory-fleet", Namespace: "rbln-system"}, ds)).To(Succeed())
+		Expect(ds.OwnerReferences).To(ContainElement(HaveField("Name", "fleet")))
+		podSpec := ds.Spec.Template.Spec
+		Expect(podSpec.NodeSelector).To(HaveKeyWithValue("rebellions.ai/npu.present", "true"))
+		Expect(podSpec.ServiceAccountName).To(Equal(FirmwareInventoryName))
+		Expect(podSpec.Containers).To(HaveLen(1))
+		Expect(podSpec.Containers[0].Image).To(Equal("repo.rebellions.ai/rebellions/rbln-validator:1.0.0"))
+		Expect(podSpec.Containers[0].Args).To(Equal([]string{"firmware", "--sleep-interval-seconds=120"}))
+
+		role := &rbacv1.ClusterRole{}
+		Expect(c.Get(context.Background(), client.ObjectKey{Name: FirmwareInventoryName}, role)).To(Succeed())
+		Expect(role.Rules).To(ContainElement(HaveField("Resources", ConsistOf("nodes"))))
+	})
+
+	It("should confine the node patches of the inventory to its own node", func() {
+		c := newClient()
+		patcher, err := NewFirmwareInventoryPatcher(c, logr.Discard(), "rbln-system", owner, cpSpec, scheme, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+		policy := &admissionregistrationv1.ValidatingAdmissionPolicy{}
+		Expect(c.Get(context.Background(), client.ObjectKey{Name: FirmwareInventoryName}, policy)).To(Succeed())
+		Expect(policy.Spec.MatchConditions).To(ConsistOf(HaveField("Expression",
+			"request.userInfo.username == 'system:serviceaccount:rbln-system:rbln-firmware-inventory'")))
+		Expect(policy.Spec.Variables).To(ContainElement(HaveField("Expression", "['rebellions.ai/npu.firmware']")))
+		Expect(policy.Spec.Validations).To(ContainElement(HaveField("Expression",
+			ContainSubstring("object.metadata.name in request.userInfo.extra['authentication.kubernetes.io/node-name']"))))
+
+		binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{}
+		Expect(c.Get(context.Background(), client.ObjectKey{Name: FirmwareInventoryName}, binding)).To(Succeed())
+		Expect(binding.Spec.PolicyName).To(Equal(FirmwareInventoryName))
+	})
+
+	It("should give the flash jobs a service account without a token", func() {
+		c := newClient()
+		patcher, err := NewFirmwareInventoryPatcher(c, logr.Discard(), "rbln-system", owner, cpSpec, scheme, "4.16")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+		sa := &corev1.ServiceAccount{}
+		Expect(c.Get(context.Background(), client.ObjectKey{Name: FirmwareFlashName, Namespace: "rbln-system"}, sa)).To(Succeed())
+		Expect(sa.AutomountServiceAccountToken).To(HaveValue(BeFalse()))
+
+		binding := &rbacv1.RoleBinding{}
+		Expect(c.Get(context.Background(), client.ObjectKey{Name: FirmwareInventoryName, Namespace: "rbln-system"}, binding)).To(Succeed())
+		Expect(binding.Subjects).To(ContainElement(HaveField("Name", FirmwareFlashName)))
+		clusterBinding := &rbacv1.ClusterRoleBinding{}
+		Expect(c.Get(context.Background(), client.ObjectKey{Name: FirmwareInventoryName}, clusterBinding)).To(Succeed())
+		Expect(clusterBinding.Subjects).To(ConsistOf(HaveField("Name", FirmwareInventoryName)))
+	})
+
+	It("should fail without the ValidatingAdmissionPolicy API", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(meta.NewDefaultRESTMapper(nil)).Build()
+		patcher, err := NewFirmwareInventoryPatcher(c, logr.Discard(), "rbln-system", owner, cpSpec, scheme, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(patcher.Patch(context.Background(), owner)).To(MatchError(ContainSubstring("Kubernetes 1.30+")))
+	})
+
+	It("should remove the DaemonSet on clean up", func() {
+		c := newClient()
+		patcher, err := NewFirmwareInventoryPatcher(c, logr.Discard(), "rbln-system", owner, cpSpec, scheme, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+		Expect(patcher.CleanUp(context.Background(), owner)).To(Succeed())
+		Expect(patcher.CleanUp(context.Background(), owner)).To(Succeed())
+
+		list := &appsv1.DaemonSetList{}
+		Expect(c.List(context.Background(), list)).To(Succeed())
+		Expect(list.Items).To(BeEmpty())
+	})
+})
@@ -0,0 +1,288 @@
+package patch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+const (
+	grafanaDashboardUID      = "rbln-npu-telemetry"
+	grafanaDashboardTitle    = "RBLN NPU Telemetry"
+	grafanaDashboardFileName = "rbln-npu-telemetry.json"
+	grafanaFolderAnnotation  = "grafana_folder"
+)
+
+var (
+	grafanaDashboardGVK = schema.GroupVersionKind{Group: "grafana.integreatly.org", Version: "v1beta1", Kind: "GrafanaDashboard"}
+	configMapGVK        = corev1.SchemeGroupVersion.WithKind("ConfigMap")
+)
+
+func (h *metricsExporterPatcher) handleGrafanaDashboard(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	if !h.desiredSpec.IsGrafanaDashboardEnabled() {
+		return h.deleteGrafanaDashboard(ctx, owner)
+	}
+
+	dashboard, err := buildNPUDashboard()
+	if err != nil {
+		return err
+	}
+
+	useCR, err := h.useGrafanaDashboardCR()
+	if err != nil {
+		return err
+	}
+	desired := &rblnv1beta1.GrafanaDashboardReference{
+		Kind:      configMapGVK.Kind,
+		Namespace: h.dashboardNamespace(),
+		Name:      h.dashboardName(),
+	}
+	if useCR {
+		desired.Kind = grafanaDashboardGVK.Kind
+	}
+	if err := h.recordGrafanaDashboard(ctx, owner, desired); err != nil {
+		return err
+	}
+
+	if useCR {
+		return h.handleGrafanaDashboardCR(ctx, owner, dashboard)
+	}
+	return h.handleGrafanaDashboardConfigMap(ctx, owner, dashboard)
+}
+
+func (h *metricsExporterPatcher) handleGrafanaDashboardConfigMap(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy, dashboard string) error {
+	dashboardSpec := h.desiredSpec.GrafanaDashboard
+	labels := dashboardSpec.Labels
+	if len(labels) == 0 {
+		labels = map[string]string{"grafana_dashboard": "1"}
+	}
+	var annotations map[string]string
+	if dashboardSpec.Folder != "" {
+		annotations = map[string]string{grafanaFolderAnnotation: dashboardSpec.Folder}
+	}
+
+	builder := k8sutil.NewConfigMapBuilder(h.dashboardName(), h.dashboardNamespace())
+	cm := builder.Build()
+	cmRes, err := controllerutil.CreateOrPatch(ctx, h.client, cm, func() error {
+		cm = builder.
+			WithLabels(k8sutil.MergeMaps(labels, map[string]string{"app": h.name})).
+			WithAnnotations(annotations).
+			WithData(map[string]string{
+				grafanaDashboardFileName: dashboard,
+			}).
+			WithOwner(owner, h.scheme).
+			Build()
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter Grafana dashboard ConfigMap")
+		return err
+	}
+
+	h.log.Info("Reconciled RBLNMetricsExporter Grafana dashboard ConfigMap", "namespace", cm.Namespace, "name", cm.Name, "result", cmRes)
+	return nil
+}
+
+func (h *metricsExporterPatcher) handleGrafanaDashboardCR(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy, dashboard string) error {
+	dashboardSpec := h.desiredSpec.GrafanaDashboard
+	instanceSelector, err := runtime.DefaultUnstructuredConverter.ToUnstructured(dashboardSpec.InstanceSelector)
+	if err != nil {
+		return fmt.Errorf("failed to convert grafana instance selector: %w", err)
+	}
+	spec := map[string]interface{}{
+		"instanceSelector": instanceSelector,
+		"json":             dashboard,
+	}
+	if dashboardSpec.Folder != "" {
+		spec["folder"] = dashboardSpec.Folder
+	}
+
+	builder := k8sutil.NewUnstructuredBuilder(grafanaDashboardGVK, h.dashboardName(), h.dashboardNamespace())
+	gd := builder.Build()
+	gdRes, err := controllerutil.CreateOrPatch(ctx, h.client, gd, func() error {
+		gd = builder.
+			WithLabels(map[string]string{"app": h.name}).
+			WithSpec(spec).
+			WithOwner(owner, h.scheme).
+			Build()
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter GrafanaDashboard")
+		return err
+	}
+
+	h.log.Info("Reconciled RBLNMetricsExporter GrafanaDashboard", "namespace", gd.GetNamespace(), "name", gd.GetName(), "result", gdRes)
+	return nil
+}
+
+func (h *metricsExporterPatcher) deleteGrafanaDashboard(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	return h.recordGrafanaDashboard(ctx, owner, nil)
+}
+
+// recordGrafanaDashboard deletes the dashboard recorded in the RBLNClusterPolicy status when it is
+// not the desired one, e.g. after the namespace or kind changed, and records the desired dashboard.
+func (h *metricsExporterPatcher) recordGrafanaDashboard(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy, desired *rblnv1beta1.GrafanaDashboardReference) error {
+	recorded := owner.Status.GrafanaDashboard
+	if equality.Semantic.DeepEqual(recorded, desired) {
+		return nil
+	}
+	if recorded != nil {
+		gvk := configMapGVK
+		if recorded.Kind == grafanaDashboardGVK.Kind {
+			gvk = grafanaDashboardGVK
+		}
+		if err := deleteIfKindAvailable(ctx, h.client, gvk, recorded.Name, recorded.Namespace); err != nil {
+			return err
+		}
+		h.log.Info("Deleted stale RBLNMetricsExporter Grafana dashboard", "kind", recorded.Kind, "namespace", recorded.Namespace, "name", recorded.Name)
+	}
+
+	original := owner.DeepCopy()
+	owner.Status.GrafanaDashboard = desired
+	if err := h.client.Status().Patch(ctx, owner, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to record the Grafana dashboard in the RBLNClusterPolicy status: %w", err)
+	}
+	return nil
+}
+
+// useGrafanaDashboardCR returns true if the dashboard should be shipped as a Grafana Operator resource.
+func (h *metricsExporterPatcher) useGrafanaDashboardCR() (bool, error) {
+	if h.desiredSpec.GrafanaDashboard.InstanceSelector == nil {
+		return false, nil
+	}
+	return isKindAvailable(h.client, grafanaDashboardGVK)
+}
+
+func (h *metricsExporterPatcher) dashboardName() string {
+	return h.name + "-dashboard"
+}
+
+func (h *metricsExporterPatcher) dashboardNamespace() string {
+	if h.desiredSpec.GrafanaDashboard != nil && h.desiredSpec.GrafanaDashboard.Namespace != "" {
+		return h.desiredSpec.GrafanaDashboard.Namespace
+	}
+	return h.namespace
+}
+
+// buildNPUDashboard renders the Grafana dashboard JSON for the metrics exported by rbln-metrics-exporter.
+func buildNPUDashboard() (string, error) {
+	nodeFilter := fmt.Sprintf(`%s=~"$node"`, npuNodeLabel)
+	cardLegend := fmt.Sprintf("{{%s}} / {{%s}}", npuNodeLabel, npuCardLabel)
+
+	panels := []map[string]interface{}{
+		timeseriesPanel(1, "NPU Utilization", "percent", 0, 0,
+			promTarget(fmt.Sprintf("%s{%s}", npuUtilizationMetric, nodeFilter), cardLegend)),
+		timeseriesPanel(2, "NPU Memory Used", "bytes", 12, 0,
+			promTarget(fmt.Sprintf("%s{%s}", npuMemoryUsedMetric, nodeFilter), cardLegend)),
+		timeseriesPanel(3, "NPU Memory Usage", "percentunit", 0, 8,
+			promTarget(fmt.Sprintf("%s{%s} / %s{%s}", npuMemoryUsedMetric, nodeFilter, npuMemoryTotalMetric, nodeFilter), cardLegend)),
+		timeseriesPanel(4, "NPU Temperature", "celsius", 12, 8,
+			promTarget(fmt.Sprintf("%s{%s}", npuTemperatureMetric, nodeFilter), cardLegend)),
+		timeseriesPanel(5, "NPU Power", "watt", 0, 16,
+			promTarget(fmt.Sprintf("%s{%s}", npuPowerMetric, nodeFilter), cardLegend)),
+		timeseriesPanel(6, "NPU Utilization by Pod", "percent", 12, 16,
+			promTarget(fmt.Sprintf(`sum by (namespace, pod) (%s{%s, pod!=""})`, npuUtilizationMetric, nodeFilter), "{{namespace}}/{{pod}}")),
+		tablePanel(7, "NPU Allocation by Pod", 0, 24,
+			promTarget(fmt.Sprintf(`count by (namespace, pod, %s) (%s{%s, pod!=""})`, npuNodeLabel, npuUtilizationMetric, nodeFilter), "")),
+	}
+
+	dashboard := map[string]interface{}{
+		"uid":           grafanaDashboardUID,
+		"title":         grafanaDashboardTitle,
+		"tags":          []string{"rebellions", "npu"},
+		"timezone":      "browser",
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time": map[string]interface{}{
+			"from": "now-1h",
+			"to":   "now",
+		},
+		"templating": map[string]interface{}{
+			"list": []map[string]interface{}{
+				{
+					"name":  "datasource",
+					"label": "Data source",
+					"type":  "datasource",
+					"query": "prometheus",
+				},
+				{
+					"name":       "node",
+					"label":      "Node",
+					"type":       "query",
+					"datasource": map[string]interface{}{"type": "prometheus", "uid": "${datasource}"},
+					"query":      fmt.Sprintf("label_values(%s, %s)", npuUtilizationMetric, npuNodeLabel),
+					"refresh":    2,
+					"multi":      true,
+					"includeAll": true,
+					"current":    map[string]interface{}{"text": "All", "value": "$__all"},
+				},
+			},
+		},
+		"panels": panels,
+	}
+
+	out, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to render grafana dashboard: %w", err)
+	}
+	return string(out), nil
+}
+
+func promTarget(expr, legend string) map[string]interface{} {
+	target := map[string]interface{}{
+		"refId":      "A",
+		"expr":       expr,
+		"datasource": map[string]interface{}{"type": "prometheus", "uid": "${datasource}"},
+	}
+	if legend != "" {
+		target["legendFormat"] = legend
+	}
+	return target
+}
+
+func timeseriesPanel(id int, title, unit string, x, y int, target map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         id,
+		"type":       "timeseries",
+		"title":      title,
+		"datasource": map[string]interface{}{"type": "prometheus", "uid": "${datasource}"},
+		"gridPos":    map[string]interface{}{"x": x, "y": y, "w": 12, "h": 8},
+		"fieldConfig": map[string]interface{}{
+			"defaults": map[string]interface{}{"unit": unit},
+		},
+		"targets": []map[string]interface{}{target},
+	}
+}
+
+func tablePanel(id int, title string, x, y int, target map[string]interface{}) map[string]interface{} {
+	target["instant"] = true
+	target["format"] = "table"
+	return map[string]interface{}{
+		"id":         id,
+		"type":       "table",
+		"title":      title,
+		"datasource": map[string]interface{}{"type": "prometheus", "uid": "${datasource}"},
+		"gridPos":    map[string]interface{}{"x": x, "y": y, "w": 24, "h": 8},
+		"transformations": []map[string]interface{}{
+			{
+				"id": "organize",
+				"options": map[string]interface{}{
+					"excludeByName": map[string]interface{}{"Time": true},
+					"renameByName":  map[string]interface{}{"Value": "Allocated NPUs"},
+				},
+			},
+		},
+		"targets": []map[string]interface{}{target},
+	}
+}
@@ -0,0 +1,141 @@
+package patch
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+)
+
+var _ = Describe("GrafanaDashboard", func() {
+	var (
+		patcher *metricsExporterPatcher
+		owner   *rblnv1beta1.RBLNClusterPolicy
+		mapper  *meta.DefaultRESTMapper
+	)
+
+	newClient := func() client.Client {
+		return fake.NewClientBuilder().WithScheme(patcher.scheme).WithRESTMapper(mapper).
+			WithObjects(owner).WithStatusSubresource(owner).Build()
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+		mapper = meta.NewDefaultRESTMapper(nil)
+		mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+
+		patcher = &metricsExporterPatcher{
+			log:       logr.Discard(),
+			scheme:    scheme,
+			name:      "rbln-metrics-exporter",
+			namespace: "rbln-system",
+			desiredSpec: &rblnv1beta1.RBLNMetricsExporterSpec{
+				Enabled: true,
+				GrafanaDashboard: &rblnv1beta1.GrafanaDashboardSpec{
+					Enabled:   true,
+					Namespace: "monitoring",
+					Folder:    "NPU",
+					InstanceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"dashboards": "grafana"},
+					},
+				},
+			},
+		}
+		owner = &rblnv1beta1.RBLNClusterPolicy{}
+		owner.SetName("rbln-cluster-policy")
+		owner.SetUID(types.UID("uid"))
+	})
+
+	Describe("buildNPUDashboard", func() {
+		It("should render valid dashboard JSON with the telemetry panels", func() {
+			dashboard, err := buildNPUDashboard()
+			Expect(err).NotTo(HaveOccurred())
+
+			var parsed map[string]interface{}
+			Expect(json.Unmarshal([]byte(dashboard), &parsed)).To(Succeed())
+			Expect(parsed).To(HaveKeyWithValue("uid", grafanaDashboardUID))
+
+			titles := make([]string, 0)
+			for _, p := range parsed["panels"].([]interface{}) {
+				titles = append(titles, p.(map[string]interface{})["title"].(string))
+			}
+			Expect(titles).To(ContainElements(
+				"NPU Utilization", "NPU Memory Used", "NPU Temperature", "NPU Power", "NPU Utilization by Pod",
+			))
+			Expect(dashboard).To(ContainSubstring(`pod!=\"\"`))
+		})
+	})
+
+	Describe("handleGrafanaDashboard", func() {
+		It("should fall back to a labelled ConfigMap without the Grafana Operator CRD", func() {
+			patcher.client = newClient()
+			patcher.desiredSpec.GrafanaDashboard.Labels = map[string]string{"grafana_dashboard": "1"}
+
+			Expect(patcher.handleGrafanaDashboard(context.Background(), owner)).To(Succeed())
+
+			cm := &corev1.ConfigMap{}
+			Expect(patcher.client.Get(context.Background(), types.NamespacedName{Name: "rbln-metrics-exporter-dashboard", Namespace: "monitoring"}, cm)).To(Succeed())
+			Expect(cm.Labels).To(HaveKeyWithValue("grafana_dashboard", "1"))
+			Expect(cm.Annotations).To(HaveKeyWithValue(grafanaFolderAnnotation, "NPU"))
+			Expect(cm.Data).To(HaveKey(grafanaDashboardFileName))
+		})
+
+		It("should delete the ConfigMap left in the previous namespace", func() {
+			patcher.client = newClient()
+			Expect(patcher.handleGrafanaDashboard(context.Background(), owner)).To(Succeed())
+
+			patcher.desiredSpec.GrafanaDashboard.Namespace = "grafana"
+			Expect(patcher.handleGrafanaDashboard(context.Background(), owner)).To(Succeed())
+
+			cm := &corev1.ConfigMap{}
+			Expect(patcher.client.Get(context.Background(), types.NamespacedName{Name: "rbln-metrics-exporter-dashboard", Namespace: "grafana"}, cm)).To(Succeed())
+			Expect(patcher.client.Get(context.Background(), types.NamespacedName{Name: "rbln-metrics-exporter-dashboard", Namespace: "monitoring"}, cm)).NotTo(Succeed())
+
+			recorded := &rblnv1beta1.RBLNClusterPolicy{}
+			Expect(patcher.client.Get(context.Background(), client.ObjectKeyFromObject(owner), recorded)).To(Succeed())
+			Expect(recorded.Status.GrafanaDashboard).To(Equal(&rblnv1beta1.GrafanaDashboardReference{
+				Kind: "ConfigMap", Namespace: "grafana", Name: "rbln-metrics-exporter-dashboard",
+			}))
+		})
+
+		It("should not look dashboards up when it is disabled and none was recorded", func() {
+			patcher.desiredSpec.GrafanaDashboard.Enabled = false
+			// the client has no mapping for ConfigMaps, so any lookup would fail
+			patcher.client = fake.NewClientBuilder().WithScheme(patcher.scheme).WithRESTMapper(meta.NewDefaultRESTMapper(nil)).Build()
+
+			Expect(patcher.handleGrafanaDashboard(context.Background(), owner)).To(Succeed())
+		})
+
+		It("should create a GrafanaDashboard when the CRD is installed", func() {
+			mapper.Add(grafanaDashboardGVK, meta.RESTScopeNamespace)
+			patcher.client = newClient()
+
+			Expect(patcher.handleGrafanaDashboard(context.Background(), owner)).To(Succeed())
+
+			gd := &unstructured.Unstructured{}
+			gd.SetGroupVersionKind(grafanaDashboardGVK)
+			Expect(patcher.client.Get(context.Background(), types.NamespacedName{Name: "rbln-metrics-exporter-dashboard", Namespace: "monitoring"}, gd)).To(Succeed())
+			matchLabels, _, _ := unstructured.NestedStringMap(gd.Object, "spec", "instanceSelector", "matchLabels")
+			Expect(matchLabels).To(HaveKeyWithValue("dashboards", "grafana"))
+
+			patcher.desiredSpec.GrafanaDashboard.Enabled = false
+			Expect(patcher.handleGrafanaDashboard(context.Background(), owner)).To(Succeed())
+			Expect(patcher.client.Get(context.Background(), types.NamespacedName{Name: "rbln-metrics-exporter-dashboard", Namespace: "monitoring"}, gd)).NotTo(Succeed())
+		})
+	})
+})
@@ -3,15 +3,19 @@ package patch
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -20,6 +24,45 @@ import (
 	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
 )
 
+const (
+	npuTemperatureMetric = "rbln_device_temperature"
+	npuECCErrorMetric    = "rbln_device_ecc_errors_total"
+	npuUtilizationMetric = "rbln_device_utilization"
+	npuMemoryUsedMetric  = "rbln_device_memory_used_bytes"
+	npuMemoryTotalMetric = "rbln_device_memory_total_bytes"
+	npuPowerMetric       = "rbln_device_power_watts"
+	npuCardLabel         = "card"
+	npuNodeLabel         = "node"
+
+	defaultNPUTemperatureThreshold = 85
+
+	// the exporter serves plain HTTP on the configured port; the tls and rbacProxy modes put
+	// kube-rbac-proxy on the configured port in front of it, move the exporter to the upstream
+	// port and only admit traffic to the proxy
+	defaultMetricsExporterPort   = 9090
+	metricsExporterUpstreamPort  = 9091
+	metricsExporterPortEnv       = "RBLN_METRICS_EXPORTER_PORT"
+	metricsExporterTLSVolumeName = "metrics-tls"
+	metricsExporterTLSMountPath  = "/etc/rbln/metrics-tls"
+	metricsExporterDaemonURLEnv  = "RBLN_METRICS_EXPORTER_RBLN_DAEMON_URL"
+
+	// The exporter reads its settings from RBLN_METRICS_EXPORTER_ prefixed variables. When the
+	// rbln-daemon serves mTLS, the client certificate is passed with the variables below, as
+	// defined by the rbln-daemon TLS component contract (RBLNDaemonTLSSpec.ComponentContract).
+	metricsExporterDaemonCAFileEnv     = metricsExporterDaemonURLEnv + "_TLS_CA_FILE"
+	metricsExporterDaemonCertFileEnv   = metricsExporterDaemonURLEnv + "_TLS_CERT_FILE"
+	metricsExporterDaemonKeyFileEnv    = metricsExporterDaemonURLEnv + "_TLS_KEY_FILE"
+	metricsExporterDaemonServerNameEnv = metricsExporterDaemonURLEnv + "_TLS_SERVER_NAME"
+
+	openshiftServingCertAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+	serviceAccountTokenKey         = "token"
+)
+
+var (
+	serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	prometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+)
+
 type metricsExporterPatcher struct {
 	client client.Client
 	log    logr.Logger
@@ -29,6 +72,8 @@ type metricsExporterPatcher struct {
 	name             string
 	namespace        string
 	openshiftVersion string
+
+	daemonClient rblnDaemonClient
 }
 
 func NewMetricsExporterPatcher(client client.Client, log logr.Logger, namespace string, cpSpec *rblnv1beta1.RBLNClusterPolicySpec, scheme *runtime.Scheme, openshiftVersion string) (Patcher, error) {
@@ -40,6 +85,8 @@ func NewMetricsExporterPatcher(client client.Client, log logr.Logger, namespace
 		name:             cpSpec.BaseName + "-" + consts.RBLNMetricExporterName,
 		namespace:        namespace,
 		openshiftVersion: openshiftVersion,
+
+		daemonClient: newRBLNDaemonClient(cpSpec, namespace),
 	}
 
 	synced := syncSpec(cpSpec, cpSpec.MetricsExporter)
@@ -55,6 +102,10 @@ func (h *metricsExporterPatcher) IsEnabled() bool {
 	return h.desiredSpec.IsEnabled()
 }
 
+func (h *metricsExporterPatcher) ManagementState() rblnv1beta1.ManagementState {
+	return componentManagementState(h.desiredSpec)
+}
+
 func (h *metricsExporterPatcher) Patch(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	if !h.desiredSpec.IsEnabled() {
 		return nil
@@ -74,13 +125,71 @@ func (h *metricsExporterPatcher) Patch(ctx context.Context, owner *rblnv1beta1.R
 		}
 	}
 
+	mode := h.desiredSpec.GetSecurityMode()
+	if mode != rblnv1beta1.MetricsSecurityModeNone {
+		// the ServiceMonitor verifies the proxy with the CA of its serving certificate
+		if h.tlsSecretName() == "" {
+			return fmt.Errorf("metrics exporter security mode %q requires a TLS secret or a cert-manager issuer", mode)
+		}
+		if h.metricsPort() == metricsExporterUpstreamPort {
+			return fmt.Errorf("metrics exporter port %d is used by the exporter behind kube-rbac-proxy in security mode %q", metricsExporterUpstreamPort, mode)
+		}
+	}
+
+	// reconcile serving certificate
+	if err := h.handleCertificate(ctx, owner); err != nil {
+		return err
+	}
+
+	// reconcile auth proxy RBAC and the identity Prometheus scrapes with
+	if mode == rblnv1beta1.MetricsSecurityModeRBACProxy {
+		if err := h.handleClusterRole(ctx, owner); err != nil {
+			return err
+		}
+		if err := h.handleClusterRoleBinding(ctx, owner); err != nil {
+			return err
+		}
+		if err := h.handleScraper(ctx, owner); err != nil {
+			return err
+		}
+	} else {
+		if err := h.deleteClusterRBAC(ctx); err != nil {
+			return err
+		}
+		if err := h.deleteScraper(ctx); err != nil {
+			return err
+		}
+	}
+
+	// the exporter port is reachable on the pod IP, so only admit traffic to the proxy
+	if mode != rblnv1beta1.MetricsSecurityModeNone {
+		if err := h.handleNetworkPolicy(ctx, owner); err != nil {
+			return err
+		}
+	} else if err := h.deleteNetworkPolicy(ctx); err != nil {
+		return err
+	}
+
+	// reconcile service before the daemonset so that OpenShift can provision the serving certificate
+	if err := h.handleService(ctx, owner); err != nil {
+		return err
+	}
+
 	// reconcile daemonset
 	if err := h.handleDaemonSet(ctx, owner); err != nil {
 		return err
 	}
 
-	// reconcile service
-	if err := h.handleService(ctx, owner); err != nil {
+	// reconcile prometheus operator resources
+	if err := h.handleServiceMonitor(ctx, owner); err != nil {
+		return err
+	}
+	if err := h.handlePrometheusRule(ctx, owner); err != nil {
+		return err
+	}
+
+	// reconcile grafana dashboard
+	if err := h.handleGrafanaDashboard(ctx, owner); err != nil {
 		return err
 	}
 
@@ -89,6 +198,28 @@ func (h *metricsExporterPatcher) Patch(ctx context.Context, owner *rblnv1beta1.R
 
 func (h *metricsExporterPatcher) CleanUp(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	h.log.Info("WARNING: Metrics Exporter is disabled. Remove all Metrics Exporter resources")
+	if err := h.deleteGrafanaDashboard(ctx, owner); err != nil {
+		return err
+	}
+	if err := deleteIfKindAvailable(ctx, h.client, prometheusRuleGVK, h.name, h.namespace); err != nil {
+		return err
+	}
+	if err := deleteIfKindAvailable(ctx, h.client, serviceMonitorGVK, h.name, h.namespace); err != nil {
+		return err
+	}
+	if err := deleteIfKindAvailable(ctx, h.client, certificateGVK, h.name, h.namespace); err != nil {
+		return err
+	}
+	if err := h.deleteClusterRBAC(ctx); err != nil {
+		return err
+	}
+	if err := h.deleteScraper(ctx); err != nil {
+		return err
+	}
+	if err := h.deleteNetworkPolicy(ctx); err != nil {
+		return err
+	}
+
 	if err := h.client.Delete(ctx, &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      h.name + "-service",
@@ -267,19 +398,26 @@ func (h *metricsExporterPatcher) handleService(ctx context.Context, cp *rblnv1be
 	labelsMap := map[string]string{
 		"app": h.name,
 	}
+	port := h.metricsPort()
+	annotations := map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/path":   "/metrics",
+		"prometheus.io/port":   fmt.Sprintf("%d", port),
+		"prometheus.io/scheme": h.metricsPortName(),
+	}
+	if h.useServingCertAnnotation() {
+		annotations[openshiftServingCertAnnotation] = h.tlsSecretName()
+	}
 	svcRes, err := controllerutil.CreateOrPatch(ctx, h.client, svc, func() error {
 		svc = builder.
-			WithAnnotations(map[string]string{
-				"prometheus.io/scrape": "true",
-				"prometheus.io/path":   "/metrics",
-				"prometheus.io/port":   "9090",
-			}).
+			WithAnnotations(annotations).
 			WithLabels(labelsMap).
 			WithSelector(labelsMap).
 			WithPorts([]corev1.ServicePort{
 				{
-					Name: "http",
-					Port: 9090,
+					Name:       h.metricsPortName(),
+					Port:       port,
+					TargetPort: intstr.FromString(h.metricsPortName()),
 				},
 			}).
 			WithOwner(cp, h.scheme).
@@ -295,6 +433,236 @@ func (h *metricsExporterPatcher) handleService(ctx context.Context, cp *rblnv1be
 	return nil
 }
 
+func (h *metricsExporterPatcher) handleCertificate(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	security := h.desiredSpec.Security
+	if h.desiredSpec.GetSecurityMode() == rblnv1beta1.MetricsSecurityModeNone || security.TLS == nil || security.TLS.CertManager == nil {
+		return deleteIfKindAvailable(ctx, h.client, certificateGVK, h.name, h.namespace)
+	}
+
+	svcName := h.name + "-service"
+	certRes, err := reconcileCertificate(ctx, h.client, h.scheme, owner, security.TLS.CertManager, certificateRequest{
+		name:       h.name,
+		namespace:  h.namespace,
+		secretName: h.tlsSecretName(),
+		commonName: svcName,
+		dnsNames: []string{
+			svcName,
+			fmt.Sprintf("%s.%s.svc", svcName, h.namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", svcName, h.namespace),
+		},
+		usages: []string{"server auth"},
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter Certificate")
+		return err
+	}
+
+	h.log.Info("Reconciled RBLNMetricsExporter Certificate", "namespace", h.namespace, "name", h.name, "result", certRes)
+	return nil
+}
+
+func (h *metricsExporterPatcher) handleClusterRole(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: h.name,
+		},
+	}
+
+	roleRes, err := controllerutil.CreateOrPatch(ctx, h.client, role, func() error {
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"authentication.k8s.io"},
+				Resources: []string{"tokenreviews"},
+				Verbs:     []string{"create"},
+			},
+			{
+				APIGroups: []string{"authorization.k8s.io"},
+				Resources: []string{"subjectaccessreviews"},
+				Verbs:     []string{"create"},
+			},
+		}
+		return controllerutil.SetControllerReference(owner, role, h.scheme)
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter ClusterRole")
+		return err
+	}
+	h.log.Info("Reconciled RBLNMetricsExporter ClusterRole", "name", role.Name, "result", roleRes)
+	return nil
+}
+
+func (h *metricsExporterPatcher) handleClusterRoleBinding(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: h.name,
+		},
+	}
+
+	bindingRes, err := controllerutil.CreateOrPatch(ctx, h.client, binding, func() error {
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     h.name,
+		}
+		binding.Subjects = []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      h.name,
+				Namespace: h.namespace,
+			},
+		}
+		return controllerutil.SetControllerReference(owner, binding, h.scheme)
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter ClusterRoleBinding")
+		return err
+	}
+	h.log.Info("Reconciled RBLNMetricsExporter ClusterRoleBinding", "name", binding.Name, "result", bindingRes)
+	return nil
+}
+
+func (h *metricsExporterPatcher) deleteClusterRBAC(ctx context.Context) error {
+	if err := h.client.Delete(ctx, &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: h.name,
+		},
+	}); err != nil && !kapierrors.IsNotFound(err) {
+		return err
+	}
+	if err := h.client.Delete(ctx, &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: h.name,
+		},
+	}); err != nil && !kapierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// handleScraper grants get on /metrics to a ServiceAccount whose token the ServiceMonitor
+// authorizes scrapes with, so that kube-rbac-proxy admits Prometheus.
+func (h *metricsExporterPatcher) handleScraper(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	scraperName := h.scraperName()
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: scraperName, Namespace: h.namespace}}
+	saRes, err := controllerutil.CreateOrPatch(ctx, h.client, sa, func() error {
+		return controllerutil.SetControllerReference(owner, sa, h.scheme)
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter scraper ServiceAccount")
+		return err
+	}
+	h.log.Info("Reconciled RBLNMetricsExporter scraper ServiceAccount", "namespace", sa.Namespace, "name", sa.Name, "result", saRes)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: h.scraperTokenSecretName(), Namespace: h.namespace}}
+	secretRes, err := controllerutil.CreateOrPatch(ctx, h.client, secret, func() error {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[corev1.ServiceAccountNameKey] = scraperName
+		secret.Type = corev1.SecretTypeServiceAccountToken
+		return controllerutil.SetControllerReference(owner, secret, h.scheme)
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter scraper token Secret")
+		return err
+	}
+	h.log.Info("Reconciled RBLNMetricsExporter scraper token Secret", "namespace", secret.Namespace, "name", secret.Name, "result", secretRes)
+
+	role := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: scraperName}}
+	roleRes, err := controllerutil.CreateOrPatch(ctx, h.client, role, func() error {
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				NonResourceURLs: []string{"/metrics"},
+				Verbs:           []string{"get"},
+			},
+		}
+		return controllerutil.SetControllerReference(owner, role, h.scheme)
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter scraper ClusterRole")
+		return err
+	}
+	h.log.Info("Reconciled RBLNMetricsExporter scraper ClusterRole", "name", role.Name, "result", roleRes)
+
+	binding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: scraperName}}
+	bindingRes, err := controllerutil.CreateOrPatch(ctx, h.client, binding, func() error {
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     scraperName,
+		}
+		binding.Subjects = []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      scraperName,
+				Namespace: h.namespace,
+			},
+		}
+		return controllerutil.SetControllerReference(owner, binding, h.scheme)
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter scraper ClusterRoleBinding")
+		return err
+	}
+	h.log.Info("Reconciled RBLNMetricsExporter scraper ClusterRoleBinding", "name", binding.Name, "result", bindingRes)
+	return nil
+}
+
+func (h *metricsExporterPatcher) deleteScraper(ctx context.Context) error {
+	scraperName := h.scraperName()
+	for _, obj := range []client.Object{
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: scraperName}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: scraperName}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: h.scraperTokenSecretName(), Namespace: h.namespace}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: scraperName, Namespace: h.namespace}},
+	} {
+		if err := h.client.Delete(ctx, obj); err != nil && !kapierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *metricsExporterPatcher) handleNetworkPolicy(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	builder := k8sutil.NewNetworkPolicyBuilder(h.name, h.namespace)
+	np := builder.Build()
+	npRes, err := controllerutil.CreateOrPatch(ctx, h.client, np, func() error {
+		np = builder.
+			WithLabels(map[string]string{"app": h.name}).
+			WithPodSelector(map[string]string{"app": h.name}).
+			WithIngressRules(networkingv1.NetworkPolicyIngressRule{
+				Ports: []networkingv1.NetworkPolicyPort{
+					{
+						Protocol: ptr(corev1.ProtocolTCP),
+						Port:     ptr(intstr.FromInt32(h.metricsPort())),
+					},
+				},
+			}).
+			WithOwner(owner, h.scheme).
+			Build()
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter NetworkPolicy")
+		return err
+	}
+	h.log.Info("Reconciled RBLNMetricsExporter NetworkPolicy", "namespace", np.Namespace, "name", np.Name, "result", npRes)
+	return nil
+}
+
+func (h *metricsExporterPatcher) deleteNetworkPolicy(ctx context.Context) error {
+	if err := h.client.Delete(ctx, &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      h.name,
+			Namespace: h.namespace,
+		},
+	}); err != nil && !kapierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
 func (h *metricsExporterPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	builder := k8sutil.NewDaemonSetBuilder(h.name, h.namespace)
 	ds := builder.Build()
@@ -333,7 +701,7 @@ func (h *metricsExporterPatcher) handleDaemonSet(ctx context.Context, owner *rbl
 					WithAffinity(h.desiredSpec.Affinity).
 					WithTolerations(h.desiredSpec.Tolerations).
 					WithImagePullSecrets(h.desiredSpec.ImagePullSecrets).
-					WithVolumes([]corev1.Volume{
+					WithVolumes(append([]corev1.Volume{
 						{
 							Name: validationsVolumeName,
 							VolumeSource: corev1.VolumeSource{
@@ -361,56 +729,9 @@ func (h *metricsExporterPatcher) handleDaemonSet(ctx context.Context, owner *rbl
 								},
 							},
 						},
-					}).
+					}, slices.Concat(h.tlsVolumes(), h.daemonClient.volumes())...)).
 					WithInitContainers([]*corev1.Container{initContainer}).
-					WithContainers([]*corev1.Container{
-						k8sutil.NewContainerBuilder().
-							WithName(h.name).
-							WithImage(ComposeImageReference(h.desiredSpec.Registry, h.desiredSpec.Image), h.desiredSpec.Version, h.desiredSpec.ImagePullPolicy).
-							WithVolumeMounts([]corev1.VolumeMount{
-								{
-									Name:      "pod-resources",
-									MountPath: "/var/lib/kubelet/pod-resources",
-									ReadOnly:  true,
-								},
-								{
-									Name:      "sysfs",
-									MountPath: "/sys",
-									ReadOnly:  true,
-								},
-							}).
-							WithEnvs([]corev1.EnvVar{
-								{
-									Name: "NODE_IP",
-									ValueFrom: &corev1.EnvVarSource{
-										FieldRef: &corev1.ObjectFieldSelector{
-											APIVersion: "v1",
-											FieldPath:  "status.hostIP",
-										},
-									},
-								},
-								{
-									Name: "NODE_NAME",
-									ValueFrom: &corev1.EnvVarSource{
-										FieldRef: &corev1.ObjectFieldSelector{
-											FieldPath: "spec.nodeName",
-										},
-									},
-								},
-								{
-									Name:  "RBLN_METRICS_EXPORTER_RBLN_DAEMON_URL",
-									Value: "http://$(NODE_IP):50051",
-								},
-							}).
-							WithResources(h.desiredSpec.Resources, "250m", "40Mi").
-							WithSecurityContext(&corev1.SecurityContext{
-								Privileged:             ptr(true),
-								RunAsUser:              ptr(int64(0)),
-								RunAsGroup:             ptr(int64(0)),
-								ReadOnlyRootFilesystem: ptr(false),
-							}).
-							Build(),
-					}).
+					WithContainers(h.buildContainers()).
 					WithTerminationGracePeriodSeconds(0).
 					Build(),
 			).
@@ -426,3 +747,409 @@ func (h *metricsExporterPatcher) handleDaemonSet(ctx context.Context, owner *rbl
 	h.log.Info("Reconciled RBLNMetricsExporter DaemonSet", "namespace", ds.Namespace, "name", ds.Name, "result", dsRes)
 	return nil
 }
+
+func (h *metricsExporterPatcher) handleServiceMonitor(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	available, err := isKindAvailable(h.client, serviceMonitorGVK)
+	if err != nil {
+		return err
+	}
+	if !available {
+		if h.desiredSpec.IsServiceMonitorEnabled() {
+			h.log.Info("ServiceMonitor CRD is not installed, skipping RBLNMetricsExporter ServiceMonitor")
+		}
+		return nil
+	}
+	if !h.desiredSpec.IsServiceMonitorEnabled() {
+		return deleteIfKindAvailable(ctx, h.client, serviceMonitorGVK, h.name, h.namespace)
+	}
+
+	spec, err := h.buildServiceMonitorSpec()
+	if err != nil {
+		return err
+	}
+
+	builder := k8sutil.NewUnstructuredBuilder(serviceMonitorGVK, h.name, h.namespace)
+	sm := builder.Build()
+	smRes, err := controllerutil.CreateOrPatch(ctx, h.client, sm, func() error {
+		sm = builder.
+			WithLabels(k8sutil.MergeMaps(h.desiredSpec.ServiceMonitor.AdditionalLabels, map[string]string{"app": h.name})).
+			WithSpec(spec).
+			WithOwner(owner, h.scheme).
+			Build()
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter ServiceMonitor")
+		return err
+	}
+
+	h.log.Info("Reconciled RBLNMetricsExporter ServiceMonitor", "namespace", sm.GetNamespace(), "name", sm.GetName(), "result", smRes)
+	return nil
+}
+
+func (h *metricsExporterPatcher) handlePrometheusRule(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	available, err := isKindAvailable(h.client, prometheusRuleGVK)
+	if err != nil {
+		return err
+	}
+	if !available {
+		if h.desiredSpec.IsPrometheusRuleEnabled() {
+			h.log.Info("PrometheusRule CRD is not installed, skipping RBLNMetricsExporter PrometheusRule")
+		}
+		return nil
+	}
+	if !h.desiredSpec.IsPrometheusRuleEnabled() {
+		return deleteIfKindAvailable(ctx, h.client, prometheusRuleGVK, h.name, h.namespace)
+	}
+
+	builder := k8sutil.NewUnstructuredBuilder(prometheusRuleGVK, h.name, h.namespace)
+	rule := builder.Build()
+	ruleRes, err := controllerutil.CreateOrPatch(ctx, h.client, rule, func() error {
+		rule = builder.
+			WithLabels(k8sutil.MergeMaps(h.desiredSpec.PrometheusRule.AdditionalLabels, map[string]string{"app": h.name})).
+			WithSpec(h.buildPrometheusRuleSpec()).
+			WithOwner(owner, h.scheme).
+			Build()
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNMetricsExporter PrometheusRule")
+		return err
+	}
+
+	h.log.Info("Reconciled RBLNMetricsExporter PrometheusRule", "namespace", rule.GetNamespace(), "name", rule.GetName(), "result", ruleRes)
+	return nil
+}
+
+func (h *metricsExporterPatcher) buildServiceMonitorSpec() (map[string]interface{}, error) {
+	smSpec := h.desiredSpec.ServiceMonitor
+
+	endpoint := map[string]interface{}{
+		"port":        h.metricsPortName(),
+		"path":        "/metrics",
+		"scheme":      h.metricsPortName(),
+		"honorLabels": smSpec.HonorLabels,
+	}
+	if h.desiredSpec.GetSecurityMode() != rblnv1beta1.MetricsSecurityModeNone {
+		svcName := h.name + "-service"
+		tlsConfig := map[string]interface{}{
+			"serverName": fmt.Sprintf("%s.%s.svc", svcName, h.namespace),
+		}
+		if h.useServingCertAnnotation() {
+			tlsConfig["caFile"] = "/etc/prometheus/configmaps/serving-certs-ca-bundle/service-ca.crt"
+		} else {
+			tlsConfig["ca"] = map[string]interface{}{
+				"secret": map[string]interface{}{
+					"name": h.tlsSecretName(),
+					"key":  tlsCAKey,
+				},
+			}
+		}
+		endpoint["tlsConfig"] = tlsConfig
+	}
+	if h.desiredSpec.GetSecurityMode() == rblnv1beta1.MetricsSecurityModeRBACProxy {
+		endpoint["authorization"] = map[string]interface{}{
+			"type": "Bearer",
+			"credentials": map[string]interface{}{
+				"name": h.scraperTokenSecretName(),
+				"key":  serviceAccountTokenKey,
+			},
+		}
+	}
+	if smSpec.Interval != "" {
+		endpoint["interval"] = smSpec.Interval
+	}
+	// label the series with the node name, which the dashboard and the alerts group by
+	relabelings := []interface{}{
+		map[string]interface{}{
+			"sourceLabels": []interface{}{"__meta_kubernetes_pod_node_name"},
+			"targetLabel":  npuNodeLabel,
+			"action":       "replace",
+		},
+	}
+	for i := range smSpec.Relabelings {
+		relabeling, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&smSpec.Relabelings[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert relabeling config: %w", err)
+		}
+		relabelings = append(relabelings, relabeling)
+	}
+	endpoint["relabelings"] = relabelings
+
+	return map[string]interface{}{
+		"jobLabel": "app",
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				"app": h.name,
+			},
+		},
+		"namespaceSelector": map[string]interface{}{
+			"matchNames": []interface{}{h.namespace},
+		},
+		"endpoints": []interface{}{endpoint},
+	}, nil
+}
+
+// buildPrometheusRuleSpec returns alerts on the scrape status of the exporter, on the temperature
+// and ECC error series of the exporter, and on the RBLNNPUHealthy node condition exported by
+// kube-state-metrics.
+func (h *metricsExporterPatcher) buildPrometheusRuleSpec() map[string]interface{} {
+	threshold := h.desiredSpec.PrometheusRule.TemperatureThreshold
+	if threshold <= 0 {
+		threshold = defaultNPUTemperatureThreshold
+	}
+	up := fmt.Sprintf(`up{job=%q, namespace=%q}`, h.name, h.namespace)
+
+	alert := func(name, expr, duration, severity, summary, description string) interface{} {
+		return map[string]interface{}{
+			"alert": name,
+			"expr":  expr,
+			"for":   duration,
+			"labels": map[string]interface{}{
+				"severity": severity,
+			},
+			"annotations": map[string]interface{}{
+				"summary":     summary,
+				"description": description,
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name": h.name + ".rules",
+				"rules": []interface{}{
+					alert(
+						"RBLNMetricsExporterDown",
+						up+" == 0",
+						"5m", "warning",
+						"RBLN metrics exporter is down",
+						"RBLN metrics exporter {{ $labels.pod }} on node {{ $labels.node }} has not been scraped successfully for 5 minutes.",
+					),
+					// up is only reported for discovered targets, so a missing exporter never makes it 0
+					alert(
+						"RBLNMetricsExporterAbsent",
+						"absent("+up+")",
+						"10m", "warning",
+						"RBLN metrics exporter is not scraped",
+						"No RBLN metrics exporter target has been discovered for 10 minutes.",
+					),
+					alert(
+						"RBLNNPUTemperatureHigh",
+						fmt.Sprintf("max by (%s, %s) (%s) > %d", npuNodeLabel, npuCardLabel, npuTemperatureMetric, threshold),
+						"5m", "warning",
+						"RBLN NPU temperature is high",
+						fmt.Sprintf("NPU {{ $labels.%s }} on node {{ $labels.%s }} has been above %d°C for 5 minutes.", npuCardLabel, npuNodeLabel, threshold),
+					),
+					alert(
+						"RBLNNPUECCErrors",
+						fmt.Sprintf("sum by (%s, %s) (increase(%s[10m])) > 0", npuNodeLabel, npuCardLabel, npuECCErrorMetric),
+						"1m", "critical",
+						"RBLN NPU reports ECC errors",
+						fmt.Sprintf("NPU {{ $labels.%s }} on node {{ $labels.%s }} reported {{ $value }} ECC errors in the last 10 minutes.", npuCardLabel, npuNodeLabel),
+					),
+					alert(
+						"RBLNNPUUnhealthy",
+						fmt.Sprintf(`kube_node_status_condition{condition=%q, status="false"} == 1`, consts.RBLNNodeConditionTypeNPUHealthy),
+						"5m", "critical",
+						"RBLN NPU is unhealthy",
+						fmt.Sprintf("Node {{ $labels.node }} has reported the %s condition as False for 5 minutes.", consts.RBLNNodeConditionTypeNPUHealthy),
+					),
+				},
+			},
+		},
+	}
+}
+
+func (h *metricsExporterPatcher) buildContainers() []*corev1.Container {
+	mode := h.desiredSpec.GetSecurityMode()
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "pod-resources",
+			MountPath: "/var/lib/kubelet/pod-resources",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "sysfs",
+			MountPath: "/sys",
+			ReadOnly:  true,
+		},
+	}
+	envs := []corev1.EnvVar{
+		{
+			Name: "NODE_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  "status.hostIP",
+				},
+			},
+		},
+		{
+			Name: "NODE_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "spec.nodeName",
+				},
+			},
+		},
+		{
+			Name:  metricsExporterPortEnv,
+			Value: fmt.Sprintf("%d", h.exporterPort()),
+		},
+		{
+			Name:  metricsExporterDaemonURLEnv,
+			Value: h.daemonClient.url(),
+		},
+	}
+	if h.daemonClient.tls {
+		envs = append(envs,
+			corev1.EnvVar{Name: metricsExporterDaemonCAFileEnv, Value: h.daemonClient.caFile()},
+			corev1.EnvVar{Name: metricsExporterDaemonCertFileEnv, Value: h.daemonClient.certFile()},
+			corev1.EnvVar{Name: metricsExporterDaemonKeyFileEnv, Value: h.daemonClient.keyFile()},
+			corev1.EnvVar{Name: metricsExporterDaemonServerNameEnv, Value: h.daemonClient.serverName},
+		)
+		volumeMounts = append(volumeMounts, h.daemonClient.volumeMounts()...)
+	}
+	// the exporter port is only published when it is scraped directly
+	var ports []corev1.ContainerPort
+	if mode == rblnv1beta1.MetricsSecurityModeNone {
+		ports = []corev1.ContainerPort{{Name: h.metricsPortName(), ContainerPort: h.metricsPort(), Protocol: corev1.ProtocolTCP}}
+	}
+
+	containers := []*corev1.Container{
+		k8sutil.NewContainerBuilder().
+			WithName(h.name).
+			WithImage(ComposeImageReference(h.desiredSpec.Registry, h.desiredSpec.Image), h.desiredSpec.Version, h.desiredSpec.ImagePullPolicy).
+			WithVolumeMounts(volumeMounts).
+			WithEnvs(envs).
+			WithPorts(ports).
+			WithResources(h.desiredSpec.Resources, "250m", "40Mi").
+			WithSecurityContext(&corev1.SecurityContext{
+				Privileged:             ptr(true),
+				RunAsUser:              ptr(int64(0)),
+				RunAsGroup:             ptr(int64(0)),
+				ReadOnlyRootFilesystem: ptr(false),
+			}).
+			Build(),
+	}
+
+	if mode != rblnv1beta1.MetricsSecurityModeNone {
+		containers = append(containers, h.buildRBACProxyContainer())
+	}
+	return containers
+}
+
+// buildRBACProxyContainer returns the kube-rbac-proxy sidecar serving the exporter over HTTPS.
+// In tls mode it only terminates TLS; in rbacProxy mode it also authorizes every scrape.
+func (h *metricsExporterPatcher) buildRBACProxyContainer() *corev1.Container {
+	proxySpec := h.desiredSpec.Security.RBACProxy
+	args := []string{
+		fmt.Sprintf("--secure-listen-address=0.0.0.0:%d", h.metricsPort()),
+		fmt.Sprintf("--upstream=http://127.0.0.1:%d/", h.exporterPort()),
+	}
+	if h.desiredSpec.GetSecurityMode() == rblnv1beta1.MetricsSecurityModeTLS {
+		args = append(args, "--ignore-paths=/metrics")
+	} else {
+		args = append(args, "--allow-paths=/metrics")
+	}
+	var volumeMounts []corev1.VolumeMount
+	if h.tlsSecretName() != "" {
+		args = append(args,
+			"--tls-cert-file="+metricsExporterTLSMountPath+"/"+tlsCertKey,
+			"--tls-private-key-file="+metricsExporterTLSMountPath+"/"+tlsKeyKey,
+		)
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: metricsExporterTLSVolumeName, MountPath: metricsExporterTLSMountPath, ReadOnly: true})
+	}
+
+	container := k8sutil.NewContainerBuilder().
+		WithName("kube-rbac-proxy").
+		WithImage(ComposeImageReference(proxySpec.Registry, proxySpec.Image), proxySpec.Version, proxySpec.ImagePullPolicy).
+		WithArgs(args).
+		WithVolumeMounts(volumeMounts).
+		WithPorts([]corev1.ContainerPort{{Name: h.metricsPortName(), ContainerPort: h.metricsPort(), Protocol: corev1.ProtocolTCP}}).
+		WithResources(proxySpec.Resources, "10m", "20Mi").
+		WithSecurityContext(&corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr(false),
+			ReadOnlyRootFilesystem:   ptr(true),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		}).
+		Build()
+	if container.ImagePullPolicy == "" {
+		container.ImagePullPolicy = corev1.PullIfNotPresent
+	}
+	return container
+}
+
+func (h *metricsExporterPatcher) tlsVolumes() []corev1.Volume {
+	secretName := h.tlsSecretName()
+	if h.desiredSpec.GetSecurityMode() == rblnv1beta1.MetricsSecurityModeNone || secretName == "" {
+		return nil
+	}
+	return []corev1.Volume{
+		{
+			Name: metricsExporterTLSVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secretName,
+				},
+			},
+		},
+	}
+}
+
+func (h *metricsExporterPatcher) metricsPort() int32 {
+	if h.desiredSpec.Port > 0 {
+		return h.desiredSpec.Port
+	}
+	return defaultMetricsExporterPort
+}
+
+// exporterPort returns the port the exporter listens on, behind kube-rbac-proxy in the tls and
+// rbacProxy modes.
+func (h *metricsExporterPatcher) exporterPort() int32 {
+	if h.desiredSpec.GetSecurityMode() == rblnv1beta1.MetricsSecurityModeNone {
+		return h.metricsPort()
+	}
+	return metricsExporterUpstreamPort
+}
+
+func (h *metricsExporterPatcher) scraperName() string {
+	return h.name + "-scraper"
+}
+
+func (h *metricsExporterPatcher) scraperTokenSecretName() string {
+	return h.scraperName() + "-token"
+}
+
+func (h *metricsExporterPatcher) metricsPortName() string {
+	if h.desiredSpec.GetSecurityMode() == rblnv1beta1.MetricsSecurityModeNone {
+		return "http"
+	}
+	return "https"
+}
+
+// tlsSecretName returns the Secret holding the serving certificate, or an empty string if none is configured.
+func (h *metricsExporterPatcher) tlsSecretName() string {
+	if h.desiredSpec.Security == nil {
+		return ""
+	}
+	if secretName := tlsSecretName(h.desiredSpec.Security.TLS, h.name+"-tls"); secretName != "" {
+		return secretName
+	}
+	if h.useServingCertAnnotation() {
+		return h.name + "-tls"
+	}
+	return ""
+}
+
+// useServingCertAnnotation returns true if the serving certificate is provisioned by the OpenShift service CA.
+func (h *metricsExporterPatcher) useServingCertAnnotation() bool {
+	return h.openshiftVersion != "" &&
+		h.desiredSpec.GetSecurityMode() != rblnv1beta1.MetricsSecurityModeNone &&
+		h.desiredSpec.Security.TLS == nil
+}
@@ -0,0 +1,303 @@
+package patch
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+)
+
+var _ = Describe("MetricsExporterPatcher", func() {
+	var patcher *metricsExporterPatcher
+
+	BeforeEach(func() {
+		patcher = &metricsExporterPatcher{
+			log:       logr.Discard(),
+			name:      "rbln-metrics-exporter",
+			namespace: "rbln-system",
+			desiredSpec: &rblnv1beta1.RBLNMetricsExporterSpec{
+				Enabled: true,
+				ServiceMonitor: &rblnv1beta1.ServiceMonitorSpec{
+					Enabled:  true,
+					Interval: "30s",
+					Relabelings: []rblnv1beta1.RelabelConfig{
+						{
+							SourceLabels: []string{"__meta_kubernetes_pod_host_ip"},
+							TargetLabel:  "host_ip",
+							Action:       "replace",
+						},
+					},
+				},
+				PrometheusRule: &rblnv1beta1.PrometheusRuleSpec{
+					Enabled:              true,
+					TemperatureThreshold: 90,
+				},
+			},
+		}
+	})
+
+	Describe("buildServiceMonitorSpec", func() {
+		It("should select the exporter service and apply relabelings", func() {
+			spec, err := patcher.buildServiceMonitorSpec()
+			Expect(err).NotTo(HaveOccurred())
+
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+			matchLabels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "selector", "matchLabels")
+			Expect(matchLabels).To(HaveKeyWithValue("app", "rbln-metrics-exporter"))
+
+			endpoints, _, _ := unstructured.NestedSlice(obj.Object, "spec", "endpoints")
+			Expect(endpoints).To(HaveLen(1))
+			endpoint := endpoints[0].(map[string]interface{})
+			Expect(endpoint).To(HaveKeyWithValue("interval", "30s"))
+			Expect(endpoint["relabelings"]).To(HaveLen(2))
+			Expect(endpoint["relabelings"].([]interface{})[0]).To(HaveKeyWithValue("targetLabel", "node"))
+			Expect(endpoint["relabelings"].([]interface{})[1]).To(HaveKeyWithValue("targetLabel", "host_ip"))
+
+			// the spec must be deep copyable to be used with CreateOrPatch
+			Expect(func() { obj.DeepCopy() }).NotTo(Panic())
+		})
+	})
+
+	Describe("buildPrometheusRuleSpec", func() {
+		It("should alert on the exporter, the NPU devices and the NPU health condition", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": patcher.buildPrometheusRuleSpec()}}
+			groups, _, _ := unstructured.NestedSlice(obj.Object, "spec", "groups")
+			Expect(groups).To(HaveLen(1))
+
+			rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+			alerts := make(map[string]string)
+			for _, r := range rules {
+				rule := r.(map[string]interface{})
+				alerts[rule["alert"].(string)] = rule["expr"].(string)
+			}
+			Expect(alerts).To(HaveKeyWithValue("RBLNMetricsExporterDown", `up{job="rbln-metrics-exporter", namespace="rbln-system"} == 0`))
+			Expect(alerts).To(HaveKeyWithValue("RBLNMetricsExporterAbsent", `absent(up{job="rbln-metrics-exporter", namespace="rbln-system"})`))
+			Expect(alerts).To(HaveKeyWithValue("RBLNNPUTemperatureHigh", "max by (node, card) (rbln_device_temperature) > 90"))
+			Expect(alerts).To(HaveKeyWithValue("RBLNNPUECCErrors", ContainSubstring("increase(rbln_device_ecc_errors_total[10m])")))
+			Expect(alerts).To(HaveKeyWithValue("RBLNNPUUnhealthy", ContainSubstring(`condition="RBLNNPUHealthy"`)))
+			Expect(alerts).To(HaveLen(5))
+			Expect(func() { obj.DeepCopy() }).NotTo(Panic())
+		})
+	})
+
+	Describe("handleServiceMonitor", func() {
+		It("should skip when the monitoring CRDs are not installed", func() {
+			scheme := runtime.NewScheme()
+			Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+			patcher.scheme = scheme
+			patcher.client = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRESTMapper(meta.NewDefaultRESTMapper(nil)).
+				Build()
+
+			owner := &rblnv1beta1.RBLNClusterPolicy{}
+			owner.SetName("rbln-cluster-policy")
+			owner.SetUID(types.UID("uid"))
+
+			Expect(patcher.handleServiceMonitor(context.Background(), owner)).To(Succeed())
+			Expect(patcher.handlePrometheusRule(context.Background(), owner)).To(Succeed())
+			Expect(deleteIfKindAvailable(context.Background(), patcher.client, serviceMonitorGVK, patcher.name, patcher.namespace)).To(Succeed())
+		})
+
+		It("should create and remove the ServiceMonitor when the CRD is installed", func() {
+			scheme := runtime.NewScheme()
+			Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(serviceMonitorGVK, meta.RESTScopeNamespace)
+			patcher.scheme = scheme
+			patcher.client = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRESTMapper(mapper).
+				Build()
+
+			owner := &rblnv1beta1.RBLNClusterPolicy{}
+			owner.SetName("rbln-cluster-policy")
+			owner.SetUID(types.UID("uid"))
+
+			Expect(patcher.handleServiceMonitor(context.Background(), owner)).To(Succeed())
+
+			sm := &unstructured.Unstructured{}
+			sm.SetGroupVersionKind(serviceMonitorGVK)
+			key := types.NamespacedName{Name: patcher.name, Namespace: patcher.namespace}
+			Expect(patcher.client.Get(context.Background(), key, sm)).To(Succeed())
+			Expect(sm.GetOwnerReferences()).To(HaveLen(1))
+
+			patcher.desiredSpec.ServiceMonitor.Enabled = false
+			Expect(patcher.handleServiceMonitor(context.Background(), owner)).To(Succeed())
+			Expect(patcher.client.Get(context.Background(), key, sm)).NotTo(Succeed())
+		})
+
+		It("should not delete the ServiceMonitor again once it is gone", func() {
+			scheme := runtime.NewScheme()
+			Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(serviceMonitorGVK, meta.RESTScopeNamespace)
+			deletes := 0
+			patcher.scheme = scheme
+			patcher.client = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRESTMapper(mapper).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+						deletes++
+						return c.Delete(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			patcher.desiredSpec.ServiceMonitor.Enabled = false
+
+			owner := &rblnv1beta1.RBLNClusterPolicy{}
+			owner.SetName("rbln-cluster-policy")
+			Expect(patcher.handleServiceMonitor(context.Background(), owner)).To(Succeed())
+			Expect(patcher.handleServiceMonitor(context.Background(), owner)).To(Succeed())
+			Expect(deletes).To(BeZero())
+		})
+	})
+
+	Describe("buildContainers", func() {
+		It("should serve plain HTTP on the default port", func() {
+			containers := patcher.buildContainers()
+			Expect(containers).To(HaveLen(1))
+			Expect(containers[0].Ports).To(ConsistOf(HaveField("ContainerPort", int32(9090))))
+			Expect(containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "RBLN_METRICS_EXPORTER_PORT", Value: "9090"}))
+			Expect(patcher.tlsVolumes()).To(BeEmpty())
+		})
+
+		It("should serve plain HTTP on the configured port", func() {
+			patcher.desiredSpec.Port = 9400
+			containers := patcher.buildContainers()
+			Expect(containers[0].Ports).To(ConsistOf(HaveField("ContainerPort", int32(9400))))
+			Expect(containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "RBLN_METRICS_EXPORTER_PORT", Value: "9400"}))
+		})
+
+		It("should front the exporter with kube-rbac-proxy", func() {
+			patcher.desiredSpec.Port = 443
+			patcher.desiredSpec.Security = &rblnv1beta1.MetricsExporterSecuritySpec{
+				Mode: rblnv1beta1.MetricsSecurityModeRBACProxy,
+				TLS:  &rblnv1beta1.TLSCertificateSpec{SecretName: "metrics-tls"},
+				RBACProxy: rblnv1beta1.RBACProxySpec{
+					Registry: "quay.io",
+					Image:    "brancz/kube-rbac-proxy",
+					Version:  "v0.18.1",
+				},
+			}
+
+			containers := patcher.buildContainers()
+			Expect(containers).To(HaveLen(2))
+			Expect(containers[0].Ports).To(BeEmpty())
+			Expect(containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "RBLN_METRICS_EXPORTER_PORT", Value: "9091"}))
+			Expect(containers[1].Image).To(Equal("quay.io/brancz/kube-rbac-proxy:v0.18.1"))
+			Expect(containers[1].Args).To(ContainElements(
+				"--secure-listen-address=0.0.0.0:443",
+				"--upstream=http://127.0.0.1:9091/",
+				"--allow-paths=/metrics",
+			))
+			Expect(containers[1].Ports).To(ConsistOf(And(HaveField("Name", "https"), HaveField("ContainerPort", int32(443)))))
+
+			spec, err := patcher.buildServiceMonitorSpec()
+			Expect(err).NotTo(HaveOccurred())
+			endpoint := spec["endpoints"].([]interface{})[0].(map[string]interface{})
+			Expect(endpoint).NotTo(HaveKey("bearerTokenFile"))
+			Expect(endpoint).To(HaveKeyWithValue("authorization", map[string]interface{}{
+				"type": "Bearer",
+				"credentials": map[string]interface{}{
+					"name": "rbln-metrics-exporter-scraper-token",
+					"key":  "token",
+				},
+			}))
+			Expect(endpoint["tlsConfig"]).To(HaveKeyWithValue("ca", map[string]interface{}{
+				"secret": map[string]interface{}{"name": "metrics-tls", "key": "ca.crt"},
+			}))
+			Expect(endpoint["tlsConfig"]).NotTo(HaveKey("insecureSkipVerify"))
+		})
+
+		It("should require a serving certificate in rbacProxy mode", func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+			patcher.scheme = scheme
+			patcher.client = fake.NewClientBuilder().WithScheme(scheme).Build()
+			owner := &rblnv1beta1.RBLNClusterPolicy{}
+			owner.SetName("rbln-cluster-policy")
+			owner.SetUID(types.UID("uid"))
+
+			patcher.desiredSpec.Security = &rblnv1beta1.MetricsExporterSecuritySpec{Mode: rblnv1beta1.MetricsSecurityModeRBACProxy}
+			Expect(patcher.Patch(context.Background(), owner)).To(MatchError(ContainSubstring("requires a TLS secret")))
+
+			patcher.openshiftVersion = "4.16"
+			Expect(patcher.tlsSecretName()).To(Equal("rbln-metrics-exporter-tls"))
+			spec, err := patcher.buildServiceMonitorSpec()
+			Expect(err).NotTo(HaveOccurred())
+			endpoint := spec["endpoints"].([]interface{})[0].(map[string]interface{})
+			Expect(endpoint["tlsConfig"]).To(HaveKey("caFile"))
+		})
+
+		It("should mount the cert-manager issued secret in tls mode", func() {
+			patcher.desiredSpec.Security = &rblnv1beta1.MetricsExporterSecuritySpec{
+				Mode: rblnv1beta1.MetricsSecurityModeTLS,
+				TLS: &rblnv1beta1.TLSCertificateSpec{
+					CertManager: &rblnv1beta1.CertManagerIssuerSpec{IssuerName: "ca-issuer"},
+				},
+			}
+
+			Expect(patcher.tlsSecretName()).To(Equal("rbln-metrics-exporter-tls"))
+			Expect(patcher.tlsVolumes()).To(ConsistOf(HaveField("VolumeSource.Secret.SecretName", "rbln-metrics-exporter-tls")))
+			containers := patcher.buildContainers()
+			Expect(containers).To(HaveLen(2))
+			Expect(containers[0].Ports).To(BeEmpty())
+			Expect(containers[1].Args).To(ContainElement("--ignore-paths=/metrics"))
+			Expect(containers[1].VolumeMounts).To(ContainElement(HaveField("Name", metricsExporterTLSVolumeName)))
+
+			spec, err := patcher.buildServiceMonitorSpec()
+			Expect(err).NotTo(HaveOccurred())
+			endpoint := spec["endpoints"].([]interface{})[0].(map[string]interface{})
+			Expect(endpoint).To(HaveKeyWithValue("scheme", "https"))
+			Expect(endpoint["tlsConfig"]).To(HaveKey("ca"))
+			Expect(endpoint).NotTo(HaveKey("authorization"))
+		})
+	})
+
+	Describe("handleScraper", func() {
+		It("should grant the scraper ServiceAccount get on /metrics", func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+			patcher.scheme = scheme
+			patcher.client = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			owner := &rblnv1beta1.RBLNClusterPolicy{}
+			owner.SetName("rbln-cluster-policy")
+			owner.SetUID(types.UID("uid"))
+			Expect(patcher.handleScraper(context.Background(), owner)).To(Succeed())
+
+			secret := &corev1.Secret{}
+			Expect(patcher.client.Get(context.Background(), types.NamespacedName{Name: "rbln-metrics-exporter-scraper-token", Namespace: "rbln-system"}, secret)).To(Succeed())
+			Expect(secret.Type).To(Equal(corev1.SecretTypeServiceAccountToken))
+			Expect(secret.Annotations).To(HaveKeyWithValue(corev1.ServiceAccountNameKey, "rbln-metrics-exporter-scraper"))
+
+			role := &rbacv1.ClusterRole{}
+			Expect(patcher.client.Get(context.Background(), types.NamespacedName{Name: "rbln-metrics-exporter-scraper"}, role)).To(Succeed())
+			Expect(role.Rules).To(ConsistOf(HaveField("NonResourceURLs", []string{"/metrics"})))
+
+			binding := &rbacv1.ClusterRoleBinding{}
+			Expect(patcher.client.Get(context.Background(), types.NamespacedName{Name: "rbln-metrics-exporter-scraper"}, binding)).To(Succeed())
+			Expect(binding.Subjects).To(ConsistOf(HaveField("Name", "rbln-metrics-exporter-scraper")))
+
+			Expect(patcher.deleteScraper(context.Background())).To(Succeed())
+			Expect(patcher.client.Get(context.Background(), types.NamespacedName{Name: "rbln-metrics-exporter-scraper"}, role)).NotTo(Succeed())
+		})
+	})
+})
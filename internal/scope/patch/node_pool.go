@@ -17,6 +17,9 @@ const (
 	nfdOSReleaseIDLabelKey = "feature.node.kubernetes.io/system-os_release.ID"
 	nfdOSVersionIDLabelKey = "feature.node.kubernetes.io/system-os_release.VERSION_ID"
 	nfdKernelLabelKey      = "feature.node.kubernetes.io/kernel-version.full"
+	// nfdOSTreeVersionLabelKey is the RHCOS version of OpenShift nodes, e.g. 414.92.202310170514-0
+	nfdOSTreeVersionLabelKey = "feature.node.kubernetes.io/system-os_release.OSTREE_VERSION"
+	rhcosOSReleaseID         = "rhcos"
 )
 
 type nodePool struct {
@@ -24,11 +27,14 @@ type nodePool struct {
 	osRelease    string
 	osVersion    string
 	kernel       string
+	rhcosVersion string
 	nodeSelector map[string]string
 }
 
 // getNodePools partitions nodes per osVersion-kernelVersion for precompiled drivers.
-func getNodePools(ctx context.Context, k8sClient client.Client, selector map[string]string) ([]nodePool, error) {
+// With byRHCOSVersion, RHCOS nodes are partitioned per RHCOS version instead, which
+// determines the kernel and the Driver Toolkit image.
+func getNodePools(ctx context.Context, k8sClient client.Client, selector map[string]string, byRHCOSVersion bool) ([]nodePool, error) {
 	nodePoolMap := make(map[string]nodePool)
 
 	logger := log.FromContext(ctx)
@@ -42,7 +48,7 @@ func getNodePools(ctx context.Context, k8sClient client.Client, selector map[str
 	}
 
 	for _, node := range nodeList.Items {
-		nodePool, ok := buildNodePool(node, nodeSelector, logger)
+		nodePool, ok := buildNodePool(node, nodeSelector, byRHCOSVersion, logger)
 		if !ok {
 			continue
 		}
@@ -68,7 +74,7 @@ func buildNodeSelector(selector map[string]string) map[string]string {
 	return nodeSelector
 }
 
-func buildNodePool(node corev1.Node, baseSelector map[string]string, logger logr.Logger) (nodePool, bool) {
+func buildNodePool(node corev1.Node, baseSelector map[string]string, byRHCOSVersion bool, logger logr.Logger) (nodePool, bool) {
 	nodeLabels := node.GetLabels()
 	nodePool := nodePool{
 		nodeSelector: make(map[string]string),
@@ -94,13 +100,38 @@ func buildNodePool(node corev1.Node, baseSelector map[string]string, logger logr
 	if !ok {
 		return nodePool, false
 	}
-	nodePool.nodeSelector[nfdKernelLabelKey] = kernelVersion
 	nodePool.kernel = kernelVersion
+
+	if byRHCOSVersion && osID == rhcosOSReleaseID {
+		rhcosVersion, ok := getNodeLabel(nodeLabels, node.Name, nfdOSTreeVersionLabelKey, logger)
+		if !ok {
+			return nodePool, false
+		}
+		return newRHCOSNodePool(baseSelector, osVersion, rhcosVersion, kernelVersion), true
+	}
+
+	nodePool.nodeSelector[nfdKernelLabelKey] = kernelVersion
 	nodePool.name = fmt.Sprintf("%s-%s", nodePool.name, getSanitizedKernelVersion(kernelVersion))
 
 	return nodePool, true
 }
 
+// newRHCOSNodePool returns the pool of an RHCOS version. The kernel is empty for pools
+// created ahead of a cluster update, before any node runs the version.
+func newRHCOSNodePool(baseSelector map[string]string, osVersion, rhcosVersion, kernel string) nodePool {
+	pool := nodePool{
+		osRelease:    rhcosOSReleaseID,
+		osVersion:    osVersion,
+		kernel:       kernel,
+		rhcosVersion: rhcosVersion,
+		nodeSelector: maps.Clone(baseSelector),
+	}
+	pool.nodeSelector[nfdOSReleaseIDLabelKey] = rhcosOSReleaseID
+	pool.nodeSelector[nfdOSTreeVersionLabelKey] = rhcosVersion
+	pool.name = fmt.Sprintf("%s-%s", pool.getOS(), rhcosVersion)
+	return pool
+}
+
 func getNodeLabel(labels map[string]string, nodeName string, labelKey string, logger logr.Logger) (string, bool) {
 	value, ok := labels[labelKey]
 	if !ok {
@@ -114,6 +145,14 @@ func (n nodePool) getOS() string {
 	return fmt.Sprintf("%s%s", n.osRelease, n.osVersion)
 }
 
+// cacheKey identifies the built module of the pool on the host.
+func (n nodePool) cacheKey() string {
+	if n.rhcosVersion != "" {
+		return n.rhcosVersion
+	}
+	return n.kernel
+}
+
 func getSanitizedKernelVersion(kernelVersion string) string {
 	archRegex := regexp.MustCompile("x86_64(?:_64k)?|aarch64(?:_64k)?")
 	sanitized := archRegex.ReplaceAllString(kernelVersion, "")
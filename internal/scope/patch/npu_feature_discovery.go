@@ -29,6 +29,8 @@ type npuFeatureDiscoveryPatcher struct {
 	name             string
 	namespace        string
 	openshiftVersion string
+
+	daemonClient rblnDaemonClient
 }
 
 func NewNPUFeatureDiscoveryPatcher(client client.Client, log logr.Logger, namespace string, cpSpec *rblnv1beta1.RBLNClusterPolicySpec, scheme *runtime.Scheme, openshiftVersion string) (Patcher, error) {
@@ -40,6 +42,8 @@ func NewNPUFeatureDiscoveryPatcher(client client.Client, log logr.Logger, namesp
 		name:             cpSpec.BaseName + "-" + consts.RBLNFeatureDiscoveryName,
 		namespace:        namespace,
 		openshiftVersion: openshiftVersion,
+
+		daemonClient: newRBLNDaemonClient(cpSpec, namespace),
 	}
 
 	synced := syncSpec(cpSpec, cpSpec.NPUFeatureDiscovery)
@@ -55,6 +59,10 @@ func (h *npuFeatureDiscoveryPatcher) IsEnabled() bool {
 	return h.desiredSpec.IsEnabled()
 }
 
+func (h *npuFeatureDiscoveryPatcher) ManagementState() rblnv1beta1.ManagementState {
+	return componentManagementState(h.desiredSpec)
+}
+
 func (h *npuFeatureDiscoveryPatcher) Patch(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	if !h.desiredSpec.IsEnabled() {
 		return nil
@@ -278,7 +286,7 @@ func (h *npuFeatureDiscoveryPatcher) handleDaemonSet(ctx context.Context, owner
 				WithAffinity(h.desiredSpec.Affinity).
 				WithTolerations(h.desiredSpec.Tolerations).
 				WithImagePullSecrets(h.desiredSpec.ImagePullSecrets).
-				WithVolumes([]corev1.Volume{
+				WithVolumes(append([]corev1.Volume{
 					{
 						Name: validationsVolumeName,
 						VolumeSource: corev1.VolumeSource{
@@ -296,7 +304,7 @@ func (h *npuFeatureDiscoveryPatcher) handleDaemonSet(ctx context.Context, owner
 							},
 						},
 					},
-				}).
+				}, h.daemonClient.volumes()...)).
 				WithInitContainers([]*corev1.Container{initContainer}).
 				WithTerminationGracePeriodSeconds(0).
 				WithContainers([]*corev1.Container{
@@ -315,17 +323,14 @@ func (h *npuFeatureDiscoveryPatcher) handleDaemonSet(ctx context.Context, owner
 							},
 						}).
 						WithResources(h.desiredSpec.Resources, "250m", "40Mi").
-						WithArgs([]string{
-							"--rbln-daemon-url",
-							"http://$(NODE_IP):50051",
-						}).
-						WithVolumeMounts([]corev1.VolumeMount{
+						WithArgs(h.buildArgs()).
+						WithVolumeMounts(append([]corev1.VolumeMount{
 							{
 								Name:      "features-dir",
 								MountPath: "/etc/kubernetes/node-feature-discovery/features.d",
 								ReadOnly:  false,
 							},
-						}).
+						}, h.daemonClient.volumeMounts()...)).
 						WithSecurityContext(&corev1.SecurityContext{
 							Privileged: ptr(true),
 							RunAsUser:  ptr(int64(0)),
@@ -346,3 +351,19 @@ func (h *npuFeatureDiscoveryPatcher) handleDaemonSet(ctx context.Context, owner
 	h.log.Info("Reconciled RBLNNPUFeatureDiscovery DaemonSet", "namespace", ds.Namespace, "name", ds.Name, "result", dsRes)
 	return nil
 }
+
+func (h *npuFeatureDiscoveryPatcher) buildArgs() []string {
+	args := []string{
+		"--rbln-daemon-url",
+		h.daemonClient.url(),
+	}
+	if h.daemonClient.tls {
+		args = append(args,
+			"--rbln-daemon-tls-ca-file", h.daemonClient.caFile(),
+			"--rbln-daemon-tls-cert-file", h.daemonClient.certFile(),
+			"--rbln-daemon-tls-key-file", h.daemonClient.keyFile(),
+			"--rbln-daemon-tls-server-name", h.daemonClient.serverName,
+		)
+	}
+	return args
+}
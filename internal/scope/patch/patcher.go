@@ -10,6 +10,7 @@ import (
 
 type Patcher interface {
 	IsEnabled() bool
+	ManagementState() rblnv1beta1.ManagementState
 	Patch(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error
 	CleanUp(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error
 	ConditionReport(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) ([]metav1.Condition, error)
@@ -2,16 +2,21 @@ package patch
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"slices"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -33,8 +38,108 @@ const (
 	rblnDaemonLogPath          = "/var/log/rebellions"
 	rblnDaemonVarRunVolumeName = "host-var-run"
 	rblnDaemonVarRunPath       = "/var/run"
+
+	rblnDaemonServerTLSVolumeName = "rbln-daemon-tls"
+	rblnDaemonServerTLSMountPath  = "/etc/rbln/rbln-daemon-tls"
+	rblnDaemonClientTLSVolumeName = "rbln-daemon-client-tls"
+	rblnDaemonClientTLSMountPath  = "/etc/rbln/rbln-daemon-client-tls"
+	rblnDaemonCAVolumeName        = "rbln-daemon-ca"
+	rblnDaemonCAMountPath         = "/etc/rbln/rbln-daemon-ca"
+
+	rblnDaemonServerSecretName = "rbln-daemon-server-tls"
+	rblnDaemonClientSecretName = "rbln-daemon-client-tls"
+	rblnDaemonCABundleName     = "rbln-daemon-ca-bundle"
 )
 
+// rblnDaemonClient describes how operator components connect to rbln-daemon.
+type rblnDaemonClient struct {
+	hostPort         int32
+	tls              bool
+	serverName       string
+	clientSecretName string
+}
+
+func newRBLNDaemonClient(cpSpec *rblnv1beta1.RBLNClusterPolicySpec, namespace string) rblnDaemonClient {
+	daemonSpec := cpSpec.RBLNDaemon
+	hostPort := daemonSpec.HostPort
+	if hostPort == 0 {
+		hostPort = rblnDaemonDefaultHostPort
+	}
+	c := rblnDaemonClient{
+		hostPort: hostPort,
+		tls:      daemonSpec.IsEnabled() && daemonSpec.IsTLSEnabled(),
+	}
+	if c.tls {
+		// the daemon is reached through the node IP, so clients verify the Service name instead
+		c.serverName = fmt.Sprintf("%s.%s.svc", consts.RBLNDaemonName, namespace)
+		c.clientSecretName = tlsSecretName(&daemonSpec.TLS.ClientCertificate, rblnDaemonClientSecretName)
+	}
+	return c
+}
+
+// url returns the rbln-daemon endpoint on the local node, expecting NODE_IP to be set in the container.
+func (c rblnDaemonClient) url() string {
+	scheme := "http"
+	if c.tls {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://$(NODE_IP):%d", scheme, c.hostPort)
+}
+
+func (c rblnDaemonClient) caFile() string {
+	return rblnDaemonCAMountPath + "/" + tlsCAKey
+}
+
+func (c rblnDaemonClient) certFile() string {
+	return rblnDaemonClientTLSMountPath + "/" + tlsCertKey
+}
+
+func (c rblnDaemonClient) keyFile() string {
+	return rblnDaemonClientTLSMountPath + "/" + tlsKeyKey
+}
+
+func (c rblnDaemonClient) volumes() []corev1.Volume {
+	if !c.tls {
+		return nil
+	}
+	return []corev1.Volume{
+		{
+			Name: rblnDaemonClientTLSVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: c.clientSecretName,
+				},
+			},
+		},
+		{
+			Name: rblnDaemonCAVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: rblnDaemonCABundleName},
+				},
+			},
+		},
+	}
+}
+
+func (c rblnDaemonClient) volumeMounts() []corev1.VolumeMount {
+	if !c.tls {
+		return nil
+	}
+	return []corev1.VolumeMount{
+		{
+			Name:      rblnDaemonClientTLSVolumeName,
+			MountPath: rblnDaemonClientTLSMountPath,
+			ReadOnly:  true,
+		},
+		{
+			Name:      rblnDaemonCAVolumeName,
+			MountPath: rblnDaemonCAMountPath,
+			ReadOnly:  true,
+		},
+	}
+}
+
 type rblnDaemonPatcher struct {
 	client client.Client
 	log    logr.Logger
@@ -44,6 +149,9 @@ type rblnDaemonPatcher struct {
 	name             string
 	namespace        string
 	openshiftVersion string
+
+	// clientNames are the operator components connecting to rbln-daemon
+	clientNames []string
 }
 
 func NewRBLNDaemonPatcher(client client.Client, log logr.Logger, namespace string, cpSpec *rblnv1beta1.RBLNClusterPolicySpec, scheme *runtime.Scheme, openshiftVersion string) (Patcher, error) {
@@ -55,6 +163,11 @@ func NewRBLNDaemonPatcher(client client.Client, log logr.Logger, namespace strin
 		name:             consts.RBLNDaemonName,
 		namespace:        namespace,
 		openshiftVersion: openshiftVersion,
+
+		clientNames: []string{
+			cpSpec.BaseName + "-" + consts.RBLNMetricExporterName,
+			cpSpec.BaseName + "-" + consts.RBLNFeatureDiscoveryName,
+		},
 	}
 
 	synced := syncSpec(cpSpec, cpSpec.RBLNDaemon)
@@ -69,6 +182,10 @@ func (h *rblnDaemonPatcher) IsEnabled() bool {
 	return h.desiredSpec.IsEnabled()
 }
 
+func (h *rblnDaemonPatcher) ManagementState() rblnv1beta1.ManagementState {
+	return componentManagementState(h.desiredSpec)
+}
+
 func (h *rblnDaemonPatcher) Patch(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	if !h.desiredSpec.IsEnabled() {
 		return nil
@@ -85,17 +202,43 @@ func (h *rblnDaemonPatcher) Patch(ctx context.Context, owner *rblnv1beta1.RBLNCl
 			return err
 		}
 	}
+	if tls := h.desiredSpec.TLS; tls != nil && tls.Enabled && tls.ComponentContract == "" {
+		return fmt.Errorf("rbln-daemon mTLS requires tls.componentContract, the TLS contract the rbln-daemon, NPU feature discovery and metrics exporter images implement")
+	}
+	if h.desiredSpec.IsTLSEnabled() {
+		if err := h.handleCertificates(ctx, owner); err != nil {
+			return err
+		}
+		if err := h.handleCABundle(ctx, owner); err != nil {
+			return err
+		}
+	} else if err := h.deleteTLSResources(ctx); err != nil {
+		return err
+	}
 	if err := h.handleDaemonSet(ctx, owner); err != nil {
 		return err
 	}
 	if err := h.handleService(ctx, owner); err != nil {
 		return err
 	}
+	if h.desiredSpec.IsNetworkPolicyEnabled() {
+		if err := h.handleNetworkPolicy(ctx, owner); err != nil {
+			return err
+		}
+	} else if err := h.deleteNetworkPolicy(ctx); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (h *rblnDaemonPatcher) CleanUp(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	h.log.Info("WARNING: RBLN Daemon is disabled. Remove all RBLN Daemon resources")
+	if err := h.deleteNetworkPolicy(ctx); err != nil {
+		return err
+	}
+	if err := h.deleteTLSResources(ctx); err != nil {
+		return err
+	}
 	if err := h.client.Delete(ctx, &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      h.name,
@@ -328,14 +471,14 @@ func (h *rblnDaemonPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1be
 		WithName(h.name).
 		WithImage(ComposeImageReference(h.desiredSpec.Registry, h.desiredSpec.Image), h.desiredSpec.Version, h.desiredSpec.ImagePullPolicy).
 		WithCommands([]string{rblnDaemonCommand}).
-		WithArgs(h.desiredSpec.Args).
+		WithArgs(slices.Concat(h.desiredSpec.Args, h.tlsArgs())).
 		WithEnvs(h.desiredSpec.Env).
 		WithResources(h.desiredSpec.Resources, "250m", "40Mi").
 		WithSecurityContext(&corev1.SecurityContext{
 			Privileged: ptr(true),
 			RunAsUser:  ptr(int64(0)),
 		}).
-		WithVolumeMounts([]corev1.VolumeMount{
+		WithVolumeMounts(append([]corev1.VolumeMount{
 			{
 				Name:      rblnDaemonVarRunVolumeName,
 				MountPath: rblnDaemonVarRunPath,
@@ -353,7 +496,7 @@ func (h *rblnDaemonPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1be
 				Name:      rblnDaemonLogVolumeName,
 				MountPath: rblnDaemonLogPath,
 			},
-		}).
+		}, h.tlsVolumeMounts()...)).
 		Build()
 	daemonContainer.Ports = []corev1.ContainerPort{
 		{
@@ -377,7 +520,7 @@ func (h *rblnDaemonPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1be
 					WithTolerations(h.desiredSpec.Tolerations).
 					WithImagePullSecrets(h.desiredSpec.ImagePullSecrets).
 					WithPriorityClassName(h.desiredSpec.PriorityClassName).
-					WithVolumes([]corev1.Volume{
+					WithVolumes(append([]corev1.Volume{
 						{
 							Name: validationsVolumeName,
 							VolumeSource: corev1.VolumeSource{
@@ -423,7 +566,7 @@ func (h *rblnDaemonPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1be
 								},
 							},
 						},
-					}).
+					}, h.tlsVolumes()...)).
 					WithInitContainers([]*corev1.Container{initContainer}).
 					WithContainers([]*corev1.Container{daemonContainer}).
 					WithTerminationGracePeriodSeconds(0).
@@ -441,3 +584,257 @@ func (h *rblnDaemonPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1be
 	h.log.Info("Reconciled RBLNDaemon DaemonSet", "namespace", ds.Namespace, "name", ds.Name, "result", dsRes)
 	return nil
 }
+
+func (h *rblnDaemonPatcher) handleCertificates(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	tlsSpec := h.desiredSpec.TLS
+	if h.serverSecretName() == "" || tlsSecretName(&tlsSpec.ClientCertificate, rblnDaemonClientSecretName) == "" {
+		return fmt.Errorf("rbln-daemon mTLS requires a secretName or a cert-manager issuer for both the server and client certificates")
+	}
+
+	if tlsSpec.ServerCertificate.CertManager != nil {
+		dnsNames := []string{
+			h.name,
+			fmt.Sprintf("%s.%s.svc", h.name, h.namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", h.name, h.namespace),
+		}
+		res, err := reconcileCertificate(ctx, h.client, h.scheme, owner, tlsSpec.ServerCertificate.CertManager, certificateRequest{
+			name:       h.name + "-server",
+			namespace:  h.namespace,
+			secretName: h.serverSecretName(),
+			commonName: dnsNames[1],
+			dnsNames:   dnsNames,
+			usages:     []string{"server auth"},
+		})
+		if err != nil {
+			h.log.Error(err, "Failed to reconcile RBLNDaemon server Certificate")
+			return err
+		}
+		h.log.Info("Reconciled RBLNDaemon server Certificate", "namespace", h.namespace, "name", h.name+"-server", "result", res)
+	} else if err := deleteIfKindAvailable(ctx, h.client, certificateGVK, h.name+"-server", h.namespace); err != nil {
+		return err
+	}
+
+	if tlsSpec.ClientCertificate.CertManager != nil {
+		res, err := reconcileCertificate(ctx, h.client, h.scheme, owner, tlsSpec.ClientCertificate.CertManager, certificateRequest{
+			name:       h.name + "-client",
+			namespace:  h.namespace,
+			secretName: tlsSecretName(&tlsSpec.ClientCertificate, rblnDaemonClientSecretName),
+			commonName: h.name + "-client",
+			dnsNames:   []string{h.name + "-client"},
+			usages:     []string{"client auth"},
+		})
+		if err != nil {
+			h.log.Error(err, "Failed to reconcile RBLNDaemon client Certificate")
+			return err
+		}
+		h.log.Info("Reconciled RBLNDaemon client Certificate", "namespace", h.namespace, "name", h.name+"-client", "result", res)
+	} else if err := deleteIfKindAvailable(ctx, h.client, certificateGVK, h.name+"-client", h.namespace); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleCABundle publishes the CA of the server certificate so that clients can verify rbln-daemon.
+// The same CA is the only one rbln-daemon accepts client certificates from, so the client
+// certificate is verified against it before the bundle is published.
+func (h *rblnDaemonPatcher) handleCABundle(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	secret := &corev1.Secret{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: h.serverSecretName(), Namespace: h.namespace}, secret); err != nil {
+		return fmt.Errorf("failed to get rbln-daemon server certificate secret %s/%s: %w", h.namespace, h.serverSecretName(), err)
+	}
+	ca, ok := secret.Data[tlsCAKey]
+	if !ok || len(ca) == 0 {
+		return fmt.Errorf("rbln-daemon server certificate secret %s/%s does not contain %s", h.namespace, h.serverSecretName(), tlsCAKey)
+	}
+
+	clientSecretName := tlsSecretName(&h.desiredSpec.TLS.ClientCertificate, rblnDaemonClientSecretName)
+	clientSecret := &corev1.Secret{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: clientSecretName, Namespace: h.namespace}, clientSecret); err != nil {
+		return fmt.Errorf("failed to get rbln-daemon client certificate secret %s/%s: %w", h.namespace, clientSecretName, err)
+	}
+	if err := verifyClientCertificate(ca, clientSecret.Data[tlsCertKey]); err != nil {
+		return fmt.Errorf("rbln-daemon client certificate secret %s/%s is not accepted by the %s of %s/%s: %w",
+			h.namespace, clientSecretName, tlsCAKey, h.namespace, h.serverSecretName(), err)
+	}
+
+	builder := k8sutil.NewConfigMapBuilder(rblnDaemonCABundleName, h.namespace)
+	cm := builder.Build()
+	cmRes, err := controllerutil.CreateOrPatch(ctx, h.client, cm, func() error {
+		cm = builder.
+			WithLabels(map[string]string{"app": h.name}).
+			WithData(map[string]string{
+				tlsCAKey: string(ca),
+			}).
+			WithOwner(owner, h.scheme).
+			Build()
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNDaemon CA bundle ConfigMap")
+		return err
+	}
+	h.log.Info("Reconciled RBLNDaemon CA bundle ConfigMap", "namespace", cm.Namespace, "name", cm.Name, "result", cmRes)
+	return nil
+}
+
+// verifyClientCertificate checks that the PEM encoded certificate chain is issued for client
+// authentication by one of the PEM encoded CAs.
+func verifyClientCertificate(caPEM, certPEM []byte) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("no CA certificate found")
+	}
+
+	var chain []*x509.Certificate
+	for rest := certPEM; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("invalid certificate: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return fmt.Errorf("no certificate found under %s", tlsCertKey)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
+
+func (h *rblnDaemonPatcher) deleteTLSResources(ctx context.Context) error {
+	if err := deleteIfKindAvailable(ctx, h.client, certificateGVK, h.name+"-server", h.namespace); err != nil {
+		return err
+	}
+	if err := deleteIfKindAvailable(ctx, h.client, certificateGVK, h.name+"-client", h.namespace); err != nil {
+		return err
+	}
+	if err := h.client.Delete(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rblnDaemonCABundleName,
+			Namespace: h.namespace,
+		},
+	}); err != nil && !kapierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (h *rblnDaemonPatcher) handleNetworkPolicy(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	builder := k8sutil.NewNetworkPolicyBuilder(h.name, h.namespace)
+	np := builder.Build()
+	npRes, err := controllerutil.CreateOrPatch(ctx, h.client, np, func() error {
+		np = builder.
+			WithLabels(map[string]string{"app": h.name}).
+			WithPodSelector(map[string]string{"app": h.name}).
+			WithIngressRules(h.buildIngressRule()).
+			WithOwner(owner, h.scheme).
+			Build()
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile RBLNDaemon NetworkPolicy")
+		return err
+	}
+	h.log.Info("Reconciled RBLNDaemon NetworkPolicy", "namespace", np.Namespace, "name", np.Name, "result", npRes)
+	return nil
+}
+
+func (h *rblnDaemonPatcher) buildIngressRule() networkingv1.NetworkPolicyIngressRule {
+	peers := []networkingv1.NetworkPolicyPeer{
+		{
+			PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      "app",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   h.clientNames,
+					},
+				},
+			},
+		},
+	}
+	peers = append(peers, h.desiredSpec.NetworkPolicy.AdditionalClients...)
+
+	return networkingv1.NetworkPolicyIngressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			{
+				Protocol: ptr(corev1.ProtocolTCP),
+				Port:     ptr(intstr.FromInt32(rblnDaemonDefaultHostPort)),
+			},
+		},
+		From: peers,
+	}
+}
+
+func (h *rblnDaemonPatcher) deleteNetworkPolicy(ctx context.Context) error {
+	if err := h.client.Delete(ctx, &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      h.name,
+			Namespace: h.namespace,
+		},
+	}); err != nil && !kapierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (h *rblnDaemonPatcher) serverSecretName() string {
+	return tlsSecretName(&h.desiredSpec.TLS.ServerCertificate, rblnDaemonServerSecretName)
+}
+
+func (h *rblnDaemonPatcher) tlsArgs() []string {
+	if !h.desiredSpec.IsTLSEnabled() {
+		return nil
+	}
+	return []string{
+		"--tls-cert-file=" + rblnDaemonServerTLSMountPath + "/" + tlsCertKey,
+		"--tls-key-file=" + rblnDaemonServerTLSMountPath + "/" + tlsKeyKey,
+		// clients are verified against the CA of the server certificate, see handleCABundle
+		"--tls-client-ca-file=" + rblnDaemonServerTLSMountPath + "/" + tlsCAKey,
+	}
+}
+
+func (h *rblnDaemonPatcher) tlsVolumes() []corev1.Volume {
+	if !h.desiredSpec.IsTLSEnabled() {
+		return nil
+	}
+	return []corev1.Volume{
+		{
+			Name: rblnDaemonServerTLSVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: h.serverSecretName(),
+				},
+			},
+		},
+	}
+}
+
+func (h *rblnDaemonPatcher) tlsVolumeMounts() []corev1.VolumeMount {
+	if !h.desiredSpec.IsTLSEnabled() {
+		return nil
+	}
+	return []corev1.VolumeMount{
+		{
+			Name:      rblnDaemonServerTLSVolumeName,
+			MountPath: rblnDaemonServerTLSMountPath,
+			ReadOnly:  true,
+		},
+	}
+}
@@ -0,0 +1,193 @@
+package patch
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+)
+
+// issueCertificate returns a PEM encoded certificate with the extended key usage, self-signed
+// when ca is nil. The key of the certificate is returned to sign further certificates with.
+func issueCertificate(cn string, usage x509.ExtKeyUsage, ca *x509.Certificate, caKey *ecdsa.PrivateKey) ([]byte, *x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	if ca == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		ca, caKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), cert, key
+}
+
+var _ = Describe("RBLNDaemonPatcher", func() {
+	var (
+		cpSpec       *rblnv1beta1.RBLNClusterPolicySpec
+		owner        *rblnv1beta1.RBLNClusterPolicy
+		scheme       *runtime.Scheme
+		serverSecret *corev1.Secret
+		clientSecret *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+
+		cpSpec = &rblnv1beta1.RBLNClusterPolicySpec{
+			BaseName: "rbln",
+			RBLNDaemon: rblnv1beta1.RBLNDaemonSpec{
+				Enabled:  true,
+				HostPort: 50052,
+				TLS: &rblnv1beta1.RBLNDaemonTLSSpec{
+					Enabled:           true,
+					ComponentContract: rblnv1beta1.RBLNDaemonTLSContractV1,
+					ServerCertificate: rblnv1beta1.TLSCertificateSpec{SecretName: "daemon-server"},
+					ClientCertificate: rblnv1beta1.TLSCertificateSpec{SecretName: "daemon-client"},
+				},
+				NetworkPolicy: &rblnv1beta1.RBLNDaemonNetworkPolicySpec{Enabled: true},
+			},
+		}
+		owner = &rblnv1beta1.RBLNClusterPolicy{}
+		owner.SetName("rbln-cluster-policy")
+		owner.SetUID(types.UID("uid"))
+
+		caPEM, ca, caKey := issueCertificate("rbln-ca", x509.ExtKeyUsageAny, nil, nil)
+		serverPEM, _, _ := issueCertificate("rbln-daemon", x509.ExtKeyUsageServerAuth, ca, caKey)
+		clientPEM, _, _ := issueCertificate("rbln-daemon-client", x509.ExtKeyUsageClientAuth, ca, caKey)
+		serverSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "daemon-server", Namespace: "rbln-system"},
+			Data: map[string][]byte{
+				tlsCertKey: serverPEM,
+				tlsKeyKey:  []byte("key"),
+				tlsCAKey:   caPEM,
+			},
+		}
+		clientSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "daemon-client", Namespace: "rbln-system"},
+			Data: map[string][]byte{
+				tlsCertKey: clientPEM,
+				tlsKeyKey:  []byte("key"),
+			},
+		}
+	})
+
+	Describe("newRBLNDaemonClient", func() {
+		It("should connect over TLS on the configured host port", func() {
+			c := newRBLNDaemonClient(cpSpec, "rbln-system")
+			Expect(c.url()).To(Equal("https://$(NODE_IP):50052"))
+			Expect(c.serverName).To(Equal("rbln-daemon.rbln-system.svc"))
+			Expect(c.volumes()).To(ContainElement(HaveField("VolumeSource.Secret.SecretName", "daemon-client")))
+			Expect(c.volumes()).To(ContainElement(HaveField("VolumeSource.ConfigMap.Name", rblnDaemonCABundleName)))
+		})
+
+		It("should use plain HTTP when the daemon is disabled", func() {
+			cpSpec.RBLNDaemon.Enabled = false
+			c := newRBLNDaemonClient(cpSpec, "rbln-system")
+			Expect(c.url()).To(Equal("http://$(NODE_IP):50052"))
+			Expect(c.volumes()).To(BeEmpty())
+		})
+
+		It("should use plain HTTP until the components implement a TLS contract", func() {
+			cpSpec.RBLNDaemon.TLS.ComponentContract = ""
+			c := newRBLNDaemonClient(cpSpec, "rbln-system")
+			Expect(c.url()).To(Equal("http://$(NODE_IP):50052"))
+			Expect(c.volumes()).To(BeEmpty())
+		})
+	})
+
+	Describe("Patch", func() {
+		It("should publish the CA bundle and restrict access with a NetworkPolicy", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(serverSecret, clientSecret).Build()
+			patcher, err := NewRBLNDaemonPatcher(c, logr.Discard(), "rbln-system", cpSpec, scheme, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(Succeed())
+
+			cm := &corev1.ConfigMap{}
+			Expect(c.Get(context.Background(), types.NamespacedName{Name: rblnDaemonCABundleName, Namespace: "rbln-system"}, cm)).To(Succeed())
+			Expect(cm.Data).To(HaveKeyWithValue(tlsCAKey, string(serverSecret.Data[tlsCAKey])))
+
+			np := &networkingv1.NetworkPolicy{}
+			Expect(c.Get(context.Background(), types.NamespacedName{Name: "rbln-daemon", Namespace: "rbln-system"}, np)).To(Succeed())
+			Expect(np.Spec.Ingress).To(HaveLen(1))
+			Expect(np.Spec.Ingress[0].From[0].PodSelector.MatchExpressions[0].Values).To(ConsistOf("rbln-metrics-exporter", "rbln-npu-feature-discovery"))
+		})
+
+		It("should not configure the daemon for mTLS without a TLS contract", func() {
+			cpSpec.RBLNDaemon.TLS.ComponentContract = ""
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(serverSecret, clientSecret).Build()
+			patcher, err := NewRBLNDaemonPatcher(c, logr.Discard(), "rbln-system", cpSpec, scheme, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).To(MatchError(ContainSubstring("tls.componentContract")))
+			Expect(c.Get(context.Background(), types.NamespacedName{Name: "rbln-daemon", Namespace: "rbln-system"}, &appsv1.DaemonSet{})).NotTo(Succeed())
+		})
+
+		It("should fail when the server certificate has no CA", func() {
+			delete(serverSecret.Data, tlsCAKey)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(serverSecret, clientSecret).Build()
+			patcher, err := NewRBLNDaemonPatcher(c, logr.Discard(), "rbln-system", cpSpec, scheme, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = patcher.Patch(context.Background(), owner)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not contain ca.crt"))
+		})
+
+		It("should fail when the client certificate is not issued by the server CA", func() {
+			_, otherCA, otherKey := issueCertificate("other-ca", x509.ExtKeyUsageAny, nil, nil)
+			clientSecret.Data[tlsCertKey], _, _ = issueCertificate("rbln-daemon-client", x509.ExtKeyUsageClientAuth, otherCA, otherKey)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(serverSecret, clientSecret).Build()
+			patcher, err := NewRBLNDaemonPatcher(c, logr.Discard(), "rbln-system", cpSpec, scheme, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = patcher.Patch(context.Background(), owner)
+			Expect(err).To(MatchError(ContainSubstring("is not accepted by the ca.crt of rbln-system/daemon-server")))
+			Expect(c.Get(context.Background(), types.NamespacedName{Name: rblnDaemonCABundleName, Namespace: "rbln-system"}, &corev1.ConfigMap{})).NotTo(Succeed())
+		})
+
+		It("should fail when the client certificate is not issued for client authentication", func() {
+			_, ca, caKey := issueCertificate("rbln-ca", x509.ExtKeyUsageAny, nil, nil)
+			serverSecret.Data[tlsCAKey] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})
+			clientSecret.Data[tlsCertKey], _, _ = issueCertificate("rbln-daemon-client", x509.ExtKeyUsageServerAuth, ca, caKey)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(serverSecret, clientSecret).Build()
+			patcher, err := NewRBLNDaemonPatcher(c, logr.Discard(), "rbln-system", cpSpec, scheme, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(patcher.Patch(context.Background(), owner)).NotTo(Succeed())
+		})
+	})
+})
@@ -0,0 +1,110 @@
+package patch
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/nodetaint"
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+const defaultRuntimeClassName = "rbln"
+
+func (h *containerToolkitPatcher) handleRuntimeClass(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
+	if !h.desiredSpec.IsRuntimeClassEnabled() {
+		return h.deleteRuntimeClasses(ctx, "")
+	}
+
+	name := h.runtimeClassName()
+	handler := h.runtimeHandler()
+
+	// the handler of a RuntimeClass is immutable
+	existing := &nodev1.RuntimeClass{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: name}, existing); err == nil {
+		if existing.Handler != handler {
+			h.log.Info("Recreating Container Toolkit RuntimeClass with a new handler", "name", name, "handler", handler)
+			if err := h.client.Delete(ctx, existing); err != nil && !kapierrors.IsNotFound(err) {
+				return err
+			}
+		}
+	} else if !kapierrors.IsNotFound(err) {
+		return err
+	}
+
+	// the RuntimeClass only runs on NPU nodes, so its pods tolerate their taint
+	var tolerations []corev1.Toleration
+	if h.nodeTaint {
+		tolerations = []corev1.Toleration{nodetaint.Toleration}
+	}
+
+	builder := k8sutil.NewRuntimeClassBuilder(name)
+	rc := builder.Build()
+	rcRes, err := controllerutil.CreateOrPatch(ctx, h.client, rc, func() error {
+		rc = builder.
+			WithLabels(map[string]string{"app": h.name}).
+			WithHandler(handler).
+			WithNodeSelector(map[string]string{containerToolkitDeployLabelKey: "true"}).
+			WithTolerations(tolerations).
+			WithOwner(owner, h.scheme).
+			Build()
+		return nil
+	})
+	if err != nil {
+		h.log.Error(err, "Failed to reconcile Container Toolkit RuntimeClass")
+		return err
+	}
+	h.log.Info("Reconciled Container Toolkit RuntimeClass", "name", rc.Name, "handler", rc.Handler, "result", rcRes)
+
+	// drop the RuntimeClass left behind by a previous name
+	return h.deleteRuntimeClasses(ctx, name)
+}
+
+// deleteRuntimeClasses removes RuntimeClasses created for the container toolkit, except keep.
+func (h *containerToolkitPatcher) deleteRuntimeClasses(ctx context.Context, keep string) error {
+	rcList := &nodev1.RuntimeClassList{}
+	if err := h.client.List(ctx, rcList, client.MatchingLabels{"app": h.name}); err != nil {
+		return err
+	}
+	for _, rc := range rcList.Items {
+		if rc.Name == keep {
+			continue
+		}
+		if err := h.client.Delete(ctx, &rc); err != nil && !kapierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// runtimeHandlerEnv returns the environment variables telling rbln-ctk-daemon to register a named
+// runtime handler rather than changing the default runtime.
+func (h *containerToolkitPatcher) runtimeHandlerEnv() []corev1.EnvVar {
+	if !h.desiredSpec.IsRuntimeClassEnabled() {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{Name: containerToolkitRuntimeHandlerEnv, Value: h.runtimeHandler()},
+		{Name: containerToolkitSetAsDefaultEnv, Value: "false"},
+	}
+}
+
+func (h *containerToolkitPatcher) runtimeClassName() string {
+	if h.desiredSpec.RuntimeClass.Name != "" {
+		return h.desiredSpec.RuntimeClass.Name
+	}
+	return defaultRuntimeClassName
+}
+
+func (h *containerToolkitPatcher) runtimeHandler() string {
+	if h.desiredSpec.RuntimeClass.Handler != "" {
+		return h.desiredSpec.RuntimeClass.Handler
+	}
+	return defaultRuntimeClassName
+}
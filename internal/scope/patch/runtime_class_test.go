@@ -0,0 +1,98 @@
+package patch
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/nodetaint"
+)
+
+var _ = Describe("ContainerToolkitRuntimeClass", func() {
+	var (
+		patcher *containerToolkitPatcher
+		owner   *rblnv1beta1.RBLNClusterPolicy
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+
+		owner = &rblnv1beta1.RBLNClusterPolicy{}
+		owner.SetName("rbln-cluster-policy")
+		owner.SetUID(types.UID("uid"))
+
+		patcher = &containerToolkitPatcher{
+			client:    fake.NewClientBuilder().WithScheme(scheme).Build(),
+			log:       logr.Discard(),
+			scheme:    scheme,
+			name:      "rbln-container-toolkit",
+			namespace: "rbln-system",
+			desiredSpec: &rblnv1beta1.RBLNContainerToolkitSpec{
+				Enabled:      true,
+				RuntimeClass: &rblnv1beta1.RuntimeClassSpec{Enabled: true},
+			},
+		}
+	})
+
+	It("should create a RuntimeClass scheduled on container toolkit nodes", func() {
+		Expect(patcher.handleRuntimeClass(context.Background(), owner)).To(Succeed())
+
+		rc := &nodev1.RuntimeClass{}
+		Expect(patcher.client.Get(context.Background(), types.NamespacedName{Name: "rbln"}, rc)).To(Succeed())
+		Expect(rc.Handler).To(Equal("rbln"))
+		Expect(rc.Scheduling.NodeSelector).To(HaveKeyWithValue(containerToolkitDeployLabelKey, "true"))
+		Expect(rc.Scheduling.Tolerations).To(BeEmpty())
+		Expect(rc.GetOwnerReferences()).To(HaveLen(1))
+		Expect(patcher.runtimeHandlerEnv()).To(Equal([]corev1.EnvVar{
+			{Name: "RBLN_CTK_DAEMON_RUNTIME_HANDLER", Value: "rbln"},
+			{Name: "RBLN_CTK_DAEMON_SET_AS_DEFAULT", Value: "false"},
+		}))
+	})
+
+	It("should tolerate the NPU node taint when enabled", func() {
+		patcher.nodeTaint = true
+		Expect(patcher.handleRuntimeClass(context.Background(), owner)).To(Succeed())
+
+		rc := &nodev1.RuntimeClass{}
+		Expect(patcher.client.Get(context.Background(), types.NamespacedName{Name: "rbln"}, rc)).To(Succeed())
+		Expect(rc.Scheduling.Tolerations).To(ConsistOf(nodetaint.Toleration))
+	})
+
+	It("should replace the RuntimeClass when the name or handler changes", func() {
+		Expect(patcher.handleRuntimeClass(context.Background(), owner)).To(Succeed())
+
+		patcher.desiredSpec.RuntimeClass.Name = "rbln-npu"
+		patcher.desiredSpec.RuntimeClass.Handler = "rbln-npu"
+		Expect(patcher.handleRuntimeClass(context.Background(), owner)).To(Succeed())
+
+		rcList := &nodev1.RuntimeClassList{}
+		Expect(patcher.client.List(context.Background(), rcList)).To(Succeed())
+		Expect(rcList.Items).To(ConsistOf(And(
+			HaveField("Name", "rbln-npu"),
+			HaveField("Handler", "rbln-npu"),
+		)))
+	})
+
+	It("should remove the RuntimeClass when disabled", func() {
+		Expect(patcher.handleRuntimeClass(context.Background(), owner)).To(Succeed())
+
+		patcher.desiredSpec.RuntimeClass.Enabled = false
+		Expect(patcher.handleRuntimeClass(context.Background(), owner)).To(Succeed())
+		Expect(patcher.runtimeHandlerEnv()).To(BeEmpty())
+
+		rcList := &nodev1.RuntimeClassList{}
+		Expect(patcher.client.List(context.Background(), rcList)).To(Succeed())
+		Expect(rcList.Items).To(BeEmpty())
+	})
+})
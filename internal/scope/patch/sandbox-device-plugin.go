@@ -27,10 +27,11 @@ type sandboxDevicePluginPatcher struct {
 	log    logr.Logger
 	scheme *runtime.Scheme
 
-	desiredSpec      *rblnv1beta1.RBLNSandboxDevicePluginSpec
-	name             string
-	namespace        string
-	openshiftVersion string
+	desiredSpec        *rblnv1beta1.RBLNSandboxDevicePluginSpec
+	name               string
+	namespace          string
+	openshiftVersion   string
+	deviceListStrategy deviceListStrategy
 }
 
 func NewSandboxDevicePluginPatcher(client client.Client, log logr.Logger, namespace string, cpSpec *rblnv1beta1.RBLNClusterPolicySpec, scheme *runtime.Scheme, openshiftVersion string) (Patcher, error) {
@@ -39,9 +40,10 @@ func NewSandboxDevicePluginPatcher(client client.Client, log logr.Logger, namesp
 		log:    log,
 		scheme: scheme,
 
-		name:             cpSpec.BaseName + "-" + consts.RBLNSandboxDevicePluginName,
-		namespace:        namespace,
-		openshiftVersion: openshiftVersion,
+		name:               cpSpec.BaseName + "-" + consts.RBLNSandboxDevicePluginName,
+		namespace:          namespace,
+		openshiftVersion:   openshiftVersion,
+		deviceListStrategy: newDeviceListStrategy(cpSpec),
 	}
 
 	if cpSpec.SandboxDevicePlugin.IsEnabled() {
@@ -60,6 +62,10 @@ func (h *sandboxDevicePluginPatcher) IsEnabled() bool {
 	return h.desiredSpec.IsEnabled()
 }
 
+func (h *sandboxDevicePluginPatcher) ManagementState() rblnv1beta1.ManagementState {
+	return componentManagementState(h.desiredSpec)
+}
+
 func (h *sandboxDevicePluginPatcher) Patch(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	if !h.desiredSpec.IsEnabled() {
 		return nil
@@ -470,7 +476,7 @@ echo "VFIO-PCI binding check completed."`,
 						WithName(h.name).
 						WithImage(ComposeImageReference(h.desiredSpec.Registry, h.desiredSpec.Image), h.desiredSpec.Version, h.desiredSpec.ImagePullPolicy).
 						WithCommands([]string{"/usr/bin/sriovdp"}).
-						WithArgs([]string{"-v=10", "--logtostderr", "--use-cdi=false"}).
+						WithArgs(h.deviceListStrategy.pluginArgs()).
 						WithResources(h.desiredSpec.Resources, "250m", "40Mi").
 						WithVolumeMounts([]corev1.VolumeMount{
 							{
@@ -6,6 +6,9 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
 	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
@@ -15,6 +18,16 @@ import (
 // ComponentSpec defines the common interface for component specs.
 type ComponentSpec interface {
 	IsEnabled() bool
+	GetManagementState() rblnv1beta1.ManagementState
+}
+
+// componentManagementState returns the management state of a synced component spec.
+// Specs of disabled components are zeroed by syncSpec, so they are Removed.
+func componentManagementState[T ComponentSpec](spec *T) rblnv1beta1.ManagementState {
+	if spec == nil {
+		return rblnv1beta1.ManagementStateRemoved
+	}
+	return (*spec).GetManagementState()
 }
 
 type deviceSelector struct {
@@ -85,3 +98,14 @@ func collectDevices(productCardNames []string) ([]string, error) {
 	}
 	return devices, nil
 }
+
+// isKindAvailable reports whether the API server serves the given kind, i.e. whether an optional CRD is installed.
+func isKindAvailable(c client.Client, gvk schema.GroupVersionKind) (bool, error) {
+	if _, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
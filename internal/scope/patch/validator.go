@@ -33,6 +33,11 @@ const (
 	validatorHostDriverPath       = "/run/rbln/driver"
 	validatorCDIRootVolumeName    = "cdi-root"
 	validatorCDIRootPath          = "/var/run/cdi"
+	validatorHostSysVolumeName    = "host-sys"
+	validatorHostSysPath          = "/sys"
+
+	validatorComponentHealth     = "health"
+	defaultHealthIntervalSeconds = 30
 )
 
 type validatorPatcher struct {
@@ -40,11 +45,13 @@ type validatorPatcher struct {
 	log    logr.Logger
 	scheme *runtime.Scheme
 
-	desiredSpec      *rblnv1beta1.ValidatorSpec
-	name             string
-	namespace        string
-	openshiftVersion string
-	daemonsets       *rblnv1beta1.DaemonsetsSpec
+	desiredSpec        *rblnv1beta1.ValidatorSpec
+	name               string
+	namespace          string
+	openshiftVersion   string
+	daemonsets         *rblnv1beta1.DaemonsetsSpec
+	deviceListStrategy deviceListStrategy
+	healthMonitor      *rblnv1beta1.RBLNHealthMonitorSpec
 }
 
 func NewValidatorPatcher(client client.Client, log logr.Logger, namespace string, cpSpec *rblnv1beta1.RBLNClusterPolicySpec, scheme *runtime.Scheme, openshiftVersion string) (Patcher, error) {
@@ -53,13 +60,17 @@ func NewValidatorPatcher(client client.Client, log logr.Logger, namespace string
 		log:    log,
 		scheme: scheme,
 
-		name:             cpSpec.BaseName + "-" + consts.RBLNValidatorName,
-		namespace:        namespace,
-		openshiftVersion: openshiftVersion,
-		daemonsets:       cpSpec.Daemonsets,
+		name:               cpSpec.BaseName + "-" + consts.RBLNValidatorName,
+		namespace:          namespace,
+		openshiftVersion:   openshiftVersion,
+		daemonsets:         cpSpec.Daemonsets,
+		deviceListStrategy: newDeviceListStrategy(cpSpec),
 	}
 
 	patcher.desiredSpec = &cpSpec.Validator
+	if cpSpec.IsHealthMonitorEnabled() {
+		patcher.healthMonitor = cpSpec.HealthMonitor
+	}
 	return patcher, nil
 }
 
@@ -67,6 +78,13 @@ func (h *validatorPatcher) IsEnabled() bool {
 	return h.desiredSpec != nil
 }
 
+func (h *validatorPatcher) ManagementState() rblnv1beta1.ManagementState {
+	if h.desiredSpec == nil {
+		return rblnv1beta1.ManagementStateRemoved
+	}
+	return h.desiredSpec.GetManagementState()
+}
+
 func (h *validatorPatcher) Patch(ctx context.Context, owner *rblnv1beta1.RBLNClusterPolicy) error {
 	if !h.IsEnabled() {
 		return nil
@@ -81,6 +99,9 @@ func (h *validatorPatcher) Patch(ctx context.Context, owner *rblnv1beta1.RBLNClu
 	if err := h.handleRoleBinding(ctx, owner); err != nil {
 		return err
 	}
+	if err := h.handleAdmissionPolicy(ctx); err != nil {
+		return err
+	}
 	if err := h.handleClusterRole(ctx); err != nil {
 		return err
 	}
@@ -118,6 +139,9 @@ func (h *validatorPatcher) CleanUp(ctx context.Context, owner *rblnv1beta1.RBLNC
 	}); err != nil && !kapierrors.IsNotFound(err) {
 		return err
 	}
+	if err := h.deleteAdmissionPolicy(ctx); err != nil {
+		return err
+	}
 	if err := h.client.Delete(ctx, &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      h.name,
@@ -296,13 +320,27 @@ func (h *validatorPatcher) handleClusterRole(ctx context.Context) error {
 	}
 
 	roleRes, err := controllerutil.CreateOrPatch(ctx, h.client, role, func() error {
+		nodeVerbs := []string{"get", "list", "watch"}
+		if h.healthMonitor != nil {
+			// the health watcher publishes its report as a node annotation;
+			// handleAdmissionPolicy limits the patches to its own node
+			nodeVerbs = append(nodeVerbs, "patch")
+		}
 		role.Rules = []rbacv1.PolicyRule{
 			{
 				APIGroups: []string{""},
 				Resources: []string{"nodes"},
-				Verbs:     []string{"get", "list", "watch"},
+				Verbs:     nodeVerbs,
 			},
 		}
+		if h.healthMonitor != nil {
+			// the health watcher checks that a node is drained before resetting its devices
+			role.Rules = append(role.Rules, rbacv1.PolicyRule{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"list"},
+			})
+		}
 		return nil
 	})
 	if err != nil {
@@ -381,8 +419,10 @@ func (h *validatorPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1bet
 		baseEnv,
 		validatorSpec.Driver.Env,
 	)
+	// the toolkit validation only checks CDI specs when devices are injected through CDI
 	toolkitEnv := mergeEnvVars(
 		baseEnv,
+		h.deviceListStrategy.env(deviceListStrategyEnvName),
 		validatorSpec.Toolkit.Env,
 	)
 	driverInit := k8sutil.NewContainerBuilder().
@@ -446,9 +486,6 @@ func (h *validatorPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1bet
 	mainContainerBuilder := k8sutil.NewContainerBuilder().
 		WithName(h.name).
 		WithImage(validatorImage, validatorSpec.Version, imagePullPolicy).
-		WithCommands([]string{"sh", "-c"}).
-		WithArgs([]string{"echo all validations are successful; while true; do sleep 86400; done"}).
-		WithEnvs(baseEnv).
 		WithSecurityContext(&corev1.SecurityContext{
 			Privileged: ptr(true),
 			RunAsUser:  ptr(int64(0)),
@@ -459,14 +496,51 @@ func (h *validatorPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1bet
 					Command: []string{"sh", "-c", "rm -f " + validationsMountPath + "/*-ready"},
 				},
 			},
-		}).
-		WithVolumeMounts([]corev1.VolumeMount{
-			{
-				Name:             validationsVolumeName,
-				MountPath:        validationsMountPath,
-				MountPropagation: ptr(corev1.MountPropagationBidirectional),
-			},
 		})
+	if h.healthMonitor != nil {
+		// the main container keeps watching NPU health once all validations passed
+		mainContainerBuilder.
+			WithCommands([]string{validatorDefaultCommand}).
+			WithArgs(h.healthArgs(validatorSpec.Args)).
+			WithEnvs(mergeEnvVars(baseEnv, []corev1.EnvVar{
+				{
+					Name: "NODE_NAME",
+					ValueFrom: &corev1.EnvVarSource{
+						FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: "spec.nodeName",
+						},
+					},
+				},
+			})).
+			WithVolumeMounts([]corev1.VolumeMount{
+				{
+					Name:             validationsVolumeName,
+					MountPath:        validationsMountPath,
+					MountPropagation: ptr(corev1.MountPropagationBidirectional),
+				},
+				{
+					Name:      validatorHostSysVolumeName,
+					MountPath: "/host-sys",
+				},
+				{
+					Name:      hostUsrBinVolumeName,
+					MountPath: "/host-usr-bin",
+					ReadOnly:  true,
+				},
+			})
+	} else {
+		mainContainerBuilder.
+			WithCommands([]string{"sh", "-c"}).
+			WithArgs([]string{"echo all validations are successful; while true; do sleep 86400; done"}).
+			WithEnvs(baseEnv).
+			WithVolumeMounts([]corev1.VolumeMount{
+				{
+					Name:             validationsVolumeName,
+					MountPath:        validationsMountPath,
+					MountPropagation: ptr(corev1.MountPropagationBidirectional),
+				},
+			})
+	}
 	if validatorSpec.Resources != nil {
 		mainContainerBuilder.WithResources(*validatorSpec.Resources, "250m", "40Mi")
 	}
@@ -484,75 +558,98 @@ func (h *validatorPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1bet
 		tolerations = h.daemonsets.Tolerations
 		priorityClassName = h.daemonsets.PriorityClassName
 	}
+	if h.healthMonitor != nil {
+		// keep the health watcher schedulable on tainted nodes so it can report their recovery
+		tolerations = append(slices.Clone(tolerations), corev1.Toleration{
+			Key:      consts.RBLNNPUUnhealthyTaintKey,
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+
+	volumes := []corev1.Volume{
+		{
+			Name: validationsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: validationsMountPath,
+					Type: ptr(corev1.HostPathDirectoryOrCreate),
+				},
+			},
+		},
+		{
+			Name: validatorHostDriverVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: validatorHostDriverPath,
+					Type: ptr(corev1.HostPathDirectoryOrCreate),
+				},
+			},
+		},
+		{
+			Name: validatorHostRootVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: validatorHostRootPath,
+					Type: ptr(corev1.HostPathDirectory),
+				},
+			},
+		},
+		{
+			Name: hostUsrBinVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: hostUsrBinPath,
+					Type: ptr(corev1.HostPathDirectory),
+				},
+			},
+		},
+		{
+			Name: validatorCDIRootVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: validatorCDIRootPath,
+					Type: ptr(corev1.HostPathDirectoryOrCreate),
+				},
+			},
+		},
+	}
+	if h.healthMonitor != nil {
+		// the health watcher scans PCI devices and resets them on remediation requests
+		volumes = append(volumes, corev1.Volume{
+			Name: validatorHostSysVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: validatorHostSysPath,
+					Type: ptr(corev1.HostPathDirectory),
+				},
+			},
+		})
+	}
+
+	podSpec := k8sutil.NewPodSpecBuilder().
+		WithServiceAccountName(h.name).
+		WithNodeSelector(map[string]string{"rebellions.ai/npu.deploy.operator-validator": "true"}).
+		WithAffinity(affinity).
+		WithTolerations(tolerations).
+		WithImagePullSecrets(validatorSpec.ImagePullSecrets).
+		WithPriorityClassName(priorityClassName).
+		WithVolumes(volumes).
+		WithInitContainers([]*corev1.Container{
+			driverInit,
+			toolkitInit,
+		}).
+		WithContainers([]*corev1.Container{
+			mainContainer,
+		}).
+		Build()
 
 	dsRes, err := controllerutil.CreateOrPatch(ctx, h.client, ds, func() error {
 		ds = builder.
 			WithLabelSelectors(map[string]string{"app": h.name}).
 			WithLabels(labels).
 			WithAnnotations(annotations).
-			WithPodSpec(k8sutil.NewPodSpecBuilder().
-				WithServiceAccountName(h.name).
-				WithNodeSelector(map[string]string{"rebellions.ai/npu.deploy.operator-validator": "true"}).
-				WithAffinity(affinity).
-				WithTolerations(tolerations).
-				WithImagePullSecrets(validatorSpec.ImagePullSecrets).
-				WithPriorityClassName(priorityClassName).
-				WithVolumes([]corev1.Volume{
-					{
-						Name: validationsVolumeName,
-						VolumeSource: corev1.VolumeSource{
-							HostPath: &corev1.HostPathVolumeSource{
-								Path: validationsMountPath,
-								Type: ptr(corev1.HostPathDirectoryOrCreate),
-							},
-						},
-					},
-					{
-						Name: validatorHostDriverVolumeName,
-						VolumeSource: corev1.VolumeSource{
-							HostPath: &corev1.HostPathVolumeSource{
-								Path: validatorHostDriverPath,
-								Type: ptr(corev1.HostPathDirectoryOrCreate),
-							},
-						},
-					},
-					{
-						Name: validatorHostRootVolumeName,
-						VolumeSource: corev1.VolumeSource{
-							HostPath: &corev1.HostPathVolumeSource{
-								Path: validatorHostRootPath,
-								Type: ptr(corev1.HostPathDirectory),
-							},
-						},
-					},
-					{
-						Name: hostUsrBinVolumeName,
-						VolumeSource: corev1.VolumeSource{
-							HostPath: &corev1.HostPathVolumeSource{
-								Path: hostUsrBinPath,
-								Type: ptr(corev1.HostPathDirectory),
-							},
-						},
-					},
-					{
-						Name: validatorCDIRootVolumeName,
-						VolumeSource: corev1.VolumeSource{
-							HostPath: &corev1.HostPathVolumeSource{
-								Path: validatorCDIRootPath,
-								Type: ptr(corev1.HostPathDirectoryOrCreate),
-							},
-						},
-					},
-				}).
-				WithInitContainers([]*corev1.Container{
-					driverInit,
-					toolkitInit,
-				}).
-				WithContainers([]*corev1.Container{
-					mainContainer,
-				}).
-				Build(),
-			).
+			WithPodSpec(podSpec).
 			WithOwner(owner, h.scheme).
 			Build()
 		return nil
@@ -566,6 +663,15 @@ func (h *validatorPatcher) handleDaemonSet(ctx context.Context, owner *rblnv1bet
 	return nil
 }
 
+func (h *validatorPatcher) healthArgs(baseArgs []string) []string {
+	interval := h.healthMonitor.IntervalSeconds
+	if interval <= 0 {
+		interval = defaultHealthIntervalSeconds
+	}
+	args := validatorComponentArgs(baseArgs, validatorComponentHealth, false)
+	return append(args, fmt.Sprintf("--sleep-interval-seconds=%d", interval))
+}
+
 func validatorComponentArgs(baseArgs []string, component string, withWait bool) []string {
 	args := []string{component}
 	if withWait {
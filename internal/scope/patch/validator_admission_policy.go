@@ -0,0 +1,207 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/rebellions-sw/rbln-npu-operator/internal/npuhealth"
+)
+
+// serviceAccountNodeNameKey is the user info extra the API server sets from the node of the
+// pod a service account token is bound to (ServiceAccountTokenPodNodeInfo, Kubernetes 1.30+).
+const serviceAccountNodeNameKey = "authentication.kubernetes.io/node-name"
+
+const (
+	operatorNamespaceEnv      = "OPERATOR_NAMESPACE"
+	operatorServiceAccountEnv = "OPERATOR_SERVICE_ACCOUNT"
+)
+
+var (
+	validatingAdmissionPolicyGVK        = admissionregistrationv1.SchemeGroupVersion.WithKind("ValidatingAdmissionPolicy")
+	validatingAdmissionPolicyBindingGVK = admissionregistrationv1.SchemeGroupVersion.WithKind("ValidatingAdmissionPolicyBinding")
+)
+
+// handleAdmissionPolicy confines the node writes of the health watcher. RBAC cannot scope
+// node patches to a node, so a ValidatingAdmissionPolicy only admits updates of the health
+// annotations of the node the validator pod runs on. A second policy reserves the reset
+// requests the watcher acts on to the operator.
+func (h *validatorPatcher) handleAdmissionPolicy(ctx context.Context) error {
+	if h.healthMonitor == nil {
+		return h.deleteAdmissionPolicy(ctx)
+	}
+
+	available, err := isKindAvailable(h.client, validatingAdmissionPolicyGVK)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return fmt.Errorf("the health monitor requires the %s API (Kubernetes 1.30+)", validatingAdmissionPolicyGVK.GroupVersion())
+	}
+
+	operator, err := operatorUsername()
+	if err != nil {
+		return err
+	}
+	healthPolicy := buildNodeAnnotationPolicySpec(serviceAccountUsername(h.namespace, h.name), "validator",
+		[]string{npuhealth.AnnotationKey, npuhealth.ResetCompletedAnnotationKey})
+	if err := applyAdmissionPolicy(ctx, h.client, h.log, h.name, healthPolicy); err != nil {
+		return err
+	}
+	return applyAdmissionPolicy(ctx, h.client, h.log, h.resetRequestPolicyName(), buildResetRequestPolicySpec(operator))
+}
+
+func applyAdmissionPolicy(ctx context.Context, c client.Client, log logr.Logger, name string, spec admissionregistrationv1.ValidatingAdmissionPolicySpec) error {
+	policy := &admissionregistrationv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	policyRes, err := controllerutil.CreateOrPatch(ctx, c, policy, func() error {
+		policy.Spec = spec
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to reconcile ValidatingAdmissionPolicy", "name", name)
+		return err
+	}
+	log.Info("Reconciled ValidatingAdmissionPolicy", "name", policy.Name, "result", policyRes)
+
+	binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	bindingRes, err := controllerutil.CreateOrPatch(ctx, c, binding, func() error {
+		binding.Spec.PolicyName = name
+		binding.Spec.ValidationActions = []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny}
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to reconcile ValidatingAdmissionPolicyBinding", "name", name)
+		return err
+	}
+	log.Info("Reconciled ValidatingAdmissionPolicyBinding", "name", binding.Name, "result", bindingRes)
+	return nil
+}
+
+// buildNodeAnnotationPolicySpec only admits the node updates of user that change the given
+// annotations of the node its pod runs on. component names user in the denial messages.
+func buildNodeAnnotationPolicySpec(user, component string, keys []string) admissionregistrationv1.ValidatingAdmissionPolicySpec {
+	failurePolicy := admissionregistrationv1.Fail
+	quoted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		quoted = append(quoted, "'"+key+"'")
+	}
+
+	return admissionregistrationv1.ValidatingAdmissionPolicySpec{
+		FailurePolicy: &failurePolicy,
+		MatchConstraints: &admissionregistrationv1.MatchResources{
+			ResourceRules: []admissionregistrationv1.NamedRuleWithOperations{{
+				RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Update},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{""},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"nodes"},
+					},
+				},
+			}},
+		},
+		MatchConditions: []admissionregistrationv1.MatchCondition{{
+			Name:       component,
+			Expression: fmt.Sprintf("request.userInfo.username == '%s'", user),
+		}},
+		Variables: []admissionregistrationv1.Variable{
+			{Name: "keys", Expression: "[" + strings.Join(quoted, ", ") + "]"},
+			{Name: "annotations", Expression: "has(object.metadata.annotations) ? object.metadata.annotations : {}"},
+			{Name: "oldAnnotations", Expression: "has(oldObject.metadata.annotations) ? oldObject.metadata.annotations : {}"},
+		},
+		Validations: []admissionregistrationv1.Validation{
+			{
+				Expression: fmt.Sprintf("'%[1]s' in request.userInfo.extra && object.metadata.name in request.userInfo.extra['%[1]s']", serviceAccountNodeNameKey),
+				Message:    "the " + component + " may only update the node its pod runs on",
+			},
+			{
+				Expression: "object.spec == oldObject.spec && " +
+					"(has(object.metadata.labels) ? object.metadata.labels : {}) == (has(oldObject.metadata.labels) ? oldObject.metadata.labels : {})",
+				Message: "the " + component + " may not change the spec or labels of a node",
+			},
+			{
+				Expression: "variables.annotations.all(k, k in variables.keys || (k in variables.oldAnnotations && variables.oldAnnotations[k] == variables.annotations[k])) && " +
+					"variables.oldAnnotations.all(k, k in variables.keys || k in variables.annotations)",
+				Message: "the " + component + " may only change the " + strings.Join(keys, " and ") + " annotations of a node",
+			},
+		},
+	}
+}
+
+func (h *validatorPatcher) resetRequestPolicyName() string {
+	return h.name + "-reset-request"
+}
+
+// buildResetRequestPolicySpec only admits the reset requests of the operator's remediation,
+// since the health watcher resets the devices of a node on request.
+func buildResetRequestPolicySpec(operator string) admissionregistrationv1.ValidatingAdmissionPolicySpec {
+	failurePolicy := admissionregistrationv1.Fail
+	requestOf := func(object string) string {
+		return fmt.Sprintf("(has(%[1]s.metadata.annotations) && '%[2]s' in %[1]s.metadata.annotations ? %[1]s.metadata.annotations['%[2]s'] : '')",
+			object, npuhealth.ResetRequestAnnotationKey)
+	}
+
+	return admissionregistrationv1.ValidatingAdmissionPolicySpec{
+		FailurePolicy: &failurePolicy,
+		MatchConstraints: &admissionregistrationv1.MatchResources{
+			ResourceRules: []admissionregistrationv1.NamedRuleWithOperations{{
+				RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{""},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"nodes"},
+					},
+				},
+			}},
+		},
+		MatchConditions: []admissionregistrationv1.MatchCondition{{
+			Name:       "reset-requested",
+			Expression: fmt.Sprintf("%s != '' && (request.operation == 'CREATE' || %s != %s)", requestOf("object"), requestOf("object"), requestOf("oldObject")),
+		}},
+		Validations: []admissionregistrationv1.Validation{{
+			Expression: fmt.Sprintf("request.userInfo.username == '%s'", operator),
+			Message:    "only the operator may set the " + npuhealth.ResetRequestAnnotationKey + " annotation",
+		}},
+	}
+}
+
+// operatorUsername returns the user name of the operator's service account.
+func operatorUsername() (string, error) {
+	namespace, serviceAccount := os.Getenv(operatorNamespaceEnv), os.Getenv(operatorServiceAccountEnv)
+	if namespace == "" || serviceAccount == "" {
+		return "", fmt.Errorf("%s and %s must be set", operatorNamespaceEnv, operatorServiceAccountEnv)
+	}
+	return serviceAccountUsername(namespace, serviceAccount), nil
+}
+
+func serviceAccountUsername(namespace, name string) string {
+	return "system:serviceaccount:" + namespace + ":" + name
+}
+
+func (h *validatorPatcher) deleteAdmissionPolicy(ctx context.Context) error {
+	for _, name := range []string{h.name, h.resetRequestPolicyName()} {
+		if err := deleteIfKindAvailable(ctx, h.client, validatingAdmissionPolicyBindingGVK, name, ""); err != nil {
+			return err
+		}
+		if err := deleteIfKindAvailable(ctx, h.client, validatingAdmissionPolicyGVK, name, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
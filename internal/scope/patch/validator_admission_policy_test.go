@@ -0,0 +1,116 @@
+package patch
+
+import (
+	"context"
+	"os"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+)
+
+var _ = Describe("ValidatorAdmissionPolicy", func() {
+	var patcher *validatorPatcher
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		for env, value := range map[string]string{"OPERATOR_NAMESPACE": "rbln-system", "OPERATOR_SERVICE_ACCOUNT": "rbln-npu-operator"} {
+			previous, set := os.LookupEnv(env)
+			Expect(os.Setenv(env, value)).To(Succeed())
+			DeferCleanup(func() {
+				if set {
+					Expect(os.Setenv(env, previous)).To(Succeed())
+				} else {
+					Expect(os.Unsetenv(env)).To(Succeed())
+				}
+			})
+		}
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(validatingAdmissionPolicyGVK, meta.RESTScopeRoot)
+		mapper.Add(validatingAdmissionPolicyBindingGVK, meta.RESTScopeRoot)
+
+		patcher = &validatorPatcher{
+			client:        fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).Build(),
+			log:           logr.Discard(),
+			scheme:        scheme,
+			name:          "rbln-validator",
+			namespace:     "rbln-system",
+			healthMonitor: &rblnv1beta1.RBLNHealthMonitorSpec{Enabled: true},
+		}
+	})
+
+	It("should confine the node patches of the health watcher to its own node", func() {
+		ctx := context.Background()
+		Expect(patcher.handleAdmissionPolicy(ctx)).To(Succeed())
+		Expect(patcher.handleClusterRole(ctx)).To(Succeed())
+
+		policy := &admissionregistrationv1.ValidatingAdmissionPolicy{}
+		Expect(patcher.client.Get(ctx, types.NamespacedName{Name: "rbln-validator"}, policy)).To(Succeed())
+		Expect(policy.Spec.MatchConditions).To(ConsistOf(HaveField("Expression",
+			"request.userInfo.username == 'system:serviceaccount:rbln-system:rbln-validator'")))
+		Expect(policy.Spec.Validations).To(ContainElement(HaveField("Expression",
+			ContainSubstring("object.metadata.name in request.userInfo.extra['authentication.kubernetes.io/node-name']"))))
+
+		binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{}
+		Expect(patcher.client.Get(ctx, types.NamespacedName{Name: "rbln-validator"}, binding)).To(Succeed())
+		Expect(binding.Spec.PolicyName).To(Equal("rbln-validator"))
+		Expect(binding.Spec.ValidationActions).To(ConsistOf(admissionregistrationv1.Deny))
+
+		role := &rbacv1.ClusterRole{}
+		Expect(patcher.client.Get(ctx, types.NamespacedName{Name: "rbln-validator"}, role)).To(Succeed())
+		Expect(role.Rules).To(ContainElement(And(
+			HaveField("Resources", ConsistOf("nodes")),
+			HaveField("Verbs", ContainElement("patch")),
+		)))
+	})
+
+	It("should reserve the reset requests to the operator", func() {
+		ctx := context.Background()
+		Expect(patcher.handleAdmissionPolicy(ctx)).To(Succeed())
+
+		policy := &admissionregistrationv1.ValidatingAdmissionPolicy{}
+		Expect(patcher.client.Get(ctx, types.NamespacedName{Name: "rbln-validator-reset-request"}, policy)).To(Succeed())
+		Expect(policy.Spec.MatchConditions).To(ConsistOf(HaveField("Expression", ContainSubstring("rebellions.ai/npu.reset-request"))))
+		Expect(policy.Spec.Validations).To(ConsistOf(HaveField("Expression",
+			"request.userInfo.username == 'system:serviceaccount:rbln-system:rbln-npu-operator'")))
+
+		binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{}
+		Expect(patcher.client.Get(ctx, types.NamespacedName{Name: "rbln-validator-reset-request"}, binding)).To(Succeed())
+		Expect(binding.Spec.PolicyName).To(Equal(policy.Name))
+	})
+
+	It("should fail without the ValidatingAdmissionPolicy API", func() {
+		patcher.client = fake.NewClientBuilder().WithScheme(patcher.scheme).WithRESTMapper(meta.NewDefaultRESTMapper(nil)).Build()
+		Expect(patcher.handleAdmissionPolicy(context.Background())).To(MatchError(ContainSubstring("Kubernetes 1.30+")))
+	})
+
+	It("should remove the policy and the node patch permission when the health monitor is disabled", func() {
+		ctx := context.Background()
+		Expect(patcher.handleAdmissionPolicy(ctx)).To(Succeed())
+
+		patcher.healthMonitor = nil
+		Expect(patcher.handleAdmissionPolicy(ctx)).To(Succeed())
+		Expect(patcher.handleClusterRole(ctx)).To(Succeed())
+
+		policies := &admissionregistrationv1.ValidatingAdmissionPolicyList{}
+		Expect(patcher.client.List(ctx, policies)).To(Succeed())
+		Expect(policies.Items).To(BeEmpty())
+		bindings := &admissionregistrationv1.ValidatingAdmissionPolicyBindingList{}
+		Expect(patcher.client.List(ctx, bindings)).To(Succeed())
+		Expect(bindings.Items).To(BeEmpty())
+
+		role := &rbacv1.ClusterRole{}
+		Expect(patcher.client.Get(ctx, types.NamespacedName{Name: "rbln-validator"}, role)).To(Succeed())
+		Expect(role.Rules).To(ConsistOf(HaveField("Verbs", Not(ContainElement("patch")))))
+	})
+})
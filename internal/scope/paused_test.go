@@ -0,0 +1,137 @@
+package scope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/consts"
+)
+
+func TestScope(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scope Suite")
+}
+
+// writeRecorder records the writes instead of sending them, so a test can assert there were none.
+type writeRecorder struct {
+	client.Client
+	writes []string
+}
+
+func (c *writeRecorder) record(verb string, obj client.Object) {
+	c.writes = append(c.writes, verb+" "+obj.GetObjectKind().GroupVersionKind().Kind+" "+obj.GetName())
+}
+
+func (c *writeRecorder) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	c.record("create", obj)
+	return nil
+}
+
+func (c *writeRecorder) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	c.record("update", obj)
+	return nil
+}
+
+func (c *writeRecorder) Patch(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	c.record("patch", obj)
+	return nil
+}
+
+func (c *writeRecorder) Delete(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	c.record("delete", obj)
+	return nil
+}
+
+func (c *writeRecorder) DeleteAllOf(_ context.Context, obj client.Object, _ ...client.DeleteAllOfOption) error {
+	c.record("deleteAllOf", obj)
+	return nil
+}
+
+var _ = Describe("Paused RBLNClusterPolicy", func() {
+	var (
+		scheme        *runtime.Scheme
+		c             *writeRecorder
+		clusterPolicy *rblnv1beta1.RBLNClusterPolicy
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(rebellionsaiv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		clusterPolicy = &rblnv1beta1.RBLNClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", UID: "uid"},
+			Spec: rblnv1beta1.RBLNClusterPolicySpec{
+				Namespace:    "rbln-system",
+				BaseName:     "rbln",
+				WorkloadType: consts.RBLNWorkloadConfigContainer,
+				Paused:       true,
+				NodeTaint:    &rblnv1beta1.NodeTaintSpec{Enabled: true},
+			},
+		}
+		// a node that would be labeled and tainted if the policy were not paused
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+			Labels: map[string]string{
+				"feature.node.kubernetes.io/pci-1eff.present": "true",
+			},
+		}}
+		c = &writeRecorder{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterPolicy, node).Build()}
+	})
+
+	It("should neither label nor taint nodes nor patch components", func() {
+		s, err := NewRBLNClusterPolicyScope(context.Background(), c, logr.Discard(), scheme, clusterPolicy, "", "containerd")
+		Expect(err).NotTo(HaveOccurred())
+
+		nfdInstalled, rblnNodes, err := s.LabelRblnNodes()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nfdInstalled).To(BeTrue())
+		Expect(rblnNodes).To(Equal(1))
+
+		Expect(s.PatchComponents(context.Background())).To(Succeed())
+		Expect(c.writes).To(BeEmpty())
+	})
+
+	It("should not patch the driver", func() {
+		driver := &rebellionsaiv1alpha1.RBLNDriver{
+			ObjectMeta: metav1.ObjectMeta{Name: "rbln-driver", UID: "uid"},
+			Spec: rebellionsaiv1alpha1.RBLNDriverSpec{
+				Registry: "repo.rebellions.ai",
+				Image:    "rebellions/rbln-driver",
+				Version:  "3.0.0",
+			},
+		}
+		s, err := NewRBLNDriverScope(context.Background(), c, logr.Discard(), scheme, driver, clusterPolicy, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(s.PatchComponents(context.Background())).To(Succeed())
+		Expect(s.IsManaged()).To(BeFalse())
+		Expect(c.writes).To(BeEmpty())
+	})
+
+	It("should not patch the firmware inventory", func() {
+		firmware := &rebellionsaiv1alpha1.RBLNFirmware{
+			ObjectMeta: metav1.ObjectMeta{Name: "rbln-firmware", UID: "uid"},
+			Spec:       rebellionsaiv1alpha1.RBLNFirmwareSpec{Version: "1.2.0"},
+		}
+		s, err := NewRBLNFirmwareScope(context.Background(), c, logr.Discard(), scheme, firmware, clusterPolicy, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(s.PatchComponents(context.Background())).To(Succeed())
+		Expect(s.IsPaused()).To(BeTrue())
+		Expect(c.writes).To(BeEmpty())
+	})
+})
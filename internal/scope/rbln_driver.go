@@ -6,12 +6,18 @@ import (
 	"os"
 
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
 	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/imagemirror"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/nodetaint"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/proxy"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/registry"
 	"github.com/rebellions-sw/rbln-npu-operator/internal/scope/patch"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/trustedca"
 )
 
 type RBLNDriverScope struct {
@@ -23,6 +29,10 @@ type RBLNDriverScope struct {
 	singleton        *rebellionsaiv1alpha1.RBLNDriver
 	namespace        string
 	openshiftVersion string
+	// paused is set by the cluster policy to leave the driver resources untouched
+	paused bool
+	// images rewrites the images of the driver manager to their mirrors and records them
+	images *imagemirror.Client
 
 	patcher []patch.DriverPatcher
 }
@@ -35,6 +45,7 @@ func NewRBLNDriverScope(
 	driver *rebellionsaiv1alpha1.RBLNDriver,
 	clusterPolicy *rblnv1beta1.RBLNClusterPolicy,
 	openshiftVersion string,
+	registryClient *registry.Client,
 ) (*RBLNDriverScope, error) {
 	s := &RBLNDriverScope{
 		client:           client,
@@ -59,7 +70,49 @@ func NewRBLNDriverScope(
 		return nil, err
 	}
 
-	dmp, err := patch.NewDriverManagerPatcher(client, log, s.namespace, driver, scheme, s.openshiftVersion)
+	var mirrors *rblnv1beta1.ImageMirrorsSpec
+	var proxySpec *rblnv1beta1.ProxySpec
+	var trustedCAName string
+	var mergeImage trustedca.MergeImage
+	var nodeTaint bool
+	if clusterPolicy != nil {
+		s.paused = clusterPolicy.Spec.Paused
+		nodeTaint = clusterPolicy.Spec.IsNodeTaintEnabled()
+		mirrors = clusterPolicy.Spec.ImageMirrors
+		proxySpec = clusterPolicy.Spec.Proxy
+		if clusterPolicy.Spec.TrustedCA != nil {
+			// the cluster policy materializes the bundle
+			trustedCAName = trustedca.ConfigMapName(clusterPolicy.Spec.BaseName)
+			var err error
+			if mergeImage, err = trustedca.ValidatorMergeImage(&clusterPolicy.Spec.Validator); err != nil {
+				return nil, err
+			}
+		}
+	}
+	rewriter, err := imagemirror.Load(ctx, client, mirrors, openshiftVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image mirrors: %w", err)
+	}
+	s.images = imagemirror.NewClient(client, rewriter)
+
+	proxyConfig, err := proxy.Load(ctx, client, proxySpec, openshiftVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load proxy: %w", err)
+	}
+
+	var resolver patch.ImageResolver
+	if registryClient != nil && driver.Spec.IsImagePreflightEnabled() {
+		resolver = &registryImageResolver{
+			client:    client,
+			namespace: s.namespace,
+			rewriter:  rewriter,
+			registry:  registryClient,
+			trustedCA: trustedCAName,
+		}
+	}
+
+	workloads := nodetaint.NewClient(proxy.NewClient(trustedca.NewClient(s.images, s.namespace, trustedCAName, mergeImage), proxyConfig), nodeTaint)
+	dmp, err := patch.NewDriverManagerPatcher(workloads, log, s.namespace, driver, scheme, s.openshiftVersion, resolver)
 	if err != nil {
 		return s, err
 	}
@@ -69,16 +122,83 @@ func NewRBLNDriverScope(
 }
 
 func (s *RBLNDriverScope) PatchComponents(ctx context.Context) error {
+	if s.paused {
+		s.log.Info("RBLNClusterPolicy is paused. Skip patching driver", "name", s.singleton.Name)
+		return nil
+	}
 	for _, p := range s.patcher {
-		if p.IsEnabled() {
-			if err := p.Patch(ctx, s.singleton); err != nil {
-				return fmt.Errorf("failed to patch component: %v", err)
-			}
-		} else {
+		switch p.ManagementState() {
+		case rebellionsaiv1alpha1.ManagementStateRemoved:
 			if err := p.CleanUp(ctx, s.singleton); err != nil {
 				return fmt.Errorf("failed to clean up component: %v", err)
 			}
+		case rebellionsaiv1alpha1.ManagementStateUnmanaged:
+			s.log.Info("Driver is unmanaged. Leave its resources untouched", "name", s.singleton.Name)
+		default:
+			if err := p.Patch(ctx, s.singleton); err != nil {
+				return fmt.Errorf("failed to patch component: %v", err)
+			}
 		}
 	}
 	return nil
 }
+
+// IsManaged reports whether PatchComponents reconciles the driver resources.
+func (s *RBLNDriverScope) IsManaged() bool {
+	return !s.paused && s.singleton.Spec.GetManagementState() == rebellionsaiv1alpha1.ManagementStateManaged
+}
+
+func (s *RBLNDriverScope) ConditionReport(ctx context.Context) ([]metav1.Condition, error) {
+	var conds []metav1.Condition
+	for _, p := range s.patcher {
+		if !p.IsEnabled() || p.ManagementState() == rebellionsaiv1alpha1.ManagementStateRemoved {
+			continue
+		}
+		c, err := p.ConditionReport(ctx, s.singleton)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get condition report for %s: %w", p.ComponentName(), err)
+		}
+		conds = append(conds, c...)
+	}
+	return conds, nil
+}
+
+// Images returns the effective images of the driver manager workloads.
+func (s *RBLNDriverScope) Images() []rebellionsaiv1alpha1.ContainerImage {
+	recorded := s.images.Images()
+	images := make([]rebellionsaiv1alpha1.ContainerImage, 0, len(recorded))
+	for _, image := range recorded {
+		images = append(images, rebellionsaiv1alpha1.ContainerImage{
+			Workload:  image.Workload,
+			Container: image.Container,
+			Image:     image.Image,
+		})
+	}
+	return images
+}
+
+// registryImageResolver checks driver images against the registry they are pulled from,
+// i.e. after the image mirrors are applied, with the pull secrets of the driver pods and the
+// trusted CA bundle.
+type registryImageResolver struct {
+	client    client.Reader
+	namespace string
+	rewriter  *imagemirror.Rewriter
+	registry  *registry.Client
+	trustedCA string
+}
+
+func (r *registryImageResolver) Exists(ctx context.Context, image string, pullSecrets []string) (bool, error) {
+	bundle, err := trustedca.Bundle(ctx, r.client, r.namespace, r.trustedCA)
+	if err != nil {
+		return false, err
+	}
+	if err := r.registry.TrustBundle(bundle); err != nil {
+		return false, err
+	}
+	keychain, err := registry.LoadKeychain(ctx, r.client, r.namespace, pullSecrets)
+	if err != nil {
+		return false, err
+	}
+	return r.registry.Exists(ctx, r.rewriter.Rewrite(image), keychain)
+}
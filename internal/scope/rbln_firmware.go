@@ -0,0 +1,159 @@
+package scope
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/imagemirror"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/nodetaint"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/proxy"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/scope/patch"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/trustedca"
+)
+
+type RBLNFirmwareScope struct {
+	client client.Client
+
+	ctx              context.Context
+	log              logr.Logger
+	scheme           *runtime.Scheme
+	singleton        *rebellionsaiv1alpha1.RBLNFirmware
+	namespace        string
+	openshiftVersion string
+	// paused is set by the cluster policy to leave the firmware resources and nodes untouched
+	paused bool
+	// workloads rewrites the images of the firmware workloads to their mirrors, mounts the
+	// trusted CA bundle, injects the proxy and tolerates the NPU node taint
+	workloads client.Client
+
+	patcher []patch.FirmwarePatcher
+}
+
+func NewRBLNFirmwareScope(
+	ctx context.Context,
+	client client.Client,
+	log logr.Logger,
+	scheme *runtime.Scheme,
+	firmware *rebellionsaiv1alpha1.RBLNFirmware,
+	clusterPolicy *rblnv1beta1.RBLNClusterPolicy,
+	openshiftVersion string,
+) (*RBLNFirmwareScope, error) {
+	s := &RBLNFirmwareScope{
+		client:           client,
+		ctx:              ctx,
+		log:              log,
+		scheme:           scheme,
+		singleton:        firmware,
+		openshiftVersion: openshiftVersion,
+	}
+
+	if clusterPolicy != nil && clusterPolicy.Spec.Namespace != "" {
+		s.namespace = clusterPolicy.Spec.Namespace
+	} else {
+		s.namespace = os.Getenv("OPERATOR_NAMESPACE")
+	}
+	if s.namespace == "" {
+		err := fmt.Errorf("namespace is not configured. Set OPERATOR_NAMESPACE env variable or namespace spec")
+		s.log.Error(err, "namespace configuration error")
+		return nil, err
+	}
+
+	var (
+		cpSpec        *rblnv1beta1.RBLNClusterPolicySpec
+		mirrors       *rblnv1beta1.ImageMirrorsSpec
+		proxySpec     *rblnv1beta1.ProxySpec
+		trustedCAName string
+		mergeImage    trustedca.MergeImage
+		nodeTaint     bool
+	)
+	if clusterPolicy != nil {
+		s.paused = clusterPolicy.Spec.Paused
+		cpSpec = &clusterPolicy.Spec
+		mirrors = clusterPolicy.Spec.ImageMirrors
+		proxySpec = clusterPolicy.Spec.Proxy
+		nodeTaint = clusterPolicy.Spec.IsNodeTaintEnabled()
+		if clusterPolicy.Spec.TrustedCA != nil {
+			trustedCAName = trustedca.ConfigMapName(clusterPolicy.Spec.BaseName)
+			var err error
+			if mergeImage, err = trustedca.ValidatorMergeImage(&clusterPolicy.Spec.Validator); err != nil {
+				return nil, err
+			}
+		}
+	}
+	rewriter, err := imagemirror.Load(ctx, client, mirrors, openshiftVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image mirrors: %w", err)
+	}
+	proxyConfig, err := proxy.Load(ctx, client, proxySpec, openshiftVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load proxy: %w", err)
+	}
+	s.workloads = nodetaint.NewClient(proxy.NewClient(trustedca.NewClient(imagemirror.NewClient(client, rewriter), s.namespace, trustedCAName, mergeImage), proxyConfig), nodeTaint)
+
+	fip, err := patch.NewFirmwareInventoryPatcher(s.workloads, log, s.namespace, firmware, cpSpec, scheme, s.openshiftVersion)
+	if err != nil {
+		return s, err
+	}
+	s.patcher = append(s.patcher, fip)
+
+	return s, nil
+}
+
+// Namespace returns the namespace of the firmware inventory and flash jobs.
+func (s *RBLNFirmwareScope) Namespace() string {
+	return s.namespace
+}
+
+// WorkloadClient returns the client that rewrites workload images to their mirrors, mounts
+// the trusted CA bundle, injects the proxy and tolerates the NPU node taint.
+func (s *RBLNFirmwareScope) WorkloadClient() client.Client {
+	return s.workloads
+}
+
+// IsPaused reports whether the cluster policy is paused, so neither the inventory nor the
+// firmware of the nodes is changed.
+func (s *RBLNFirmwareScope) IsPaused() bool {
+	return s.paused
+}
+
+func (s *RBLNFirmwareScope) PatchComponents(ctx context.Context) error {
+	if s.paused {
+		s.log.Info("RBLNClusterPolicy is paused. Skip patching firmware", "name", s.singleton.Name)
+		return nil
+	}
+	for _, p := range s.patcher {
+		if p.IsEnabled() {
+			if err := p.Patch(ctx, s.singleton); err != nil {
+				return fmt.Errorf("failed to patch component: %v", err)
+			}
+		} else {
+			if err := p.CleanUp(ctx, s.singleton); err != nil {
+				return fmt.Errorf("failed to clean up component: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *RBLNFirmwareScope) ConditionReport(ctx context.Context) ([]metav1.Condition, error) {
+	var conds []metav1.Condition
+	for _, p := range s.patcher {
+		if !p.IsEnabled() {
+			continue
+		}
+		c, err := p.ConditionReport(ctx, s.singleton)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get condition report for %s: %w", p.ComponentName(), err)
+		}
+		conds = append(conds, c...)
+	}
+	return conds, nil
+}
@@ -0,0 +1,235 @@
+package trustedca
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+const (
+	// HashAnnotationKey holds the bundle hash on pod templates, so pods roll when the bundle changes
+	HashAnnotationKey = "rebellions.ai/npu.trusted-ca-hash"
+
+	// MergeContainerName is the init container appending the bundle to the system bundle of the
+	// validator image, so the operands keep trusting the public CAs
+	MergeContainerName = "trusted-ca-merge"
+
+	volumeName        = "trusted-ca"
+	mergedVolumeName  = "trusted-ca-merged"
+	sourceMountPath   = "/run/rbln/trusted-ca"
+	mergedMountPath   = "/run/rbln/trusted-ca-merged"
+	osReleaseLabelKey = "feature.node.kubernetes.io/system-os_release.ID"
+	debianBundlePath  = "/etc/ssl/certs/ca-certificates.crt"
+	rhelBundlePath    = "/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem"
+)
+
+// bundlePaths maps OS release IDs to the path of their system bundle.
+var bundlePaths = map[string]string{
+	"ubuntu": debianBundlePath,
+	"debian": debianBundlePath,
+	"rhel":   rhelBundlePath,
+	"rhcos":  rhelBundlePath,
+	"centos": rhelBundlePath,
+	"rocky":  rhelBundlePath,
+}
+
+// MergeImage is the image of the merge container.
+type MergeImage struct {
+	Image       string
+	PullPolicy  corev1.PullPolicy
+	PullSecrets []string
+}
+
+// ValidatorMergeImage returns the validator image as the merge image, since it has a shell and a
+// system bundle, unlike distroless operand images.
+func ValidatorMergeImage(validator *rblnv1beta1.ValidatorSpec) (MergeImage, error) {
+	image, err := validator.GetValidatorImage()
+	if err != nil {
+		return MergeImage{}, fmt.Errorf("trustedCA requires the validator image: %w", err)
+	}
+	pullPolicy := validator.ImagePullPolicy
+	if pullPolicy == "" {
+		pullPolicy = corev1.PullIfNotPresent
+	}
+	return MergeImage{Image: image, PullPolicy: pullPolicy, PullSecrets: validator.ImagePullSecrets}, nil
+}
+
+// NewClient returns a client mounting the bundle of ConfigMap name of namespace into the
+// workloads written through it, merged by a container running merge. An empty name mounts
+// nothing. The bundle is read on the first write and kept for the lifetime of the client, which
+// lives for one reconcile.
+func NewClient(c client.Client, namespace, name string, merge MergeImage) client.Client {
+	if name == "" {
+		return c
+	}
+	injector := &injector{reader: c, namespace: namespace, name: name, merge: merge}
+	return k8sutil.NewPodTemplateClient(c, injector.inject)
+}
+
+// Bundle returns the bundle of ConfigMap name of namespace, or "" until it is materialized.
+func Bundle(ctx context.Context, reader client.Reader, namespace, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cm); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return cm.Data[BundleKey], nil
+}
+
+type injector struct {
+	reader    client.Reader
+	namespace string
+	name      string
+	merge     MergeImage
+
+	resolved bool
+	hash     string
+}
+
+func (c *injector) inject(ctx context.Context, template *corev1.PodTemplateSpec) error {
+	hash, err := c.bundleHash(ctx)
+	if err != nil || hash == "" {
+		return err
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[HashAnnotationKey] = hash
+	InjectPodSpec(&template.Spec, c.name, c.merge)
+	return nil
+}
+
+// bundleHash returns the hash of the materialized bundle, or "" until it is materialized.
+func (c *injector) bundleHash(ctx context.Context) (string, error) {
+	if c.resolved {
+		return c.hash, nil
+	}
+	bundle, err := Bundle(ctx, c.reader, c.namespace, c.name)
+	if err != nil {
+		return "", err
+	}
+	if bundle != "" {
+		sum := sha256.Sum256([]byte(bundle))
+		c.hash = hex.EncodeToString(sum[:])
+	}
+	c.resolved = true
+	return c.hash, nil
+}
+
+// InjectPodSpec adds an init container running merge, which appends the bundle of ConfigMap
+// name to its system bundle, and mounts the merged bundle over the system bundle of every
+// container. The path follows the OS the pod is pinned to; otherwise both
+// common paths are mounted. Volumes and mount paths already defined are kept.
+func InjectPodSpec(spec *corev1.PodSpec, name string, merge MergeImage) {
+	if len(spec.Containers) == 0 {
+		return
+	}
+	paths := []string{debianBundlePath, rhelBundlePath}
+	if path, ok := bundlePaths[spec.NodeSelector[osReleaseLabelKey]]; ok {
+		paths = []string{path}
+	}
+
+	volumes := map[string]bool{}
+	for _, v := range spec.Volumes {
+		volumes[v.Name] = true
+	}
+	if !volumes[volumeName] {
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: name},
+					Items:                []corev1.KeyToPath{{Key: BundleKey, Path: BundleKey}},
+				},
+			},
+		})
+	}
+	if !volumes[mergedVolumeName] {
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name:         mergedVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+	}
+
+	hasMerge := false
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			// the merge container reads the system bundle of its image
+			if containers[i].Name == MergeContainerName {
+				hasMerge = true
+				continue
+			}
+			containers[i].VolumeMounts = injectMounts(containers[i].VolumeMounts, paths)
+		}
+	}
+	if hasMerge {
+		return
+	}
+	// first, so the other init containers get the merged bundle as well
+	spec.InitContainers = append([]corev1.Container{*newMergeContainer(merge)}, spec.InitContainers...)
+	secrets := map[string]bool{}
+	for _, secret := range spec.ImagePullSecrets {
+		secrets[secret.Name] = true
+	}
+	for _, secret := range merge.PullSecrets {
+		if !secrets[secret] {
+			spec.ImagePullSecrets = append(spec.ImagePullSecrets, corev1.LocalObjectReference{Name: secret})
+		}
+	}
+}
+
+// newMergeContainer runs in the merge image, whose system bundle it extends.
+func newMergeContainer(image MergeImage) *corev1.Container {
+	script := fmt.Sprintf(
+		"for f in %s; do if [ -f \"$f\" ]; then cat \"$f\"; break; fi; done > %[2]s/%[3]s && cat %[4]s/%[3]s >> %[2]s/%[3]s",
+		strings.Join([]string{debianBundlePath, rhelBundlePath}, " "), mergedMountPath, BundleKey, sourceMountPath,
+	)
+	merge := k8sutil.NewContainerBuilder().
+		WithName(MergeContainerName).
+		WithCommands([]string{"sh", "-c"}).
+		WithArgs([]string{script}).
+		WithVolumeMounts([]corev1.VolumeMount{
+			{Name: volumeName, MountPath: sourceMountPath, ReadOnly: true},
+			{Name: mergedVolumeName, MountPath: mergedMountPath},
+		}).
+		Build()
+	merge.Image = image.Image
+	merge.ImagePullPolicy = image.PullPolicy
+	return merge
+}
+
+func injectMounts(base []corev1.VolumeMount, paths []string) []corev1.VolumeMount {
+	mounted := make(map[string]bool, len(base))
+	for _, m := range base {
+		mounted[m.MountPath] = true
+	}
+	// copy so the mounts shared by the builders are never mutated
+	merged := append([]corev1.VolumeMount{}, base...)
+	for _, path := range paths {
+		if mounted[path] {
+			continue
+		}
+		merged = append(merged, corev1.VolumeMount{
+			Name:      mergedVolumeName,
+			MountPath: path,
+			SubPath:   BundleKey,
+			ReadOnly:  true,
+		})
+	}
+	return merged
+}
@@ -0,0 +1,114 @@
+// Package trustedca materializes the trusted CA bundle in the operator namespace and mounts it
+// into the operand containers, so operands trust registries and mirrors signed by an internal CA.
+package trustedca
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	k8sutil "github.com/rebellions-sw/rbln-npu-operator/internal/utils/k8s"
+)
+
+const (
+	// BundleKey is the key of the bundle in the materialized ConfigMap, as injected by OpenShift
+	BundleKey = "ca-bundle.crt"
+	// LabelKey marks the materialized ConfigMap, so controllers watch its changes
+	LabelKey = "rebellions.ai/npu.trusted-ca"
+
+	openshiftInjectLabelKey = "config.openshift.io/inject-trusted-cabundle"
+)
+
+// ConfigMapName returns the name of the materialized ConfigMap of the components of baseName.
+func ConfigMapName(baseName string) string {
+	return baseName + "-trusted-ca"
+}
+
+// IsBundle reports whether obj is a materialized bundle ConfigMap.
+func IsBundle(obj client.Object) bool {
+	return obj.GetLabels()[LabelKey] == "true"
+}
+
+// Sync materializes the bundle of spec as ConfigMap name of namespace. A ConfigMap reference is
+// copied; on OpenShift without a reference the cluster network operator injects the cluster
+// bundle into the labeled ConfigMap. A nil spec removes the ConfigMap.
+func Sync(ctx context.Context, c client.Client, owner metav1.Object, scheme *runtime.Scheme, spec *rblnv1beta1.TrustedCASpec, namespace, name, openshiftVersion string) error {
+	if spec == nil {
+		return CleanUp(ctx, c, namespace, name)
+	}
+
+	labels := map[string]string{LabelKey: "true"}
+	var data map[string]string
+	switch {
+	case spec.ConfigMap != "":
+		bundle, err := sourceBundle(ctx, c, spec, namespace)
+		if err != nil {
+			return err
+		}
+		data = map[string]string{BundleKey: bundle}
+	case openshiftVersion != "":
+		labels[openshiftInjectLabelKey] = "true"
+	default:
+		return fmt.Errorf("trustedCA requires a configMap outside OpenShift")
+	}
+
+	builder := k8sutil.NewConfigMapBuilder(name, namespace)
+	cm := builder.Build()
+	_, err := controllerutil.CreateOrPatch(ctx, c, cm, func() error {
+		// keep the bundle injected by OpenShift
+		if data == nil {
+			data = cm.Data
+		}
+		cm = builder.
+			WithLabels(labels).
+			WithData(data).
+			WithOwner(owner, scheme).
+			Build()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile trusted CA ConfigMap %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// CleanUp removes the materialized ConfigMap.
+func CleanUp(ctx context.Context, c client.Client, namespace, name string) error {
+	if err := c.Delete(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}); err != nil && !kapierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func sourceBundle(ctx context.Context, c client.Reader, spec *rblnv1beta1.TrustedCASpec, namespace string) (string, error) {
+	if spec.Namespace != "" {
+		namespace = spec.Namespace
+	}
+	key := spec.Key
+	if key == "" {
+		key = BundleKey
+	}
+
+	source := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: spec.ConfigMap, Namespace: namespace}, source); err != nil {
+		return "", fmt.Errorf("failed to get trusted CA ConfigMap %s/%s: %w", namespace, spec.ConfigMap, err)
+	}
+	bundle, ok := source.Data[key]
+	if !ok || bundle == "" {
+		return "", fmt.Errorf("trusted CA ConfigMap %s/%s has no %s", namespace, spec.ConfigMap, key)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,225 @@
+package trustedca_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rblnv1beta1 "github.com/rebellions-sw/rbln-npu-operator/api/v1beta1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/trustedca"
+)
+
+func TestTrustedCA(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Trusted CA Suite")
+}
+
+var _ = Describe("TrustedCA", func() {
+	const (
+		namespace = "rbln-system"
+		name      = "rbln-trusted-ca"
+	)
+
+	var (
+		scheme *runtime.Scheme
+		owner  *rblnv1beta1.RBLNClusterPolicy
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(rblnv1beta1.AddToScheme(scheme)).To(Succeed())
+		owner = &rblnv1beta1.RBLNClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", UID: "uid"}}
+	})
+
+	getBundle := func(c client.Client) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, cm)).To(Succeed())
+		return cm
+	}
+
+	Describe("Sync", func() {
+		It("should copy the referenced bundle", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "corp-ca", Namespace: "certs"},
+				Data:       map[string]string{"ca.pem": "PEM"},
+			}).Build()
+			spec := &rblnv1beta1.TrustedCASpec{ConfigMap: "corp-ca", Namespace: "certs", Key: "ca.pem"}
+			Expect(trustedca.Sync(context.Background(), c, owner, scheme, spec, namespace, name, "")).To(Succeed())
+
+			cm := getBundle(c)
+			Expect(cm.Data).To(Equal(map[string]string{trustedca.BundleKey: "PEM"}))
+			Expect(cm.Labels).To(Equal(map[string]string{trustedca.LabelKey: "true"}))
+			Expect(cm.OwnerReferences).To(HaveLen(1))
+		})
+
+		It("should fail when the referenced bundle is missing", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).Build()
+			spec := &rblnv1beta1.TrustedCASpec{ConfigMap: "corp-ca"}
+			Expect(trustedca.Sync(context.Background(), c, owner, scheme, spec, namespace, name, "")).NotTo(Succeed())
+		})
+
+		It("should label the ConfigMap for injection on OpenShift and keep the injected bundle", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).Build()
+			spec := &rblnv1beta1.TrustedCASpec{}
+			Expect(trustedca.Sync(context.Background(), c, owner, scheme, spec, namespace, name, "4.14")).To(Succeed())
+			cm := getBundle(c)
+			Expect(cm.Labels).To(HaveKeyWithValue("config.openshift.io/inject-trusted-cabundle", "true"))
+
+			cm.Data = map[string]string{trustedca.BundleKey: "INJECTED"}
+			Expect(c.Update(context.Background(), cm)).To(Succeed())
+			Expect(trustedca.Sync(context.Background(), c, owner, scheme, spec, namespace, name, "4.14")).To(Succeed())
+			Expect(getBundle(c).Data).To(HaveKeyWithValue(trustedca.BundleKey, "INJECTED"))
+		})
+
+		It("should require a ConfigMap outside OpenShift", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).Build()
+			Expect(trustedca.Sync(context.Background(), c, owner, scheme, &rblnv1beta1.TrustedCASpec{}, namespace, name, "")).NotTo(Succeed())
+		})
+
+		It("should remove the ConfigMap when unset", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			}).Build()
+			Expect(trustedca.Sync(context.Background(), c, owner, scheme, nil, namespace, name, "")).To(Succeed())
+			err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, &corev1.ConfigMap{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Client", func() {
+		mergeImage := trustedca.MergeImage{
+			Image:       "rebellions/rbln-validator:1.0.0",
+			PullPolicy:  corev1.PullIfNotPresent,
+			PullSecrets: []string{"validator-pull-secret"},
+		}
+		var base client.Client
+
+		newDaemonSet := func(nodeSelector map[string]string) *appsv1.DaemonSet {
+			ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "rbln-driver", Namespace: namespace}}
+			ds.Spec.Template.Spec = corev1.PodSpec{
+				NodeSelector:   nodeSelector,
+				InitContainers: []corev1.Container{{Name: "init"}},
+				Containers:     []corev1.Container{{Name: "main", Image: "rbln-driver:3.0.0"}},
+			}
+			return ds
+		}
+
+		mountPaths := func(container corev1.Container) []string {
+			var paths []string
+			for _, m := range container.VolumeMounts {
+				paths = append(paths, m.MountPath)
+			}
+			return paths
+		}
+
+		BeforeEach(func() {
+			base = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Data:       map[string]string{trustedca.BundleKey: "PEM"},
+			}).Build()
+		})
+
+		It("should merge the bundle into the system bundle at the path of the pinned OS", func() {
+			c := trustedca.NewClient(base, namespace, name, mergeImage)
+			ds := newDaemonSet(map[string]string{"feature.node.kubernetes.io/system-os_release.ID": "ubuntu"})
+			Expect(c.Create(context.Background(), ds)).To(Succeed())
+
+			spec := ds.Spec.Template.Spec
+			Expect(spec.Volumes).To(HaveLen(2))
+			Expect(spec.Volumes[0].ConfigMap.Name).To(Equal(name))
+			Expect(spec.Volumes[1].EmptyDir).NotTo(BeNil())
+
+			Expect(spec.InitContainers).To(HaveLen(2))
+			merge := spec.InitContainers[0]
+			Expect(merge.Name).To(Equal(trustedca.MergeContainerName))
+			Expect(merge.Image).To(Equal(mergeImage.Image))
+			Expect(spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "validator-pull-secret"}))
+			Expect(mountPaths(merge)).NotTo(ContainElement("/etc/ssl/certs/ca-certificates.crt"))
+
+			for _, container := range []corev1.Container{spec.InitContainers[1], spec.Containers[0]} {
+				Expect(container.VolumeMounts).To(ConsistOf(corev1.VolumeMount{
+					Name:      spec.Volumes[1].Name,
+					MountPath: "/etc/ssl/certs/ca-certificates.crt",
+					SubPath:   trustedca.BundleKey,
+					ReadOnly:  true,
+				}))
+			}
+			Expect(ds.Spec.Template.Annotations).To(HaveKey(trustedca.HashAnnotationKey))
+		})
+
+		It("should read the bundle once per client", func() {
+			c := trustedca.NewClient(base, namespace, name, mergeImage)
+			Expect(c.Create(context.Background(), newDaemonSet(nil))).To(Succeed())
+
+			Expect(base.Delete(context.Background(), &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			})).To(Succeed())
+			ds := newDaemonSet(nil)
+			ds.Name = "rbln-validator"
+			Expect(c.Create(context.Background(), ds)).To(Succeed())
+			Expect(ds.Spec.Template.Annotations).To(HaveKey(trustedca.HashAnnotationKey))
+		})
+
+		It("should mount both common paths for unpinned pods", func() {
+			c := trustedca.NewClient(base, namespace, name, mergeImage)
+			ds := newDaemonSet(nil)
+			Expect(c.Create(context.Background(), ds)).To(Succeed())
+			Expect(mountPaths(ds.Spec.Template.Spec.Containers[0])).To(ConsistOf(
+				"/etc/ssl/certs/ca-certificates.crt",
+				"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem",
+			))
+		})
+
+		It("should roll pods when the bundle changes", func() {
+			c := trustedca.NewClient(base, namespace, name, mergeImage)
+			ds := newDaemonSet(nil)
+			Expect(c.Create(context.Background(), ds)).To(Succeed())
+			hash := ds.Spec.Template.Annotations[trustedca.HashAnnotationKey]
+
+			cm := &corev1.ConfigMap{}
+			Expect(base.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, cm)).To(Succeed())
+			cm.Data[trustedca.BundleKey] = "ROTATED"
+			Expect(base.Update(context.Background(), cm)).To(Succeed())
+
+			// a new reconcile
+			c = trustedca.NewClient(base, namespace, name, mergeImage)
+			_, err := controllerutil.CreateOrPatch(context.Background(), c, ds, func() error {
+				ds.Spec.Template = newDaemonSet(nil).Spec.Template
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ds.Spec.Template.Annotations[trustedca.HashAnnotationKey]).NotTo(Equal(hash))
+			Expect(ds.Spec.Template.Spec.Volumes).To(HaveLen(2))
+			Expect(ds.Spec.Template.Spec.InitContainers).To(HaveLen(2))
+		})
+
+		It("should leave the workloads untouched when disabled", func() {
+			c := trustedca.NewClient(base, namespace, "", mergeImage)
+			ds := newDaemonSet(nil)
+			Expect(c.Create(context.Background(), ds)).To(Succeed())
+			Expect(ds.Spec.Template.Spec.Volumes).To(BeEmpty())
+			Expect(ds.Spec.Template.Annotations).NotTo(HaveKey(trustedca.HashAnnotationKey))
+		})
+
+		It("should not change the template of existing Jobs", func() {
+			c := trustedca.NewClient(base, namespace, name, mergeImage)
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "flash", Namespace: namespace}}
+			Expect(base.Create(context.Background(), job)).To(Succeed())
+			Expect(c.Update(context.Background(), job)).To(Succeed())
+			Expect(job.Spec.Template.Spec.Volumes).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,53 @@
+package usage
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Ledger keeps the allocations of deleted pods until every report sampled them, since the
+// pods are gone from the cluster by the time of the next sample.
+type Ledger struct {
+	mu    sync.Mutex
+	ended map[string]Allocation
+}
+
+// NewLedger returns an empty ledger.
+func NewLedger() *Ledger {
+	return &Ledger{ended: map[string]Allocation{}}
+}
+
+// Record keeps the allocation of a pod deleted at deletedAt, if it held NPU resources.
+func (l *Ledger) Record(pod *corev1.Pod, deletedAt time.Time) {
+	allocation, ok := EndedAllocation(pod, deletedAt)
+	if !ok {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ended[allocation.key()] = allocation
+}
+
+// Ended returns the allocations of the deleted pods.
+func (l *Ledger) Ended() []Allocation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	allocations := make([]Allocation, 0, len(l.ended))
+	for _, allocation := range l.ended {
+		allocations = append(allocations, allocation)
+	}
+	return allocations
+}
+
+// Prune forgets the allocations that ended before t.
+func (l *Ledger) Prune(t time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, allocation := range l.ended {
+		if allocation.End.Before(t) {
+			delete(l.ended, key)
+		}
+	}
+}
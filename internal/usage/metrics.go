@@ -0,0 +1,98 @@
+package usage
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	podAllocated = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rbln_npu_pod_allocated",
+		Help: "Number of NPU devices allocated to a pod.",
+	}, []string{"namespace", "pod", "node", "resource"})
+	namespaceAllocated = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rbln_npu_namespace_allocated",
+		Help: "Number of NPU devices allocated to the pods of a namespace.",
+	}, []string{"namespace", "resource"})
+	nodeAllocated = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rbln_npu_node_allocated",
+		Help: "Number of NPU devices allocated to the pods of a node.",
+	}, []string{"node", "resource"})
+	nodeAllocatable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rbln_npu_node_allocatable",
+		Help: "Number of NPU devices of a node allocatable to pods.",
+	}, []string{"node", "resource"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(podAllocated, namespaceAllocated, nodeAllocated, nodeAllocatable)
+}
+
+// series is the label values and value of one sample of a gauge.
+type series struct {
+	labels []string
+	value  float64
+}
+
+// published holds the label values each gauge was last published with, so series that are gone
+// are deleted while the others are overwritten in place and never disappear from a scrape.
+var (
+	publishMu sync.Mutex
+	published = map[*prometheus.GaugeVec]map[string][]string{}
+)
+
+// Publish sets the usage metrics served by the operator to the snapshot and the allocatable
+// NPU resources of the nodes. Series of pods and nodes that are gone are removed.
+func Publish(snapshot *Snapshot, nodes []corev1.Node) {
+	publishMu.Lock()
+	defer publishMu.Unlock()
+
+	var pods []series
+	for _, allocation := range snapshot.Pods {
+		for name, value := range allocation.Resources {
+			pods = append(pods, series{[]string{allocation.Namespace, allocation.Name, allocation.NodeName, name}, float64(value)})
+		}
+	}
+	publish(podAllocated, pods)
+	publish(namespaceAllocated, sums(snapshot.ByNamespace()))
+	publish(nodeAllocated, sums(snapshot.ByNode()))
+
+	var allocatable []series
+	for i := range nodes {
+		for name, quantity := range nodes[i].Status.Allocatable {
+			if strings.HasPrefix(string(name), ResourcePrefix) {
+				allocatable = append(allocatable, series{[]string{nodes[i].Name, string(name)}, float64(quantity.Value())})
+			}
+		}
+	}
+	publish(nodeAllocatable, allocatable)
+}
+
+func sums(sums map[string]map[string]int64) []series {
+	var list []series
+	for key, resources := range sums {
+		for name, value := range resources {
+			list = append(list, series{[]string{key, name}, float64(value)})
+		}
+	}
+	return list
+}
+
+// publish sets the series of gauge and deletes those of the previous publish that are gone.
+func publish(gauge *prometheus.GaugeVec, list []series) {
+	current := make(map[string][]string, len(list))
+	for _, s := range list {
+		gauge.WithLabelValues(s.labels...).Set(s.value)
+		current[strings.Join(s.labels, "\x00")] = s.labels
+	}
+	for key, labels := range published[gauge] {
+		if _, ok := current[key]; !ok {
+			gauge.DeleteLabelValues(labels...)
+		}
+	}
+	published[gauge] = current
+}
@@ -0,0 +1,354 @@
+// Package usage accounts the NPU resources allocated to pods per namespace, pod and node.
+// Allocations are read from the pod specs: the device plugin allocates exactly the
+// rebellions.ai/* resources a pod requests once it is bound to a node.
+package usage
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+)
+
+// ResourcePrefix is the prefix of the extended resources advertised by the device plugins.
+const ResourcePrefix = "rebellions.ai/"
+
+// Allocation is the NPU resources allocated to a pod from Start until End, which is zero while
+// the pod holds them.
+type Allocation struct {
+	Namespace string
+	Name      string
+	NodeName  string
+	Resources map[string]int64
+	Start     time.Time
+	End       time.Time
+}
+
+func (a Allocation) key() string {
+	return a.Namespace + "/" + a.Name + "@" + a.Start.UTC().Format(time.RFC3339)
+}
+
+// Snapshot is the NPU resources allocated to the pods of the cluster at a point in time, and the
+// allocations of the pods that terminated since.
+type Snapshot struct {
+	Pods  []Allocation
+	Ended []Allocation
+}
+
+// NewSnapshot returns the allocations of the pods holding NPU resources and of the terminated
+// pods that held them.
+func NewSnapshot(pods []corev1.Pod) *Snapshot {
+	s := &Snapshot{}
+	for i := range pods {
+		if allocation, ok := PodAllocation(&pods[i]); ok {
+			s.Pods = append(s.Pods, allocation)
+		} else if allocation, ok := EndedAllocation(&pods[i], time.Time{}); ok {
+			s.Ended = append(s.Ended, allocation)
+		}
+	}
+	sort.Slice(s.Pods, func(i, j int) bool {
+		if s.Pods[i].Namespace != s.Pods[j].Namespace {
+			return s.Pods[i].Namespace < s.Pods[j].Namespace
+		}
+		return s.Pods[i].Name < s.Pods[j].Name
+	})
+	return s
+}
+
+// PodAllocation returns the NPU resources allocated to a pod, and false if it holds none.
+// Pods hold their resources from the time they are bound to a node until they terminate.
+func PodAllocation(pod *corev1.Pod) (Allocation, bool) {
+	if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return Allocation{}, false
+	}
+	resources := PodResources(&pod.Spec)
+	if len(resources) == 0 {
+		return Allocation{}, false
+	}
+	return Allocation{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		NodeName:  pod.Spec.NodeName,
+		Resources: resources,
+		Start:     podStart(pod),
+	}, true
+}
+
+// EndedAllocation returns the NPU resources a terminated or deleted pod held, and false if it
+// held none. A pod deleted while running held them until deletedAt.
+func EndedAllocation(pod *corev1.Pod, deletedAt time.Time) (Allocation, bool) {
+	if pod.Spec.NodeName == "" {
+		return Allocation{}, false
+	}
+	end, terminated := podEnd(pod)
+	if !terminated {
+		if deletedAt.IsZero() {
+			return Allocation{}, false
+		}
+		end = deletedAt
+	}
+	resources := PodResources(&pod.Spec)
+	if len(resources) == 0 {
+		return Allocation{}, false
+	}
+	return Allocation{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		NodeName:  pod.Spec.NodeName,
+		Resources: resources,
+		Start:     podStart(pod),
+		End:       end,
+	}, true
+}
+
+// podStart returns when the pod was bound to its node, which allocates its devices.
+func podStart(pod *corev1.Pod) time.Time {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionTrue && !cond.LastTransitionTime.IsZero() {
+			return cond.LastTransitionTime.Time
+		}
+	}
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime.Time
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// podEnd returns when the last container of a terminated pod finished, and false while it runs.
+func podEnd(pod *corev1.Pod) (time.Time, bool) {
+	if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+		return time.Time{}, false
+	}
+	var end time.Time
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range statuses {
+			if t := status.State.Terminated; t != nil && t.FinishedAt.After(end) {
+				end = t.FinishedAt.Time
+			}
+		}
+	}
+	if end.IsZero() {
+		for _, cond := range pod.Status.Conditions {
+			if cond.LastTransitionTime.After(end) {
+				end = cond.LastTransitionTime.Time
+			}
+		}
+	}
+	return end, true
+}
+
+// AddEnded adds the allocations of pods that terminated and are gone, skipping those the
+// snapshot already has.
+func (s *Snapshot) AddEnded(allocations []Allocation) {
+	known := make(map[string]bool, len(s.Pods)+len(s.Ended))
+	for _, list := range [][]Allocation{s.Pods, s.Ended} {
+		for _, allocation := range list {
+			known[allocation.key()] = true
+		}
+	}
+	for _, allocation := range allocations {
+		if !known[allocation.key()] {
+			known[allocation.key()] = true
+			s.Ended = append(s.Ended, allocation)
+		}
+	}
+}
+
+// PodResources returns the NPU resources requested by a pod spec, computed like the
+// scheduler does: the sum of the containers and sidecars, or the largest init container.
+func PodResources(spec *corev1.PodSpec) map[string]int64 {
+	resources := map[string]int64{}
+	for i := range spec.Containers {
+		for name, value := range containerResources(&spec.Containers[i]) {
+			resources[name] += value
+		}
+	}
+	initResources := map[string]int64{}
+	for i := range spec.InitContainers {
+		container := &spec.InitContainers[i]
+		for name, value := range containerResources(container) {
+			if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+				resources[name] += value
+			} else if value > initResources[name] {
+				initResources[name] = value
+			}
+		}
+	}
+	for name, value := range initResources {
+		if value > resources[name] {
+			resources[name] = value
+		}
+	}
+	return resources
+}
+
+func containerResources(container *corev1.Container) map[string]int64 {
+	resources := map[string]int64{}
+	// extended resources cannot be overcommitted, so the limit equals the request if both are set
+	for _, list := range []corev1.ResourceList{container.Resources.Requests, container.Resources.Limits} {
+		for name, quantity := range list {
+			if strings.HasPrefix(string(name), ResourcePrefix) && quantity.Value() > 0 {
+				resources[string(name)] = quantity.Value()
+			}
+		}
+	}
+	return resources
+}
+
+// ByNamespace returns the NPU resources allocated per namespace.
+func (s *Snapshot) ByNamespace() map[string]map[string]int64 {
+	return s.sum(func(a Allocation) string { return a.Namespace })
+}
+
+// ByNode returns the NPU resources allocated per node.
+func (s *Snapshot) ByNode() map[string]map[string]int64 {
+	return s.sum(func(a Allocation) string { return a.NodeName })
+}
+
+func (s *Snapshot) sum(key func(Allocation) string) map[string]map[string]int64 {
+	sums := map[string]map[string]int64{}
+	for _, allocation := range s.Pods {
+		k := key(allocation)
+		if sums[k] == nil {
+			sums[k] = map[string]int64{}
+		}
+		for name, value := range allocation.Resources {
+			sums[k][name] += value
+		}
+	}
+	return sums
+}
+
+// filter returns the allocations of pods in the namespaces, or all of them if namespaces is empty.
+func (s *Snapshot) filter(namespaces []string) *Snapshot {
+	if len(namespaces) == 0 {
+		return s
+	}
+	selected := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		selected[namespace] = true
+	}
+	filtered := &Snapshot{}
+	for _, allocation := range s.Pods {
+		if selected[allocation.Namespace] {
+			filtered.Pods = append(filtered.Pods, allocation)
+		}
+	}
+	for _, allocation := range s.Ended {
+		if selected[allocation.Namespace] {
+			filtered.Ended = append(filtered.Ended, allocation)
+		}
+	}
+	return filtered
+}
+
+// Sample records the snapshot taken at now in the status of the report. The usage of each
+// namespace is accumulated from the time each pod held its resources since the previous
+// sample, so pods that started and terminated between two samples are accounted as well.
+func Sample(report *rebellionsaiv1alpha1.RBLNUsageReport, snapshot *Snapshot, now metav1.Time) {
+	status := &report.Status
+	snapshot = snapshot.filter(report.Spec.Namespaces)
+
+	// the period starts with the first sample
+	since := now.Time
+	if status.LastSampleTime != nil && status.LastSampleTime.Before(&now) {
+		since = status.LastSampleTime.Time
+	}
+	if status.PeriodStart == nil {
+		status.PeriodStart = &now
+	}
+	status.LastSampleTime = &now
+
+	namespaces := map[string]map[string]*rebellionsaiv1alpha1.ResourceUsage{}
+	resourceUsage := func(namespace, name string) *rebellionsaiv1alpha1.ResourceUsage {
+		if namespaces[namespace] == nil {
+			namespaces[namespace] = map[string]*rebellionsaiv1alpha1.ResourceUsage{}
+		}
+		if namespaces[namespace][name] == nil {
+			namespaces[namespace][name] = &rebellionsaiv1alpha1.ResourceUsage{Resource: name}
+		}
+		return namespaces[namespace][name]
+	}
+	for _, ns := range status.Namespaces {
+		for _, r := range ns.Resources {
+			resourceUsage(ns.Namespace, r.Resource).DeviceSeconds = r.DeviceSeconds
+		}
+	}
+	for _, list := range [][]Allocation{snapshot.Pods, snapshot.Ended} {
+		for _, allocation := range list {
+			seconds := heldSeconds(allocation, since, now.Time)
+			if seconds == 0 {
+				continue
+			}
+			for name, value := range allocation.Resources {
+				resourceUsage(allocation.Namespace, name).DeviceSeconds += value * seconds
+			}
+		}
+	}
+	for namespace, resources := range snapshot.ByNamespace() {
+		for name, value := range resources {
+			resourceUsage(namespace, name).Allocated = value
+		}
+	}
+
+	status.Namespaces = make([]rebellionsaiv1alpha1.NamespaceUsage, 0, len(namespaces))
+	for namespace, resources := range namespaces {
+		usage := rebellionsaiv1alpha1.NamespaceUsage{Namespace: namespace}
+		for _, r := range resources {
+			usage.Resources = append(usage.Resources, *r)
+		}
+		sortResources(usage.Resources)
+		status.Namespaces = append(status.Namespaces, usage)
+	}
+	sort.Slice(status.Namespaces, func(i, j int) bool { return status.Namespaces[i].Namespace < status.Namespaces[j].Namespace })
+
+	status.Nodes = nil
+	for node, resources := range snapshot.ByNode() {
+		status.Nodes = append(status.Nodes, rebellionsaiv1alpha1.NodeUsage{NodeName: node, Resources: resourceUsages(resources)})
+	}
+	sort.Slice(status.Nodes, func(i, j int) bool { return status.Nodes[i].NodeName < status.Nodes[j].NodeName })
+
+	status.Pods = nil
+	if report.Spec.IncludePods {
+		for _, allocation := range snapshot.Pods {
+			status.Pods = append(status.Pods, rebellionsaiv1alpha1.PodUsage{
+				Namespace: allocation.Namespace,
+				Name:      allocation.Name,
+				NodeName:  allocation.NodeName,
+				Resources: resourceUsages(allocation.Resources),
+			})
+		}
+	}
+}
+
+// heldSeconds returns how long the allocation held its resources between since and now.
+func heldSeconds(allocation Allocation, since, now time.Time) int64 {
+	start, end := allocation.Start, allocation.End
+	if end.IsZero() || end.After(now) {
+		end = now
+	}
+	if start.Before(since) {
+		start = since
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return int64(end.Sub(start) / time.Second)
+}
+
+func resourceUsages(resources map[string]int64) []rebellionsaiv1alpha1.ResourceUsage {
+	usages := make([]rebellionsaiv1alpha1.ResourceUsage, 0, len(resources))
+	for name, value := range resources {
+		usages = append(usages, rebellionsaiv1alpha1.ResourceUsage{Resource: name, Allocated: value})
+	}
+	sortResources(usages)
+	return usages
+}
+
+func sortResources(usages []rebellionsaiv1alpha1.ResourceUsage) {
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Resource < usages[j].Resource })
+}
@@ -0,0 +1,221 @@
+package usage_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	rebellionsaiv1alpha1 "github.com/rebellions-sw/rbln-npu-operator/api/v1alpha1"
+	"github.com/rebellions-sw/rbln-npu-operator/internal/usage"
+)
+
+func TestUsage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Usage Suite")
+}
+
+func npuPod(namespace, name, node string, atoms int64, phase corev1.PodPhase) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name: "inference",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						"rebellions.ai/ATOM": *resource.NewQuantity(atoms, resource.DecimalSI),
+						"cpu":                resource.MustParse("4"),
+					},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+var _ = Describe("Snapshot", func() {
+	It("should only account pods bound to a node until they terminate", func() {
+		snapshot := usage.NewSnapshot([]corev1.Pod{
+			npuPod("team-a", "serve-1", "node-1", 2, corev1.PodRunning),
+			npuPod("team-a", "serve-2", "node-2", 1, corev1.PodRunning),
+			npuPod("team-b", "train", "node-1", 4, corev1.PodPending),
+			npuPod("team-b", "done", "node-1", 4, corev1.PodSucceeded),
+			npuPod("team-b", "unscheduled", "", 4, corev1.PodPending),
+			{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-c"}, Spec: corev1.PodSpec{NodeName: "node-1"}},
+		})
+
+		Expect(snapshot.Pods).To(HaveLen(3))
+		Expect(snapshot.ByNamespace()).To(Equal(map[string]map[string]int64{
+			"team-a": {"rebellions.ai/ATOM": 3},
+			"team-b": {"rebellions.ai/ATOM": 4},
+		}))
+		Expect(snapshot.ByNode()).To(Equal(map[string]map[string]int64{
+			"node-1": {"rebellions.ai/ATOM": 6},
+			"node-2": {"rebellions.ai/ATOM": 1},
+		}))
+	})
+
+	It("should count the largest init container and add sidecars", func() {
+		always := corev1.ContainerRestartPolicyAlways
+		atoms := func(n int64) corev1.ResourceRequirements {
+			return corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				"rebellions.ai/ATOM": *resource.NewQuantity(n, resource.DecimalSI),
+			}}
+		}
+		spec := &corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{Name: "warmup", Resources: atoms(4)},
+				{Name: "sidecar", Resources: atoms(1), RestartPolicy: &always},
+			},
+			Containers: []corev1.Container{{Name: "serve", Resources: atoms(2)}},
+		}
+		Expect(usage.PodResources(spec)).To(Equal(map[string]int64{"rebellions.ai/ATOM": 4}))
+
+		spec.Containers[0].Resources = atoms(4)
+		Expect(usage.PodResources(spec)).To(Equal(map[string]int64{"rebellions.ai/ATOM": 5}))
+	})
+
+	It("should publish the allocation as metrics", func() {
+		snapshot := usage.NewSnapshot([]corev1.Pod{npuPod("team-a", "serve-1", "node-1", 2, corev1.PodRunning)})
+		node := corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				"rebellions.ai/ATOM": resource.MustParse("8"),
+				"cpu":                resource.MustParse("32"),
+			}},
+		}
+		usage.Publish(snapshot, []corev1.Node{node})
+
+		values := map[string]float64{}
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				labels := ""
+				for _, label := range metric.GetLabel() {
+					labels += label.GetName() + "=" + label.GetValue() + ","
+				}
+				values[family.GetName()+"{"+labels+"}"] = metric.GetGauge().GetValue()
+			}
+		}
+		Expect(values).To(HaveKeyWithValue("rbln_npu_pod_allocated{namespace=team-a,node=node-1,pod=serve-1,resource=rebellions.ai/ATOM,}", 2.0))
+		Expect(values).To(HaveKeyWithValue("rbln_npu_namespace_allocated{namespace=team-a,resource=rebellions.ai/ATOM,}", 2.0))
+		Expect(values).To(HaveKeyWithValue("rbln_npu_node_allocated{node=node-1,resource=rebellions.ai/ATOM,}", 2.0))
+		Expect(values).To(HaveKeyWithValue("rbln_npu_node_allocatable{node=node-1,resource=rebellions.ai/ATOM,}", 8.0))
+
+		By("removing only the series of the pods that are gone")
+		usage.Publish(usage.NewSnapshot([]corev1.Pod{npuPod("team-a", "serve-2", "node-1", 1, corev1.PodRunning)}), []corev1.Node{node})
+		families, err = metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		pods := map[string]float64{}
+		for _, family := range families {
+			if family.GetName() != "rbln_npu_pod_allocated" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "pod" {
+						pods[label.GetValue()] = metric.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+		Expect(pods).To(Equal(map[string]float64{"serve-2": 1}))
+	})
+})
+
+var _ = Describe("Sample", func() {
+	var (
+		report *rebellionsaiv1alpha1.RBLNUsageReport
+		start  time.Time
+	)
+
+	BeforeEach(func() {
+		report = &rebellionsaiv1alpha1.RBLNUsageReport{ObjectMeta: metav1.ObjectMeta{Name: "usage"}}
+		start = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	It("should accumulate the device seconds of each namespace", func() {
+		usage.Sample(report, usage.NewSnapshot([]corev1.Pod{
+			npuPod("team-a", "serve-1", "node-1", 2, corev1.PodRunning),
+			npuPod("team-b", "train", "node-1", 4, corev1.PodRunning),
+		}), metav1.NewTime(start))
+		Expect(report.Status.PeriodStart.Time).To(Equal(start))
+		Expect(report.Status.Namespaces).To(Equal([]rebellionsaiv1alpha1.NamespaceUsage{
+			{Namespace: "team-a", Resources: []rebellionsaiv1alpha1.ResourceUsage{{Resource: "rebellions.ai/ATOM", Allocated: 2}}},
+			{Namespace: "team-b", Resources: []rebellionsaiv1alpha1.ResourceUsage{{Resource: "rebellions.ai/ATOM", Allocated: 4}}},
+		}))
+
+		// team-b finished training half way; its usage is kept for the period
+		train := npuPod("team-b", "train", "node-1", 4, corev1.PodSucceeded)
+		train.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  "inference",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(start.Add(30 * time.Minute))}},
+		}}
+		usage.Sample(report, usage.NewSnapshot([]corev1.Pod{
+			npuPod("team-a", "serve-1", "node-1", 2, corev1.PodRunning),
+			train,
+		}), metav1.NewTime(start.Add(time.Hour)))
+		Expect(report.Status.PeriodStart.Time).To(Equal(start))
+		Expect(report.Status.LastSampleTime.Time).To(Equal(start.Add(time.Hour)))
+		Expect(report.Status.Namespaces).To(Equal([]rebellionsaiv1alpha1.NamespaceUsage{
+			{Namespace: "team-a", Resources: []rebellionsaiv1alpha1.ResourceUsage{{Resource: "rebellions.ai/ATOM", Allocated: 2, DeviceSeconds: 7200}}},
+			{Namespace: "team-b", Resources: []rebellionsaiv1alpha1.ResourceUsage{{Resource: "rebellions.ai/ATOM", Allocated: 0, DeviceSeconds: 7200}}},
+		}))
+		Expect(report.Status.Nodes).To(Equal([]rebellionsaiv1alpha1.NodeUsage{
+			{NodeName: "node-1", Resources: []rebellionsaiv1alpha1.ResourceUsage{{Resource: "rebellions.ai/ATOM", Allocated: 2}}},
+		}))
+		Expect(report.Status.Pods).To(BeEmpty())
+	})
+
+	It("should account pods that started and were deleted between two samples", func() {
+		usage.Sample(report, usage.NewSnapshot(nil), metav1.NewTime(start))
+
+		job := npuPod("team-a", "short-job", "node-1", 2, corev1.PodRunning)
+		job.Status.Conditions = []corev1.PodCondition{{
+			Type:               corev1.PodScheduled,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(start.Add(10 * time.Minute)),
+		}}
+		ledger := usage.NewLedger()
+		ledger.Record(&job, start.Add(12*time.Minute))
+
+		snapshot := usage.NewSnapshot(nil)
+		snapshot.AddEnded(ledger.Ended())
+		usage.Sample(report, snapshot, metav1.NewTime(start.Add(15*time.Minute)))
+		Expect(report.Status.Namespaces).To(Equal([]rebellionsaiv1alpha1.NamespaceUsage{
+			{Namespace: "team-a", Resources: []rebellionsaiv1alpha1.ResourceUsage{{Resource: "rebellions.ai/ATOM", DeviceSeconds: 240}}},
+		}))
+
+		// sampling again does not count the pod twice
+		usage.Sample(report, snapshot, metav1.NewTime(start.Add(30*time.Minute)))
+		Expect(report.Status.Namespaces[0].Resources[0].DeviceSeconds).To(Equal(int64(240)))
+
+		ledger.Prune(start.Add(30 * time.Minute))
+		Expect(ledger.Ended()).To(BeEmpty())
+	})
+
+	It("should only report the selected namespaces and list pods if asked", func() {
+		report.Spec.Namespaces = []string{"team-a"}
+		report.Spec.IncludePods = true
+		usage.Sample(report, usage.NewSnapshot([]corev1.Pod{
+			npuPod("team-a", "serve-1", "node-1", 2, corev1.PodRunning),
+			npuPod("team-b", "train", "node-2", 4, corev1.PodRunning),
+		}), metav1.NewTime(start))
+
+		Expect(report.Status.Namespaces).To(HaveLen(1))
+		Expect(report.Status.Nodes).To(HaveLen(1))
+		Expect(report.Status.Pods).To(Equal([]rebellionsaiv1alpha1.PodUsage{{
+			Namespace: "team-a",
+			Name:      "serve-1",
+			NodeName:  "node-1",
+			Resources: []rebellionsaiv1alpha1.ResourceUsage{{Resource: "rebellions.ai/ATOM", Allocated: 2}},
+		}}))
+	})
+})
@@ -18,3 +18,13 @@ func (b *ConfigMapBuilder) WithData(data map[string]string) *ConfigMapBuilder {
 	b.obj.Data = data
 	return b
 }
+
+func (b *ConfigMapBuilder) WithLabels(labels map[string]string) *ConfigMapBuilder {
+	b.obj.Labels = labels
+	return b
+}
+
+func (b *ConfigMapBuilder) WithAnnotations(annotations map[string]string) *ConfigMapBuilder {
+	b.obj.Annotations = annotations
+	return b
+}
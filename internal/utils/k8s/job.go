@@ -0,0 +1,53 @@
+package k8sutil
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type JobBuilder struct {
+	*OwnableBuilder[batchv1.Job, *batchv1.Job]
+}
+
+func NewJobBuilder(name, namespace string) *JobBuilder {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: make(map[string]string),
+				},
+			},
+		},
+	}
+	return &JobBuilder{
+		OwnableBuilder: &OwnableBuilder[batchv1.Job, *batchv1.Job]{
+			Builder: NewBuilder[batchv1.Job](job),
+		},
+	}
+}
+
+func (b *JobBuilder) WithLabels(labels map[string]string) *JobBuilder {
+	b.obj.Labels = MergeMaps(b.obj.Labels, labels)
+	b.obj.Spec.Template.Labels = MergeMaps(b.obj.Spec.Template.Labels, labels)
+	return b
+}
+
+func (b *JobBuilder) WithBackoffLimit(limit int32) *JobBuilder {
+	b.obj.Spec.BackoffLimit = &limit
+	return b
+}
+
+func (b *JobBuilder) WithActiveDeadlineSeconds(seconds int64) *JobBuilder {
+	b.obj.Spec.ActiveDeadlineSeconds = &seconds
+	return b
+}
+
+func (b *JobBuilder) WithPodSpec(podSpec *corev1.PodSpec) *JobBuilder {
+	b.obj.Spec.Template.Spec = *podSpec
+	return b
+}
@@ -0,0 +1,32 @@
+package k8sutil
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NetworkPolicyBuilder struct {
+	*OwnableBuilder[networkingv1.NetworkPolicy, *networkingv1.NetworkPolicy]
+}
+
+func NewNetworkPolicyBuilder(name, namespace string) *NetworkPolicyBuilder {
+	return &NetworkPolicyBuilder{
+		OwnableBuilder: NewOwnableBuilder[networkingv1.NetworkPolicy](name, namespace),
+	}
+}
+
+func (b *NetworkPolicyBuilder) WithLabels(labels map[string]string) *NetworkPolicyBuilder {
+	b.obj.Labels = labels
+	return b
+}
+
+func (b *NetworkPolicyBuilder) WithPodSelector(matchLabels map[string]string) *NetworkPolicyBuilder {
+	b.obj.Spec.PodSelector = metav1.LabelSelector{MatchLabels: matchLabels}
+	return b
+}
+
+func (b *NetworkPolicyBuilder) WithIngressRules(rules ...networkingv1.NetworkPolicyIngressRule) *NetworkPolicyBuilder {
+	b.obj.Spec.Ingress = rules
+	b.obj.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	return b
+}
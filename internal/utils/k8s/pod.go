@@ -0,0 +1,35 @@
+package k8sutil
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodNodeNameField is the field of the node a pod is bound to, usable as a field selector.
+const PodNodeNameField = "spec.nodeName"
+
+// IsEvictable reports whether a drain evicts the pod: running pods that are neither
+// DaemonSet pods nor mirror pods of static pods.
+func IsEvictable(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+		return false
+	}
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPodReady reports whether the pod has the Ready condition.
+func IsPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -86,6 +86,21 @@ func (b *PodSpecBuilder) WithServiceAccountName(serviceAccountName string) *PodS
 	return b
 }
 
+func (b *PodSpecBuilder) WithAutomountServiceAccountToken(automount bool) *PodSpecBuilder {
+	b.obj.AutomountServiceAccountToken = &automount
+	return b
+}
+
+func (b *PodSpecBuilder) WithNodeName(nodeName string) *PodSpecBuilder {
+	b.obj.NodeName = nodeName
+	return b
+}
+
+func (b *PodSpecBuilder) WithRestartPolicy(policy corev1.RestartPolicy) *PodSpecBuilder {
+	b.obj.RestartPolicy = policy
+	return b
+}
+
 // MergeAffinity combines default and user-provided Affinity
 func MergeAffinity(defaultAffinity, userAffinity *corev1.Affinity) *corev1.Affinity {
 	if userAffinity == nil {
@@ -0,0 +1,42 @@
+package k8sutil
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+)
+
+type RuntimeClassBuilder struct {
+	*OwnableBuilder[nodev1.RuntimeClass, *nodev1.RuntimeClass]
+}
+
+func NewRuntimeClassBuilder(name string) *RuntimeClassBuilder {
+	return &RuntimeClassBuilder{
+		OwnableBuilder: NewOwnableBuilder[nodev1.RuntimeClass](name, ""),
+	}
+}
+
+func (b *RuntimeClassBuilder) WithLabels(labels map[string]string) *RuntimeClassBuilder {
+	b.obj.Labels = labels
+	return b
+}
+
+func (b *RuntimeClassBuilder) WithHandler(handler string) *RuntimeClassBuilder {
+	b.obj.Handler = handler
+	return b
+}
+
+func (b *RuntimeClassBuilder) WithNodeSelector(nodeSelector map[string]string) *RuntimeClassBuilder {
+	if b.obj.Scheduling == nil {
+		b.obj.Scheduling = &nodev1.Scheduling{}
+	}
+	b.obj.Scheduling.NodeSelector = nodeSelector
+	return b
+}
+
+func (b *RuntimeClassBuilder) WithTolerations(tolerations []corev1.Toleration) *RuntimeClassBuilder {
+	if b.obj.Scheduling == nil {
+		b.obj.Scheduling = &nodev1.Scheduling{}
+	}
+	b.obj.Scheduling.Tolerations = tolerations
+	return b
+}
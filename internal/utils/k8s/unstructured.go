@@ -0,0 +1,34 @@
+package k8sutil
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// UnstructuredBuilder builds objects whose Go types are not vendored by the operator,
+// e.g. Prometheus Operator or Grafana Operator custom resources.
+type UnstructuredBuilder struct {
+	*OwnableBuilder[unstructured.Unstructured, *unstructured.Unstructured]
+}
+
+func NewUnstructuredBuilder(gvk schema.GroupVersionKind, name, namespace string) *UnstructuredBuilder {
+	b := &UnstructuredBuilder{
+		OwnableBuilder: NewOwnableBuilder[unstructured.Unstructured](name, namespace),
+	}
+	b.obj.SetGroupVersionKind(gvk)
+	return b
+}
+
+func (b *UnstructuredBuilder) WithLabels(labels map[string]string) *UnstructuredBuilder {
+	b.obj.SetLabels(labels)
+	return b
+}
+
+// WithSpec replaces the spec of the object. The spec must only contain JSON compatible values.
+func (b *UnstructuredBuilder) WithSpec(spec map[string]interface{}) *UnstructuredBuilder {
+	if b.obj.Object == nil {
+		b.obj.Object = map[string]interface{}{}
+	}
+	b.obj.Object["spec"] = spec
+	return b
+}
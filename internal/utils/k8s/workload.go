@@ -0,0 +1,81 @@
+package k8sutil
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodTemplateOf returns the pod template of a workload, or nil if obj is no workload.
+func PodTemplateOf(obj client.Object) *corev1.PodTemplateSpec {
+	switch o := obj.(type) {
+	case *appsv1.DaemonSet:
+		return &o.Spec.Template
+	case *appsv1.Deployment:
+		return &o.Spec.Template
+	case *appsv1.StatefulSet:
+		return &o.Spec.Template
+	case *batchv1.Job:
+		return &o.Spec.Template
+	}
+	return nil
+}
+
+// PodTemplateMutator changes the pod template of a workload before it is written.
+type PodTemplateMutator func(ctx context.Context, template *corev1.PodTemplateSpec) error
+
+// PodTemplateClient applies a mutator to the pod templates of the workloads written through it,
+// so settings shared by every operand are added in one place instead of by each patcher.
+type PodTemplateClient struct {
+	client.Client
+	mutate PodTemplateMutator
+}
+
+// NewPodTemplateClient returns a client applying mutate to the written workloads. A nil mutate
+// leaves them unchanged.
+func NewPodTemplateClient(c client.Client, mutate PodTemplateMutator) *PodTemplateClient {
+	return &PodTemplateClient{
+		Client: c,
+		mutate: mutate,
+	}
+}
+
+func (c *PodTemplateClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.apply(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *PodTemplateClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.applyMutable(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *PodTemplateClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.applyMutable(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// applyMutable skips Jobs, whose pod template cannot change after creation.
+func (c *PodTemplateClient) applyMutable(ctx context.Context, obj client.Object) error {
+	if _, ok := obj.(*batchv1.Job); ok {
+		return nil
+	}
+	return c.apply(ctx, obj)
+}
+
+func (c *PodTemplateClient) apply(ctx context.Context, obj client.Object) error {
+	template := PodTemplateOf(obj)
+	if c.mutate == nil || template == nil {
+		return nil
+	}
+	return c.mutate(ctx, template)
+}